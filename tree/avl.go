@@ -2,6 +2,7 @@ package tree
 
 import (
 	"bytes"
+	"slices"
 
 	"golang.org/x/exp/constraints"
 )
@@ -10,13 +11,50 @@ import (
 // self-balancing binary search tree. In an AVL tree, the heights of the two
 // child subtrees of any node differ by at most one; if at any time they differ
 // by more than one, rebalancing is done to restore this property.
-type AVL[T constraints.Ordered] struct {
+type AVL[T any] struct {
 	root *avlNode[T]
+
+	// size is the count of values in the tree, kept up to date by the
+	// Insert and Delete methods.
+	size int
+
+	// order defines how the values in the tree are arranged.
+	order ordering[T]
 }
 
 // NewAVL returns an empty AVL tree ready to use.
-func NewAVL[T constraints.Ordered]() Tree[T] {
-	return &AVL[T]{}
+//
+// Options can include Descending to order the values largest first, and
+// IgnoreDuplicates(false) to keep every copy of equal values inserted.
+func NewAVL[T constraints.Ordered](opts ...treeOptionFunc) Tree[T] {
+	treeOpts := defaultOptions()
+	for _, opt := range opts {
+		opt(treeOpts)
+	}
+
+	return &AVL[T]{order: newOrdering[T](treeOpts)}
+}
+
+// NewAVLFunc returns an empty AVL tree ready to use, with its values ordered
+// by the given comparison function instead of their natural order. See
+// NewBSTFunc for the requirements on cmp.
+func NewAVLFunc[T any](cmp func(a, b T) int) Tree[T] {
+	return &AVL[T]{order: ordering[T]{cmp: cmp}}
+}
+
+// NewAVLFromSorted returns an AVL tree holding the given values, which must
+// be in ascending order, built directly in balanced shape in O(n) time
+// without any rotations.
+//
+// Repeated values are only stored once.
+func NewAVLFromSorted[T constraints.Ordered](vals []T) *AVL[T] {
+	vals = slices.Compact(slices.Clone(vals))
+	root, _ := avlNodeFromSorted(vals, nil)
+	return &AVL[T]{
+		root:  root,
+		size:  len(vals),
+		order: ordering[T]{}.resolve(),
+	}
 }
 
 // Root returns the root node of the tree.
@@ -26,25 +64,212 @@ func (t *AVL[T]) Root() BinaryTree[T] {
 
 // Insert inserts the node into the tree, growing as needed.
 func (t *AVL[T]) Insert(v T) bool {
-	if t.root == nil {
-		t.root = &avlNode[T]{
-			value: v,
-			bf:    0,
-			left:  nil,
-			right: nil,
+	return t.InsertErr(v) == nil
+}
+
+// InsertErr inserts the node into the tree, growing as needed. If the value
+// is already in the tree, and the tree does not keep duplicates, ErrDuplicate
+// is returned.
+func (t *AVL[T]) InsertErr(v T) error {
+	// An empty tree is handled by insert on the nil root returning the
+	// new node, and any rotations at the root return its replacement.
+	root, ok := t.root.insert(v, t.order.resolve())
+	if !ok {
+		return ErrDuplicate
+	}
+	t.root = root
+	t.size++
+
+	return nil
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+//
+// When adding many values, rather than inserting them one at a time with the
+// rotations that entails, they are sorted and merged with the existing values
+// and the tree is rebuilt in balanced shape in a single pass.
+func (t *AVL[T]) InsertAll(vals ...T) int {
+	// For a few values into a large tree, a few rotations are cheaper than
+	// rebuilding the whole tree.
+	if t.root != nil && len(vals)*t.root.Height() < t.size {
+		var n int
+		for _, v := range vals {
+			if t.Insert(v) {
+				n++
+			}
 		}
+		return n
+	}
 
-		return true
+	o := t.order.resolve()
+	sorted := slices.Clone(vals)
+	slices.SortFunc(sorted, o.compare)
+
+	existing := make([]T, 0, t.size)
+	InOrderInto(t.Root(), &existing)
+
+	// Merge the two, dropping any repeats unless the tree keeps them. Values
+	// already in the tree are taken first so they are the ones kept, or so
+	// that new copies come after them.
+	all := make([]T, 0, len(existing)+len(sorted))
+	var i, j int
+	for i < len(existing) || j < len(sorted) {
+		var v T
+		if j == len(sorted) || (i < len(existing) && o.compare(existing[i], sorted[j]) <= 0) {
+			v = existing[i]
+			i++
+		} else {
+			v = sorted[j]
+			j++
+		}
+		if !o.duplicates && len(all) > 0 && o.compare(all[len(all)-1], v) == 0 {
+			continue
+		}
+		all = append(all, v)
 	}
 
-	return t.root.Insert(v)
+	added := len(all) - t.size
+	t.root, _ = avlNodeFromSorted(all, nil)
+	t.size = len(all)
+	return added
+}
+
+// InsertSortedStream inserts the values read from the channel into the tree
+// until the channel is closed.
+//
+// It is intended for values arriving in the order of the tree, such as from a
+// sorted file. Inserting those one at a time keeps extending the right spine
+// and triggers a rotation almost every time. Instead, each run of in order
+// values following everything already in the tree is collected and appended
+// to the right of the tree in one step, after which the tree is rebuilt into
+// balanced shape. Values that break the run are inserted normally.
+func (t *AVL[T]) InsertSortedStream(ch <-chan T) {
+	var run []T
+	o := t.order.resolve()
+	last, ok := t.Max()
+
+	for v := range ch {
+		if !ok || o.compare(last, v) < 0 {
+			run = append(run, v)
+			last, ok = v, true
+			continue
+		}
+
+		t.appendSorted(run)
+		run = run[:0]
+		t.Insert(v)
+		last, ok = t.Max()
+	}
+
+	t.appendSorted(run)
+}
+
+// appendSorted adds the given in order values, all of which follow the
+// current last value, to the tree and restores its balance.
+func (t *AVL[T]) appendSorted(vals []T) {
+	if len(vals) == 0 {
+		return
+	}
+
+	// For short runs into a large tree, a few rotations are cheaper than
+	// rebuilding the whole tree.
+	if t.root != nil && len(vals)*t.root.Height() < t.size {
+		for _, v := range vals {
+			t.Insert(v)
+		}
+		return
+	}
+
+	all := make([]T, 0, t.size+len(vals))
+	InOrderInto(t.Root(), &all)
+	all = append(all, vals...)
+
+	t.root, _ = avlNodeFromSorted(all, nil)
+	t.size = len(all)
 }
 
 // Delete the requested node from the tree and reports if it was successful.
 // If the value is not in the tree, the tree is unchanged and false is returned.
 // If the node is not a leaf the trees internal structure may be updated.
 func (t *AVL[T]) Delete(v T) bool {
-	return false
+	return t.DeleteErr(v) == nil
+}
+
+// DeleteErr removes the requested node from the tree. If the tree is empty
+// ErrEmptyTree is returned, and if the value is not in the tree ErrNotFound
+// is returned, leaving the tree unchanged.
+//
+// If the tree keeps duplicates, only one copy of the value is removed.
+func (t *AVL[T]) DeleteErr(v T) error {
+	if t.root == nil {
+		return ErrEmptyTree
+	}
+	n := t.root.find(v, t.order.resolve())
+	if n == nil {
+		return ErrNotFound
+	}
+
+	t.root = deleteNode(n)
+	t.size--
+
+	return nil
+}
+
+// DeleteOne removes a single copy of v from the tree, leaving any other copies
+// in place, and reports if one was removed. It is the same as Delete, named
+// for clarity alongside DeleteAll in trees that keep duplicates.
+func (t *AVL[T]) DeleteOne(v T) bool {
+	return t.Delete(v)
+}
+
+// DeleteAll removes every copy of v from the tree, returning the number of
+// copies removed. Use Count to find how many there are without removing them.
+//
+// Unlike a Treap, which counts the copies in one node, this tree keeps each
+// copy in a node of its own, as they may be rotated apart, so removing k
+// copies takes O(k log n) time.
+func (t *AVL[T]) DeleteAll(v T) int {
+	var n int
+	for t.Delete(v) {
+		n++
+	}
+	return n
+}
+
+// Count returns the number of copies of v in the tree. Unless the tree was
+// created to keep duplicates, this is at most one.
+func (t *AVL[T]) Count(v T) int {
+	if t.root == nil {
+		return 0
+	}
+	return countValue[T](t.root, v, t.order.resolve())
+}
+
+// CountFunc returns the number of values in the tree for which pred returns
+// true. Every value is checked, but none are collected along the way.
+func (t *AVL[T]) CountFunc(pred func(T) bool) int {
+	if t.root == nil {
+		return 0
+	}
+	return countMatching[T](t.root, pred)
+}
+
+// DeleteFunc removes every value in the tree for which pred returns true,
+// returning the number of values removed. The values that are kept are
+// collected and rebuilt into a balanced tree, so this takes O(n) time however
+// many are removed.
+func (t *AVL[T]) DeleteFunc(pred func(T) bool) int {
+	if t.root == nil {
+		return 0
+	}
+	kept, removed := valuesExcept[T](t.root, pred, make([]T, 0, t.size))
+	if removed == 0 {
+		return 0
+	}
+	t.root, _ = avlNodeFromSorted(kept, nil)
+	t.size = len(kept)
+	return removed
 }
 
 // Search reports if the given value is in the tree.
@@ -53,7 +278,18 @@ func (t *AVL[T]) Search(v T) bool {
 		return false
 	}
 
-	return t.root.Search(v)
+	return t.root.find(v, t.order.resolve()) != nil
+}
+
+// Get returns the value stored in the tree that is equal to v. If there is
+// no such value, false is returned.
+func (t *AVL[T]) Get(v T) (T, bool) {
+	var zero T
+	n := t.root.find(v, t.order.resolve())
+	if n == nil {
+		return zero, false
+	}
+	return n.value, true
 }
 
 // Traverse traverse the tree in the specified order emitting the values to
@@ -61,13 +297,106 @@ func (t *AVL[T]) Search(v T) bool {
 func (t *AVL[T]) Traverse(tOrder TraverseOrder) <-chan T {
 	ch := make(chan T)
 	go func() {
-		traverseBinaryTree(t.root, tOrder, ch)
+		if !t.IsEmpty() {
+			traverseBinaryTree(t.root, tOrder, ch)
+		}
 		close(ch)
 	}()
 
 	return ch
 }
 
+// TraverseBuffered is like Traverse, but the channel holds up to bufSize values,
+// letting the traversal run ahead of the reader instead of handing over each
+// value in turn. This gives better throughput when the reader does little
+// work per value. A bufSize below 1 gives an unbuffered channel, as Traverse.
+func (t *AVL[T]) TraverseBuffered(tOrder TraverseOrder, bufSize int) <-chan T {
+	ch := make(chan T, max(bufSize, 0))
+	go func() {
+		if t.root != nil {
+			traverseBinaryTree[T](t.root, tOrder, ch)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Walk calls visit with each value of the tree in the given order, stopping
+// as soon as visit returns false. It reports if every value was visited.
+//
+// Unlike Traverse, no goroutine or channel is involved, so stopping early
+// leaves nothing behind.
+func (t *AVL[T]) Walk(tOrder TraverseOrder, visit func(T) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	return walkBinaryTree[T](t.root, tOrder, visit)
+}
+
+// AppendValues appends the values of the tree in the given order onto dst and
+// returns the extended slice, in the manner of append. Reusing dst across
+// calls saves allocating a new slice each time as ToSlice does.
+func (t *AVL[T]) AppendValues(dst []T, tOrder TraverseOrder) []T {
+	if t.root == nil {
+		return dst
+	}
+	return appendValues[T](dst, t.root, tOrder)
+}
+
+// TraverseSlice returns the values of the tree in the given order as a slice.
+// Unlike Traverse, no goroutine or channel is used, which for small trees
+// costs more than the walk itself, and nothing is left running if the caller
+// stops reading early. Use Traverse to stream the values of large trees.
+func (t *AVL[T]) TraverseSlice(tOrder TraverseOrder) []T {
+	if t.root == nil {
+		return nil
+	}
+	return appendValues[T](make([]T, 0, t.size), t.root, tOrder)
+}
+
+// MorrisInOrder returns the values of the tree in order, found with a Morris
+// traversal that needs no stack, recursion or goroutine, only O(1) space
+// beyond the returned slice. The tree is temporarily rethreaded along the way,
+// but left exactly as it was, so it must not be read by anything else while
+// this runs.
+func (t *AVL[T]) MorrisInOrder() []T {
+	if t.root == nil {
+		return nil
+	}
+	return morrisInOrder(t.root, (*avlNode[T]).childFields, (*avlNode[T]).Value, make([]T, 0, t.size))
+}
+
+// LeafCount returns the number of nodes in the tree with no children.
+func (t *AVL[T]) LeafCount() int {
+	if t.root == nil {
+		return 0
+	}
+	leaves, _ := countNodeKinds[T](t.root)
+	return leaves
+}
+
+// InternalCount returns the number of nodes in the tree with at least one
+// child, which is every node that is not a leaf.
+func (t *AVL[T]) InternalCount() int {
+	if t.root == nil {
+		return 0
+	}
+	_, internal := countNodeKinds[T](t.root)
+	return internal
+}
+
+// Boundary returns the values around the edge of the tree, going
+// anti-clockwise from the root: the root, the left boundary from the top
+// down, every leaf from left to right, and then the right boundary from the
+// bottom up. Values where the boundaries meet are only included once.
+func (t *AVL[T]) Boundary() []T {
+	if t.root == nil {
+		return nil
+	}
+	return boundaryValues[T](t.root)
+}
+
 // Height returns the height of the longest path in the tree from the
 // root node to the farthest leaf.
 func (t *AVL[T]) Height() int {
@@ -78,6 +407,314 @@ func (t *AVL[T]) Height() int {
 	return t.root.Height()
 }
 
+// MinDepth returns the number of edges from the root to the nearest leaf,
+// found with a breadth first search that stops at the first leaf reached. Set
+// against Height, it shows how lopsided the tree is. An empty tree, like one
+// with only a root, returns 0.
+func (t *AVL[T]) MinDepth() int {
+	if t.root == nil {
+		return 0
+	}
+	return minLeafDepth[T](t.root)
+}
+
+// BalanceInfo returns the Height, MinDepth and Size of the tree together with
+// its SkewRatio, to show at a glance how far from balanced the tree is.
+func (t *AVL[T]) BalanceInfo() BalanceInfo {
+	return newBalanceInfo(t.Height(), t.MinDepth(), t.size)
+}
+
+// Diameter returns the number of nodes on the longest path between any two
+// leaves of the tree, which may or may not pass through the root.
+func (t *AVL[T]) Diameter() int {
+	if t.root == nil {
+		return 0
+	}
+	_, d := binaryTreeDiameter[T](t.root)
+	return d
+}
+
+// Size returns the number of values held in the tree.
+func (t *AVL[T]) Size() int {
+	return t.size
+}
+
+// IsEmpty reports if the tree holds no values.
+func (t *AVL[T]) IsEmpty() bool {
+	return t.root == nil
+}
+
+// String returns the values of the tree indented one level per depth, with
+// the root first and each child after its parent, for use when printing the
+// tree. Large trees are cut short after the first few dozen nodes.
+func (t *AVL[T]) String() string {
+	return treeString[T](t.root, t.size)
+}
+
+// Min returns the smallest value in the tree, or the largest if the tree is
+// Descending. If the tree is empty, false is returned.
+func (t *AVL[T]) Min() (T, bool) {
+	var zero T
+	if t.root == nil {
+		return zero, false
+	}
+
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+	return n.value, true
+}
+
+// Max returns the largest value in the tree, or the smallest if the tree is
+// Descending. If the tree is empty, false is returned.
+func (t *AVL[T]) Max() (T, bool) {
+	var zero T
+	if t.root == nil {
+		return zero, false
+	}
+
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.value, true
+}
+
+// PopMin removes the smallest value from the tree, or the largest if the tree
+// is Descending, and returns it. Together with PopMax this lets the tree serve
+// as a double ended priority queue, with each operation taking O(log n) time.
+// If the tree is empty, false is returned.
+func (t *AVL[T]) PopMin() (T, bool) {
+	var zero T
+	if t.root == nil {
+		return zero, false
+	}
+
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+	t.root = removeNode(n)
+	t.size--
+	return n.value, true
+}
+
+// PopMax removes the largest value from the tree, or the smallest if the tree
+// is Descending, and returns it. See PopMin.
+func (t *AVL[T]) PopMax() (T, bool) {
+	var zero T
+	if t.root == nil {
+		return zero, false
+	}
+
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+	t.root = removeNode(n)
+	t.size--
+	return n.value, true
+}
+
+// Predecessor returns the value in the tree that comes before v in order. If v
+// is not in the tree, or is the first value, false is returned.
+func (t *AVL[T]) Predecessor(v T) (T, bool) {
+	var zero T
+	n := t.root.find(v, t.order.resolve())
+	if n == nil {
+		return zero, false
+	}
+
+	// The last value in the left subtree comes immediately before.
+	if n.left != nil {
+		n = n.left
+		for n.right != nil {
+			n = n.right
+		}
+		return n.value, true
+	}
+
+	// Otherwise walk up until we come up from a right child.
+	for n.parent != nil && n == n.parent.left {
+		n = n.parent
+	}
+	if n.parent == nil {
+		return zero, false
+	}
+	return n.parent.value, true
+}
+
+// Successor returns the value in the tree that comes after v in order. If v
+// is not in the tree, or is the last value, false is returned.
+func (t *AVL[T]) Successor(v T) (T, bool) {
+	var zero T
+	n := t.root.find(v, t.order.resolve())
+	if n == nil {
+		return zero, false
+	}
+
+	// The first value in the right subtree comes immediately after.
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n.value, true
+	}
+
+	// Otherwise walk up until we come up from a left child.
+	for n.parent != nil && n == n.parent.right {
+		n = n.parent
+	}
+	if n.parent == nil {
+		return zero, false
+	}
+	return n.parent.value, true
+}
+
+// LCA returns the lowest common ancestor of a and b, the value of the deepest
+// node that has both of them in its subtree. If one value is an ancestor of
+// the other, it is the lowest common ancestor. If either value is not in the
+// tree, false is returned.
+func (t *AVL[T]) LCA(a, b T) (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return lowestCommonAncestor[T](t.root, a, b, t.order.resolve())
+}
+
+// PathTo returns the values from the root of the tree down to the node
+// holding v, in the order a search for v visits them. The path starts with
+// the root and ends with v. If v is not in the tree, false is returned.
+func (t *AVL[T]) PathTo(v T) ([]T, bool) {
+	if t.root == nil {
+		return nil, false
+	}
+	return valuePath[T](t.root, v, t.order.resolve())
+}
+
+// Ancestors returns the values of the nodes above v in the tree, from its
+// parent up to the root, which is PathTo reversed and without v. The root has
+// no ancestors, giving an empty slice. If v is not in the tree, false is
+// returned.
+func (t *AVL[T]) Ancestors(v T) ([]T, bool) {
+	if t.root == nil {
+		return nil, false
+	}
+	return valueAncestors[T](t.root, v, t.order.resolve())
+}
+
+// Depth returns the number of edges between the root of the tree and the
+// node holding v, with the root at depth 0. If v is not in the tree, false
+// is returned.
+func (t *AVL[T]) Depth(v T) (int, bool) {
+	if t.root == nil {
+		return 0, false
+	}
+	return valueDepth[T](t.root, v, t.order.resolve())
+}
+
+// SameSubtree reports if a and b are both in the tree and lie on the same
+// side of the root, either both in its left subtree or both in its right. If
+// either value is missing, or is the root itself, false is returned.
+func (t *AVL[T]) SameSubtree(a, b T) bool {
+	if t.root == nil {
+		return false
+	}
+	return sameSubtree[T](t.root, a, b, t.order.resolve())
+}
+
+// From returns an iterator over the values of the tree in the given order,
+// starting at v rather than at the start of the tree. In order, the first
+// value is the first one not before v, and in reverse order it is the last one
+// not after v, so v need not be in the tree. Only the search path for v is
+// looked at before the first value is yielded, making it cheap to scan a few
+// values from a point in a large tree.
+//
+// The other orders have no position to start from, and yield nothing.
+func (t *AVL[T]) From(v T, tOrder TraverseOrder) func(yield func(T) bool) {
+	return valuesFrom[T](t.root, v, tOrder, t.order.resolve())
+}
+
+// RangeSearch returns the values v in the tree with lo <= v <= hi, in the
+// order of the tree. Subtrees that can not hold values in the range are not
+// visited, making this much cheaper than a full traversal for narrow ranges.
+func (t *AVL[T]) RangeSearch(lo, hi T) []T {
+	return t.rangeSearch(lo, hi, nil)
+}
+
+// rangeSearch is the worker for RangeSearch, calling visit, if not nil, on
+// each node looked at.
+func (t *AVL[T]) rangeSearch(lo, hi T, visit func(BinaryTree[T])) []T {
+	o := t.order.resolve()
+	if t.root == nil || o.cmp(hi, lo) < 0 {
+		return nil
+	}
+
+	var out []T
+	rangeAppend(t.Root(), lo, hi, o, visit, &out)
+	return out
+}
+
+// Select returns the k-th value (0-indexed) in order, i.e., the k-th smallest
+// value, or the k-th largest if the tree is Descending. If k is out of range,
+// false is returned.
+func (t *AVL[T]) Select(k int) (T, bool) {
+	var zero T
+	if k < 0 || k >= t.root.subtreeSize() {
+		return zero, false
+	}
+
+	n := t.root
+	for n != nil {
+		ls := n.left.subtreeSize()
+		switch {
+		case k < ls:
+			n = n.left
+		case k == ls:
+			return n.value, true
+		default:
+			k -= ls + 1
+			n = n.right
+		}
+	}
+	return zero, false
+}
+
+// At returns the value at the given 0-based position in order, i.e., At(0)
+// is the smallest value, or the largest if the tree is Descending. It is the
+// same as Select, taking O(height) time using the subtree sizes. If index is
+// out of range, false is returned.
+func (t *AVL[T]) At(index int) (T, bool) {
+	return t.Select(index)
+}
+
+// SelectDescending returns the k-th largest value (0-indexed), or the k-th
+// smallest if the tree is Descending. Like Select, this takes O(height) time
+// using the subtree sizes. If k is out of range, false is returned.
+func (t *AVL[T]) SelectDescending(k int) (T, bool) {
+	return t.Select(t.root.subtreeSize() - 1 - k)
+}
+
+// Rank returns the number of values in the tree that come before v in order,
+// i.e., the count of values strictly less than v, or strictly greater if the
+// tree is Descending. v need not be in the tree.
+func (t *AVL[T]) Rank(v T) int {
+	var rank int
+	o := t.order.resolve()
+	for n := t.root; n != nil; {
+		if o.compare(n.value, v) < 0 {
+			rank += n.left.subtreeSize() + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return rank
+}
+
 // toTestString prints out this tree with all its properties and children
 // ready to copy and paste into test code.
 // NOTE: This does not determine the exact type of T this instance is. It
@@ -3,18 +3,27 @@ package tree
 import (
 	"bytes"
 	"fmt"
-
-	"golang.org/x/exp/constraints"
 )
 
 // avlNode is the actual node in an AVL tree.
-type avlNode[T constraints.Ordered] struct {
+type avlNode[T any] struct {
 	value T
 
 	// bf is the balance factor, the height difference of the nodes two subtrees.
 	// Could probably be an int8 since its always in the range [-2, +2]
 	bf int
 
+	// size is the number of nodes in the subtree rooted here, including this
+	// one. It is kept up to date by insert and the rotations so that order
+	// statistics can be found in O(height).
+	size int
+
+	// height is the height of the subtree rooted here, 1 for a leaf. Like
+	// size, it is kept up to date by insert and the rotations so that Height
+	// does not need to walk the whole subtree. Zero means it was never set,
+	// such as for nodes built by hand, in which case it is computed.
+	height int
+
 	// parent is a pointer back to the parent node to allow for updates
 	// when rebalancing and navigating.
 	parent *avlNode[T]
@@ -24,6 +33,33 @@ type avlNode[T constraints.Ordered] struct {
 	right *avlNode[T]
 }
 
+// avlRotationHook, if set, is called for every single rotation. It is only
+// set by tests to measure the rebalancing work done, and is nil otherwise.
+var avlRotationHook func()
+
+// avlNodeFromSorted builds a balanced subtree from the given sorted values by
+// recursively using the middle value as the root of each subtree. It returns
+// the new subtree and its height.
+func avlNodeFromSorted[T any](vals []T, parent *avlNode[T]) (*avlNode[T], int) {
+	if len(vals) == 0 {
+		return nil, 0
+	}
+
+	mid := len(vals) / 2
+	node := &avlNode[T]{
+		parent: parent,
+		value:  vals[mid],
+		size:   len(vals),
+	}
+	var lh, rh int
+	node.left, lh = avlNodeFromSorted(vals[:mid], node)
+	node.right, rh = avlNodeFromSorted(vals[mid+1:], node)
+	node.bf = rh - lh
+	node.height = max(lh, rh) + 1
+
+	return node, node.height
+}
+
 // HasLeft reports if this node has a Left child.
 func (t *avlNode[T]) HasLeft() bool {
 	return t.left != nil
@@ -44,6 +80,20 @@ func (t *avlNode[T]) Right() BinaryTree[T] {
 	return t.right
 }
 
+// Parent returns this nodes parent, or nil if this is the root.
+func (t *avlNode[T]) Parent() BinaryTree[T] {
+	if t == nil || t.parent == nil {
+		return nil
+	}
+	return t.parent
+}
+
+// childFields returns the addresses of this nodes child pointers, so that
+// generic helpers such as flattenNodes can rearrange them.
+func (t *avlNode[T]) childFields() (left, right **avlNode[T]) {
+	return &t.left, &t.right
+}
+
 // Value returns this nodes Value.
 func (t *avlNode[T]) Value() T {
 	return t.value
@@ -67,119 +117,131 @@ func (t *avlNode[T]) balanceFactor() int {
 
 // Insert inserts the node into the tree, growing as needed, and reports
 // if the operation was successful.
+//
+// Rebalancing may rotate a new node up into this node's place, so when this
+// is the root of a tree, callers should prefer the tree's Insert which keeps
+// track of the root.
 func (t *avlNode[T]) Insert(v T) bool {
+	_, ok := t.insert(v, ordering[T]{}.resolve())
+	return ok
+}
+
+// insert is the worker for Insert, placing v according to the given ordering
+// of the tree. It returns the root of the whole tree, which
+// changes if a rotation happens at the root, and reports if v was added.
+func (t *avlNode[T]) insert(v T, o ordering[T]) (*avlNode[T], bool) {
 	if t == nil {
 		t = &avlNode[T]{
-			value: v,
-			bf:    0,
-			left:  nil,
-			right: nil,
+			value:  v,
+			bf:     0,
+			size:   1,
+			height: 1,
+			left:   nil,
+			right:  nil,
 		}
 
-		return true
+		return t, true
 	}
 
-	// Inserting a duplicate value is an error.
-	if v == t.value {
-		return false
+	// Inserting a duplicate value is an error, unless the tree keeps them,
+	// in which case they go to the right.
+	c := o.compare(v, t.value)
+	if c == 0 && !o.duplicates {
+		return nil, false
 	}
 
 	// If we need to go farther left, recurse!
-	if v < t.value && t.left != nil {
-		return t.left.Insert(v)
+	if c < 0 && t.left != nil {
+		return t.left.insert(v, o)
 	}
 
 	// If we need to go farther right, recurse!
-	if v > t.value && t.right != nil {
-		return t.right.Insert(v)
+	if c >= 0 && t.right != nil {
+		return t.right.insert(v, o)
 	}
 
 	// We are at the end of the line going left, we need to add
 	// a new node to the left, updating balancing factors.
-	if v < t.value {
-		t.left = &avlNode[T]{
-			parent: t,
-			value:  v,
-			bf:     0,
-			left:   nil,
-			right:  nil,
-		}
+	child := &avlNode[T]{
+		parent: t,
+		value:  v,
+		bf:     0,
+		size:   1,
+		height: 1,
+		left:   nil,
+		right:  nil,
+	}
+	if c < 0 {
+		t.left = child
 	} else {
 		// Or we need to add a new node to the right.
-		t.right = &avlNode[T]{
-			parent: t,
-			value:  v,
-			bf:     0,
-			left:   nil,
-			right:  nil,
-		}
+		t.right = child
 	}
 
-	// Update the balance factor back up from here after adding the new node.
-	updateBalanceFactors(t)
-
-	// Now we need to check for imbalance and apply updates as needed.
-
-	// We are in a leaf node, or a node with only the new child, so this node
-	// is not the one that needs a rebalance. Start working our way up the tree
-	// looking for a node that is far enough out of balance.
+	// Every node on the path back up to the root gained a descendant.
+	root := t
 	for x := t; x != nil; x = x.parent {
-		// If this next level is balanced enough, move up and try again.
-		// We will either get to the root or find a strong imbalance.
-		if x.bf >= -1 && x.bf <= 1 {
-			continue
+		x.size++
+		root = x
+	}
+
+	// Walk back up from the new node updating balance factors. Once a
+	// subtree's height is unchanged, nothing above it changes either, so
+	// we can stop. At most one (single or double) rotation is needed, as it
+	// restores the subtree to its height before the insert.
+	for x := t; x != nil; child, x = x, x.parent {
+		if child == x.left {
+			x.bf--
+		} else {
+			x.bf++
 		}
 
-		if x.bf > 1 { // The node is right-heavy
-			if x.right != nil {
-				// Check if it's Right-Right or Right-Left
-				if x.right.bf < 0 {
-					// Right-Left Case
-					// Double rotation: Right(Z) then Left(X)
-					rotateRightLeft(x)
-				} else if x.right.bf > 0 {
-					// Right-Right Case
-					// Single rotation Left(X)
-					rotateLeft(x)
-				}
+		var sub *avlNode[T]
+		switch {
+		case x.bf == 0:
+			// The shorter side caught up, the height is unchanged.
+			return root, true
+		case x.bf > 1: // The node is right-heavy
+			// Check if it's Right-Right or Right-Left
+			if x.right.bf < 0 {
+				// Right-Left Case
+				// Double rotation: Right(Z) then Left(X)
+				sub = rotateRightLeft(x)
+			} else {
+				// Right-Right Case
+				// Single rotation Left(X)
+				sub = rotateLeft(x)
 			}
-		} else if x.bf < -1 {
-			if x.left != nil {
-				// Check if it's Left-Right or Left-Left
-				if x.left.bf > 0 {
-					// Left-Right Case
-					// Double rotation: Left(Z) then Right(X)
-					rotateLeftRight(x)
-				} else if x.left.bf < 0 {
-					// Left-Left Case
-					// Single rotation Right
-					rotateRight(x)
-				}
+		case x.bf < -1:
+			// Check if it's Left-Right or Left-Left
+			if x.left.bf > 0 {
+				// Left-Right Case
+				// Double rotation: Left(Z) then Right(X)
+				sub = rotateLeftRight(x)
+			} else {
+				// Left-Left Case
+				// Single rotation Right
+				sub = rotateRight(x)
 			}
+		default:
+			// This subtree grew by one, keep going up.
+			x.updateHeight()
+			continue
 		}
-	}
 
-	return true
-}
-
-func updateBalanceFactors[T constraints.Ordered](node *avlNode[T]) {
-	const limit = 4
-
-	var i int
-
-	// Update the balance factor back up from here after adding the new node.
-	for x := node; x != nil; x = x.parent {
-		x.bf = x.balanceFactor()
-		i++
-
-		if i > limit {
-			break
+		// If the rotation was at the root, the subtree is the new root.
+		if sub.parent == nil {
+			root = sub
 		}
+		return root, true
 	}
+
+	return root, true
 }
 
 // rotateLeft takes a node in the tree and rotates left through the middle
-// node to balance it.
+// node to balance it. The new root of the rotated subtree is linked into
+// node's old place under its parent and returned.
 //
 // THe most common form is:
 //
@@ -217,9 +279,9 @@ func updateBalanceFactors[T constraints.Ordered](node *avlNode[T]) {
 //
 //	        parent
 //	          /
-//	   (-2) [C]   <-- node
+//	   (-2) [C]
 //	        /
-//	 (-1) [B]
+//	 (-1) [B]   <-- returned
 //	      /
 //	(0) [A]
 //
@@ -241,85 +303,56 @@ func updateBalanceFactors[T constraints.Ordered](node *avlNode[T]) {
 //
 //	       parent
 //	          \
-//	          [M] (0)   <-- node
+//	          [M] (0)   <-- returned
 //	         /   \
 //	  (0) [H]     [S] (+1)
 //	      / \        \
 //	(0) [E] [J] (0)   [Z] (0)
 //
 // And once again balance is restored.
-func rotateLeft[T constraints.Ordered](node *avlNode[T]) *avlNode[T] {
-	// node is the node with a balance factor >= 2
-	// Save its two children and its right childs two children.
-	childL := node.left
-	childR := node.right
-	grandchildL := childR.left
-	grandchildR := childR.right
-
-	// parent
-	//   \
-	//   [H]  <-- node
-	//     \
-	//     [N]
-	//       \
-	//       [Z]
-	//
+func rotateLeft[T any](node *avlNode[T]) *avlNode[T] {
+	if avlRotationHook != nil {
+		avlRotationHook()
+	}
 
-	// Move N to H's left child.
-	// Move Z up to H's right spot and update its parent to H.
-	//
-	// parent
-	//   \
-	//   [H]
-	//   / \
-	// [N] [Z]
+	//   parent              parent
+	//     \                   \
+	//     [H]  <-- node       [N]  <-- returned
+	//     / \                 / \
+	//    a  [N]     =>      [H]  c
+	//       / \             / \
+	//      b   c           a   b
 	//
-	node.left = childR
-	node.right = grandchildR
-
-	// If this was a full rotate (and not the first part of a rotate left then right)
-	// then there would be a grandchild node that would need its parent set.
-	if node.right != nil {
-		node.right.parent = node
-	}
+	child := node.right
+	b := child.left
 
-	// If the right child had a left grandchild tree, it jumps over to become
-	// the new left childs left node.
-	node.left.left = grandchildL
-	if node.left.left != nil {
-		node.left.left.parent = node.left
+	node.right = b
+	if b != nil {
+		b.parent = node
 	}
+	replaceChild(node.parent, node, child)
+	child.left = node
+	node.parent = child
 
-	// If there was an existing left child it becomes the left nodes right grandchild.
-	node.left.right = childL
-	if node.left.right != nil {
-		node.left.right.parent = node.left
-	}
+	// The child now holds everything the node did.
+	child.size = node.size
+	node.size = 1 + node.left.subtreeSize() + b.subtreeSize()
 
-	// Swap H & N's values
-	//
-	//  parent
-	//    \
-	//    [N]
-	//    / \
-	//  [H] [Z]
-	//
-	node.value, childR.value = childR.value, node.value
+	// Standard balance factor updates for a left rotation.
+	node.bf = node.bf - 1 - max(child.bf, 0)
+	child.bf = child.bf - 1 + min(node.bf, 0)
 
-	// Update the affected nodes balance factors and up the tree.
-	updateBalanceFactors(node.left)
-	// For the other child node, only need to update it by itself.
-	// updateBalanceFactors handles the main node and on up.
-	if node.right != nil {
-		node.right.bf = node.right.balanceFactor()
-	}
+	// The node is now below the child, so it must be updated first.
+	node.updateHeight()
+	child.updateHeight()
 
 	// Return new root of rotated subtree
-	return node
+	return child
 }
 
 // rotateRight takes a set of nodes and rotates right through the middle
-// node to balance it.
+// node to balance it. The new root of the rotated subtree is linked into
+// node's old place under its parent and returned.
 //
 // The most common form is:
 //
@@ -335,7 +368,7 @@ func rotateLeft[T constraints.Ordered](node *avlNode[T]) *avlNode[T] {
 //
 //	   parent
 //	      \
-//	      [C] (0)   <-- node
+//	      [C] (0)   <-- returned
 //	      / \
 //	(0) [A] [E] (0)
 //
@@ -354,9 +387,9 @@ func rotateLeft[T constraints.Ordered](node *avlNode[T]) *avlNode[T] {
 //
 //	parent
 //	   \
-//	   [H] (_2)   <-- node
+//	   [H] (+2)
 //	     \
-//	     [N] (+1)
+//	     [N] (+1)   <-- returned
 //	       \
 //	       [Z] (0)
 //
@@ -378,80 +411,66 @@ func rotateLeft[T constraints.Ordered](node *avlNode[T]) *avlNode[T] {
 //
 //	          parent
 //	             /
-//	        (0) [E]  <-- node
+//	        (0) [E]  <-- returned
 //	           /   \
 //	   (-1) [C]     [H] (0)
 //	       /        / \
 //	(0) [A] (0)   [F] [J] (0)
 //
 // And once again balance is restored.
-func rotateRight[T constraints.Ordered](node *avlNode[T]) *avlNode[T] {
-	// Save its two children and its left childs two children.
-	childL := node.left
-	childR := node.right
-	grandchildL := childL.left
-	grandchildR := childL.right
-
-	// From our starting point:
-	//
-	//       parent
-	//        /
-	//      [E]  (<--node)
-	//      /
-	//    [C]
-	//    /
-	//  [A]
-	//
-	// Move left child to node's right.
-	// Move left grandchild up to left child and update its parent to node..
-	//
-	//   parent
-	//     \
-	//     [E]
-	//     / \
-	//   [A] [C]
-	//
-	node.left = grandchildL
-	node.right = childL
-	// If this was a full rotate (and not the first part of a rotate right then left)
-	// then there would be a grandchild node that would need its parent set.
-	if node.left != nil {
-		node.left.parent = node
+func rotateRight[T any](node *avlNode[T]) *avlNode[T] {
+	if avlRotationHook != nil {
+		avlRotationHook()
 	}
 
-	// If the left child had a right grandchild tree, it jumps over to become
-	// the new right childs left node.
-	node.right.left = grandchildR
-	if node.right.left != nil {
-		node.right.left.parent = node.right
-	}
+	//        parent                 parent
+	//          /                      /
+	//   node  [E]         returned  [C]
+	//         / \                   / \
+	//       [C]  c       =>        a  [E]
+	//       / \                       / \
+	//      a   b                     b   c
+	//
+	child := node.left
+	b := child.right
 
-	// If there was an existing right child it becomes nodes right grandchild.
-	node.right.right = childR
-	if node.right.right != nil {
-		node.right.right.parent = node.right
+	node.left = b
+	if b != nil {
+		b.parent = node
 	}
+	replaceChild(node.parent, node, child)
+	child.right = node
+	node.parent = child
 
-	// Swap node and new right childs values.
-	//
-	//  parent
-	//    \
-	//    [C]
-	//    / \
-	//  [A] [E]
-	//
-	node.value, childL.value = childL.value, node.value
+	// The child now holds everything the node did.
+	child.size = node.size
+	node.size = 1 + b.subtreeSize() + node.right.subtreeSize()
 
-	// Update the affected nodes balance factors and the parents.
-	updateBalanceFactors(node.left)
-	// For the other child node, only need to update it by itself.
-	// updateBalanceFactors handles the main node and on up.
-	if node.right != nil {
-		node.right.bf = node.right.balanceFactor()
-	}
+	// Standard balance factor updates for a right rotation.
+	node.bf = node.bf + 1 - min(child.bf, 0)
+	child.bf = child.bf + 1 + max(node.bf, 0)
+
+	// The node is now below the child, so it must be updated first.
+	node.updateHeight()
+	child.updateHeight()
 
 	// Return new root of rotated subtree
-	return node
+	return child
+}
+
+// replaceChild points whichever of parent's children was old at new instead,
+// and sets new's parent to match. If parent is nil, old was the root of the
+// tree and only the parent of new is updated.
+func replaceChild[T any](parent, old, new *avlNode[T]) {
+	new.parent = parent
+	if parent == nil {
+		return
+	}
+	if parent.left == old {
+		parent.left = new
+	} else {
+		parent.right = new
+	}
 }
 
 // rotateRightLeft performs a double rotation, first right around the middle node
@@ -482,47 +501,142 @@ func rotateRight[T constraints.Ordered](node *avlNode[T]) *avlNode[T] {
 //	 (0)   (0)
 //
 // And balance is once again restored.
-func rotateRightLeft[T constraints.Ordered](node *avlNode[T]) *avlNode[T] {
+func rotateRightLeft[T any](node *avlNode[T]) *avlNode[T] {
 	rotateRight(node.right)
-	rotateLeft(node)
 
-	return node
+	return rotateLeft(node)
 }
 
-func rotateLeftRight[T constraints.Ordered](node *avlNode[T]) *avlNode[T] {
+// rotateLeftRight performs the mirror image double rotation of
+// rotateRightLeft, first left around the middle node and then right.
+func rotateLeftRight[T any](node *avlNode[T]) *avlNode[T] {
 	rotateLeft(node.left)
-	rotateRight(node)
 
-	return node
+	return rotateRight(node)
+}
+
+// removeNode unlinks the given node, which must have at most one child, from
+// its tree, moving the child up into its place. Every node on the path back up
+// to the root loses a descendant and has its balance checked, rotating where
+// the removal left it unbalanced. Unlike an insert, a rotation after a removal
+// can shorten the subtree, so the walk carries on to the root. It returns the
+// new root of the tree, which is nil if node was the only node.
+func removeNode[T any](node *avlNode[T]) *avlNode[T] {
+	child := node.left
+	if child == nil {
+		child = node.right
+	}
+	parent := node.parent
+	if child != nil {
+		child.parent = parent
+	}
+	node.parent, node.left, node.right = nil, nil, nil
+	if parent == nil {
+		return child
+	}
+	if parent.left == node {
+		parent.left = child
+	} else {
+		parent.right = child
+	}
+
+	root := parent
+	for x := parent; x != nil; x = x.parent {
+		x.size--
+	}
+
+	for x := parent; x != nil; x = x.parent {
+		x.bf = x.right.Height() - x.left.Height()
+		switch {
+		case x.bf > 1:
+			if x.right.bf < 0 {
+				x = rotateRightLeft(x)
+			} else {
+				x = rotateLeft(x)
+			}
+		case x.bf < -1:
+			if x.left.bf > 0 {
+				x = rotateLeftRight(x)
+			} else {
+				x = rotateRight(x)
+			}
+		default:
+			x.updateHeight()
+		}
+		root = x
+	}
+	return root
+}
+
+// deleteNode removes the given node's value from its tree, returning the new
+// root of the tree. A node with two children takes the value of its in order
+// successor, which has no left child, and the successor's node is removed
+// in its place.
+func deleteNode[T any](node *avlNode[T]) *avlNode[T] {
+	if node.left != nil && node.right != nil {
+		succ := node.right
+		for succ.left != nil {
+			succ = succ.left
+		}
+		node.value = succ.value
+		node = succ
+	}
+	return removeNode(node)
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+func (t *avlNode[T]) InsertAll(vals ...T) int {
+	var n int
+	for _, v := range vals {
+		if t.Insert(v) {
+			n++
+		}
+	}
+	return n
 }
 
 // Delete the requested node from the tree and reports if it was successful.
 // If the value is not in the tree, the tree is unchanged and false is returned.
 // If the node is not a leaf the trees internal structure may be updated.
+//
+// Rebalancing may rotate another node up into this node's place, so when
+// this is the root of a tree, callers should prefer the tree's Delete which
+// keeps track of the root. The last node of a tree can not remove itself, so
+// false is returned for it.
 func (t *avlNode[T]) Delete(v T) bool {
 	if t == nil {
 		return false
 	}
 
-	return false
+	n := t.find(v, ordering[T]{}.resolve())
+	if n == nil || (n.parent == nil && n.left == nil && n.right == nil) {
+		return false
+	}
+	deleteNode(n)
+	return true
 }
 
 // Search reports if the given value is in the tree.
 func (t *avlNode[T]) Search(v T) bool {
-	// If this (child) node is nil, then there is nothing to find.
-	if t == nil {
-		return false
-	}
-
-	if v == t.value {
-		return true
-	}
+	return t.find(v, ordering[T]{}.resolve()) != nil
+}
 
-	if v < t.value {
-		return t.left.Search(v)
+// find returns the node holding the given value, or nil if it is not in
+// the tree, following the given ordering of the tree.
+func (t *avlNode[T]) find(v T, o ordering[T]) *avlNode[T] {
+	for n := t; n != nil; {
+		c := o.compare(v, n.value)
+		if c == 0 {
+			return n
+		}
+		if c < 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
 	}
-
-	return t.right.Search(v)
+	return nil
 }
 
 // Traverses traverse the tree in the specified order emitting the values to
@@ -535,7 +649,7 @@ func (t *avlNode[T]) Traverse(tOrder TraverseOrder) <-chan T {
 
 	// If the node is nil that we are trying to traverse, return the channel,
 	// but close it off since there is no way to have anything to send.
-	if t == nil {
+	if t.IsEmpty() {
 		defer close(ch)
 
 		return ch
@@ -555,9 +669,27 @@ func (t *avlNode[T]) Height() int {
 	if t == nil {
 		return 0
 	}
+	if t.height > 0 {
+		return t.height
+	}
+	return t.computeHeight()
+}
+
+// updateHeight sets the tracked height of this node from the heights of its
+// children.
+func (t *avlNode[T]) updateHeight() {
+	t.height = 1 + max(t.left.Height(), t.right.Height())
+}
+
+// computeHeight returns the height of the tree rooted at this node by walking
+// the whole subtree, ignoring any tracked heights.
+func (t *avlNode[T]) computeHeight() int {
+	if t == nil {
+		return 0
+	}
 
-	lHeight := t.left.Height()
-	rHeight := t.right.Height()
+	lHeight := t.left.computeHeight()
+	rHeight := t.right.computeHeight()
 
 	if lHeight > rHeight {
 		return lHeight + 1
@@ -566,6 +698,29 @@ func (t *avlNode[T]) Height() int {
 	return rHeight + 1
 }
 
+// subtreeSize returns the tracked number of nodes in the subtree rooted at
+// this node.
+func (t *avlNode[T]) subtreeSize() int {
+	if t == nil {
+		return 0
+	}
+	return t.size
+}
+
+// Size returns the number of values in the tree rooted at this node.
+func (t *avlNode[T]) Size() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + t.left.Size() + t.right.Size()
+}
+
+// IsEmpty reports if the tree rooted at this node holds no values, which is
+// only the case for a nil node.
+func (t *avlNode[T]) IsEmpty() bool {
+	return t == nil
+}
+
 func (t *avlNode[T]) toTestString(buf *bytes.Buffer, indent int) {
 	// testIndents is a sequence of tab characaters that are to be substringed
 	// at the necessary level for proper indenting of node text.
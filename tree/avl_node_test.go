@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -39,6 +40,32 @@ func TestAVLNodeBasics(t *testing.T) {
 	node.right.right.Insert(75)
 }
 
+func TestAVLNodeBalanceFactorsStayCurrent(t *testing.T) {
+	// Build a deep tree, first with a long increasing run to push inserts
+	// well below the root, then with values scattered throughout.
+	tree := &AVL[int]{}
+	for i := 0; i < 2000; i++ {
+		tree.Insert(2 * i)
+	}
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 2000; i++ {
+		tree.Insert(r.Intn(4000))
+	}
+
+	var check func(n *avlNode[int])
+	check = func(n *avlNode[int]) {
+		if n == nil {
+			return
+		}
+		if want := n.balanceFactor(); n.bf != want {
+			t.Errorf("node %d has stored bf %d, want %d", n.value, n.bf, want)
+		}
+		check(n.left)
+		check(n.right)
+	}
+	check(tree.root)
+}
+
 func TestAVLNodeInsert(t *testing.T) {
 	// Tests are done with ints to prove the code does the right thing.
 	tests := []struct {
@@ -96,9 +123,28 @@ func TestAVLNodeDelete(t *testing.T) {
 			want: false,
 		},
 		{
+			// The only node of a tree can not remove itself.
 			tree: &avlNode[int]{},
 			want: false,
 		},
+		{
+			tree: func() *avlNode[int] {
+				tree := &AVL[int]{}
+				tree.InsertAll(2, 1, 3)
+				return tree.root
+			}(),
+			val:  3,
+			want: true,
+		},
+		{
+			tree: func() *avlNode[int] {
+				tree := &AVL[int]{}
+				tree.InsertAll(2, 1, 3)
+				return tree.root
+			}(),
+			val:  4,
+			want: false,
+		},
 	}
 
 	for _, test := range tests {
@@ -211,7 +257,7 @@ func TestAVLNodeTraverse(t *testing.T) {
 		{
 			tree:  node,
 			order: TraverseLevelOrder,
-			want:  nil,
+			want:  []int{21, 1, -13, 11},
 		},
 	}
 
@@ -234,4 +280,8 @@ func TestAVLNodeTraverse(t *testing.T) {
 				got, test.want, cmp.Diff(test.want, got))
 		}
 	}
+
+	if err := CheckTraversalConsistency[int](node); err != nil {
+		t.Errorf("CheckTraversalConsistency() = %v", err)
+	}
 }
@@ -1,16 +1,22 @@
 package tree
 
 import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/exp/constraints"
 )
 
 var (
 	avlTestTree = &AVL[int]{
 		root: &avlNode[int]{
 			value: 21,
-			bf:    -1,
+			bf:    1,
 			left: &avlNode[int]{
 				value: 1,
 				bf:    0,
@@ -76,7 +82,7 @@ func TestAVLTraverse(t *testing.T) {
 		{
 			tree:  avlTestTree,
 			order: TraverseLevelOrder,
-			want:  nil,
+			want:  []int{21, 1, 42, -13, 11, 30, 84, 57, 90},
 		},
 	}
 
@@ -99,4 +105,727 @@ func TestAVLTraverse(t *testing.T) {
 				got, test.want, cmp.Diff(test.want, got))
 		}
 	}
+
+	if err := CheckTraversalConsistency(avlTestTree.Root()); err != nil {
+		t.Errorf("CheckTraversalConsistency() = %v", err)
+	}
+}
+
+// checkAVL reports if every node in the subtree has a balance factor in the
+// range [-1, +1] that matches its actual subtree heights, and that parent
+// pointers are consistent. It returns the height of the subtree.
+func checkAVL[T constraints.Ordered](n *avlNode[T]) (int, bool) {
+	if n == nil {
+		return 0, true
+	}
+	if (n.left != nil && n.left.parent != n) ||
+		(n.right != nil && n.right.parent != n) {
+		return 0, false
+	}
+
+	lh, lok := checkAVL(n.left)
+	rh, rok := checkAVL(n.right)
+	if !lok || !rok || n.bf != rh-lh || n.bf < -1 || n.bf > 1 {
+		return 0, false
+	}
+
+	return max(lh, rh) + 1, true
+}
+
+func TestAVLInsertSortedStream(t *testing.T) {
+	tests := []struct {
+		name   string
+		have   []int
+		stream []int
+		want   []int
+	}{
+		{
+			name: "empty stream",
+			have: []int{5, 3},
+			want: []int{3, 5},
+		},
+		{
+			name:   "sorted into empty tree",
+			stream: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			want:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		{
+			name:   "sorted after existing values",
+			have:   []int{2, 1, 3},
+			stream: []int{4, 5, 6, 7, 8, 9, 10, 11},
+			want:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+		},
+		{
+			name:   "runs broken by smaller values and duplicates",
+			stream: []int{10, 20, 30, 40, 50, 60, 70, 5, 80, 90, 90, 100, 1},
+			want:   []int{1, 5, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
+		},
+	}
+
+	for _, test := range tests {
+		tree := &AVL[int]{}
+		for _, v := range test.have {
+			tree.Insert(v)
+		}
+
+		ch := make(chan int)
+		go func() {
+			for _, v := range test.stream {
+				ch <- v
+			}
+			close(ch)
+		}()
+		tree.InsertSortedStream(ch)
+
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("%s: InsertSortedStream() = %v, want %v",
+				test.name, got, test.want)
+		}
+		if tree.Size() != len(test.want) {
+			t.Errorf("%s: Size() = %d, want %d",
+				test.name, tree.Size(), len(test.want))
+		}
+		if _, ok := checkAVL(tree.root); !ok {
+			t.Errorf("%s: InsertSortedStream() resulted in an invalid AVL tree:\n%s",
+				test.name, tree.toTestString())
+		}
+	}
+}
+
+func TestAVLInsertStaysBalanced(t *testing.T) {
+	const n = 1000
+
+	r := rand.New(rand.NewSource(42))
+	tests := []struct {
+		name string
+		vals []int
+	}{
+		{
+			name: "increasing",
+		},
+		{
+			name: "decreasing",
+		},
+		{
+			name: "random",
+			vals: r.Perm(n),
+		},
+	}
+	for i := 0; i < n; i++ {
+		tests[0].vals = append(tests[0].vals, i+1)
+		tests[1].vals = append(tests[1].vals, n-i)
+	}
+
+	for _, test := range tests {
+		tree := &AVL[int]{}
+		for _, v := range test.vals {
+			if !tree.Insert(v) {
+				t.Errorf("%s: Insert(%d) = false, want true", test.name, v)
+			}
+		}
+
+		// A balanced tree of n nodes is at most ~1.44 log2(n) tall.
+		if h := tree.Height(); h >= 15 {
+			t.Errorf("%s: Height() = %d, want < 15", test.name, h)
+		}
+		if _, ok := checkAVL(tree.root); !ok {
+			t.Errorf("%s: tree does not hold the AVL invariants", test.name)
+		}
+		if !checkAVLSizes(tree.root) {
+			t.Errorf("%s: subtree sizes do not match the tree", test.name)
+		}
+
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		if !sort.IntsAreSorted(got) || len(got) != n || tree.Size() != n {
+			t.Errorf("%s: tree holds %d values (Size %d), sorted %v, want %d sorted",
+				test.name, len(got), tree.Size(), sort.IntsAreSorted(got), n)
+		}
+	}
+}
+
+func TestAVLInsertRotatesRoot(t *testing.T) {
+	tests := []struct {
+		vals []int
+		want int
+	}{
+		{
+			// Right-Right at the root.
+			vals: []int{1, 2, 3},
+			want: 2,
+		},
+		{
+			// Left-Left at the root.
+			vals: []int{3, 2, 1},
+			want: 2,
+		},
+		{
+			// Right-Left at the root.
+			vals: []int{1, 3, 2},
+			want: 2,
+		},
+		{
+			// Left-Right at the root.
+			vals: []int{3, 1, 2},
+			want: 2,
+		},
+		{
+			// A deeper rotation at the root.
+			vals: []int{10, 5, 20, 30, 40, 50},
+			want: 30,
+		},
+	}
+
+	for _, test := range tests {
+		tree := &AVL[int]{}
+		for _, v := range test.vals {
+			tree.Insert(v)
+		}
+
+		if got := tree.root.Value(); got != test.want {
+			t.Errorf("after inserting %v, root = %d, want %d", test.vals, got, test.want)
+		}
+		if tree.root.parent != nil {
+			t.Errorf("after inserting %v, root has parent %d, want nil",
+				test.vals, tree.root.parent.value)
+		}
+		if _, ok := checkAVL(tree.root); !ok {
+			t.Errorf("after inserting %v, tree does not hold the AVL invariants", test.vals)
+		}
+	}
+}
+
+// checkAVLSizes reports if the tracked subtree size of every node in the
+// subtree matches the actual number of nodes below it.
+func checkAVLSizes[T constraints.Ordered](n *avlNode[T]) bool {
+	if n == nil {
+		return true
+	}
+	return n.size == n.Size() && checkAVLSizes(n.left) && checkAVLSizes(n.right)
+}
+
+// checkAVLHeights reports if the tracked height of every node in the subtree
+// matches the actual height of the subtree below it.
+func checkAVLHeights[T constraints.Ordered](n *avlNode[T]) bool {
+	if n == nil {
+		return true
+	}
+	return n.height == n.computeHeight() && checkAVLHeights(n.left) && checkAVLHeights(n.right)
+}
+
+func TestAVLCachedHeights(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	// Mix single inserts, which rotate, with the bulk inserts, which
+	// rebuild the tree, checking the heights after every step.
+	tree := &AVL[int]{}
+	for step := 0; step < 200; step++ {
+		switch step % 10 {
+		case 3:
+			vals := make([]int, 1+r.Intn(50))
+			for i := range vals {
+				vals[i] = r.Intn(10000)
+			}
+			tree.InsertAll(vals...)
+		case 7:
+			ch := make(chan int)
+			go func() {
+				last, _ := tree.Max()
+				for i := 0; i < 1+r.Intn(20); i++ {
+					last += 1 + r.Intn(5)
+					ch <- last
+				}
+				close(ch)
+			}()
+			tree.InsertSortedStream(ch)
+		default:
+			tree.Insert(r.Intn(10000))
+		}
+
+		if !checkAVLHeights(tree.root) {
+			t.Fatalf("after step %d, tracked heights do not match the tree", step)
+		}
+	}
+
+	if got, want := tree.Height(), tree.root.computeHeight(); got != want {
+		t.Errorf("Height() = %d, want %d", got, want)
+	}
+
+	// Reloaded trees get their heights back too.
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	var reloaded AVL[int]
+	if err := json.Unmarshal(data, &reloaded); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if !checkAVLHeights(reloaded.root) {
+		t.Errorf("tracked heights do not match the reloaded tree")
+	}
+}
+
+func TestAVLCachesAfterDeleteFunc(t *testing.T) {
+	// DeleteFunc rebuilds the tree, which must leave every cached size and
+	// height matching the nodes that remain, so that Size, Height and Select
+	// stay correct without a walk.
+	tree := &AVL[int]{}
+	for v := 1; v <= 200; v++ {
+		tree.Insert(v)
+	}
+
+	for _, m := range []int{7, 5, 3, 2} {
+		tree.DeleteFunc(func(v int) bool { return v%m == 0 })
+		if !checkAVLSizes(tree.root) {
+			t.Errorf("after removing multiples of %d, tracked sizes do not match the tree", m)
+		}
+		if !checkAVLHeights(tree.root) {
+			t.Errorf("after removing multiples of %d, tracked heights do not match the tree", m)
+		}
+		if got, want := tree.Height(), tree.root.computeHeight(); got != want {
+			t.Errorf("after removing multiples of %d, Height() = %d, want %d", m, got, want)
+		}
+		if got, want := tree.Size(), tree.root.Size(); got != want {
+			t.Errorf("after removing multiples of %d, Size() = %d, want %d", m, got, want)
+		}
+		if got, want := tree.MinDepth(), tree.Height()-2; got < want {
+			t.Errorf("after removing multiples of %d, MinDepth() = %d, want at least %d", m, got, want)
+		}
+	}
+}
+
+func TestAVLNodeParent(t *testing.T) {
+	tree := &AVL[int]{}
+	for v := 1; v <= 40; v++ {
+		tree.Insert(v)
+	}
+	root := tree.Root()
+	if p := root.Parent(); p != nil {
+		t.Fatalf("Parent() of the root = %v, want nil", p.Value())
+	}
+
+	// From every node, following Parent reaches the root, passing through
+	// each node on the path down to it.
+	tree.Walk(TraverseInOrder, func(v int) bool {
+		path, ok := binaryTreePath(root, v)
+		if !ok {
+			t.Fatalf("binaryTreePath(%d) not found", v)
+		}
+		node := path[len(path)-1]
+		for i := len(path) - 2; i >= 0; i-- {
+			node = node.Parent()
+			if node == nil {
+				t.Errorf("Parent() chain from %d ended at depth %d, want it to reach the root", v, i+1)
+				return true
+			}
+			if node.Value() != path[i].Value() {
+				t.Errorf("Parent() chain from %d reached %d at depth %d, want %d", v, node.Value(), i, path[i].Value())
+				return true
+			}
+		}
+		if p := node.Parent(); p != nil {
+			t.Errorf("Parent() chain from %d went past the root to %v", v, p.Value())
+		}
+		return true
+	})
+
+	// Nodes without parent pointers always give nil.
+	bst := newBSTWith(2, 1, 3)
+	if p := bst.Root().Left().Parent(); p != nil {
+		t.Errorf("BST Parent() = %v, want nil", p.Value())
+	}
+}
+
+func TestAVLPopMinMax(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	vals := r.Perm(300)
+	want := slices.Clone(vals)
+	slices.Sort(want)
+
+	// checkTree reports if the tree is still a valid AVL tree with its
+	// cached sizes and heights up to date.
+	checkTree := func(tree *AVL[int]) bool {
+		if tree.root != nil && tree.root.parent != nil {
+			return false
+		}
+		_, ok := checkAVL(tree.root)
+		return ok && checkAVLSizes(tree.root) && checkAVLHeights(tree.root) &&
+			tree.Size() == tree.root.Size()
+	}
+
+	tests := []struct {
+		name string
+		pop  func(*AVL[int]) (int, bool)
+		want []int
+	}{
+		{
+			name: "PopMin",
+			pop:  (*AVL[int]).PopMin,
+			want: want,
+		},
+		{
+			name: "PopMax",
+			pop:  (*AVL[int]).PopMax,
+			want: func() []int {
+				rev := slices.Clone(want)
+				slices.Reverse(rev)
+				return rev
+			}(),
+		},
+	}
+
+	for _, test := range tests {
+		tree := &AVL[int]{}
+		for _, v := range vals {
+			tree.Insert(v)
+		}
+
+		var got []int
+		for !tree.IsEmpty() {
+			v, ok := test.pop(tree)
+			if !ok {
+				t.Fatalf("%s() = _, false with %d values left", test.name, tree.Size())
+			}
+			got = append(got, v)
+			if !checkTree(tree) {
+				t.Fatalf("%s: after removing %d, the tree is not a valid AVL tree", test.name, v)
+			}
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: drained %v, want %v", test.name, got, test.want)
+		}
+		if v, ok := test.pop(tree); ok {
+			t.Errorf("%s() on an empty tree = %d, true, want false", test.name, v)
+		}
+	}
+
+	// Popping from both ends meets in the middle.
+	tree := &AVL[int]{}
+	tree.InsertAll(vals...)
+	for lo, hi := 0, len(want)-1; lo <= hi; lo, hi = lo+1, hi-1 {
+		if got, ok := tree.PopMin(); got != want[lo] || !ok {
+			t.Fatalf("PopMin() = %d, %v, want %d, true", got, ok, want[lo])
+		}
+		if lo == hi {
+			break
+		}
+		if got, ok := tree.PopMax(); got != want[hi] || !ok {
+			t.Fatalf("PopMax() = %d, %v, want %d, true", got, ok, want[hi])
+		}
+		if !checkTree(tree) {
+			t.Fatalf("after popping %d and %d, the tree is not a valid AVL tree", want[lo], want[hi])
+		}
+	}
+	if !tree.IsEmpty() {
+		t.Errorf("after popping every value, Size() = %d, want 0", tree.Size())
+	}
+
+	// Descending trees pop their first value in order, the largest.
+	desc := NewAVL[int](Descending()).(*AVL[int])
+	desc.InsertAll(3, 1, 4, 5, 9, 2, 6)
+	if got, ok := desc.PopMin(); got != 9 || !ok {
+		t.Errorf("Descending PopMin() = %d, %v, want 9, true", got, ok)
+	}
+	if got, ok := desc.PopMax(); got != 1 || !ok {
+		t.Errorf("Descending PopMax() = %d, %v, want 1, true", got, ok)
+	}
+}
+
+func TestAVLDelete(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	vals := r.Perm(300)
+
+	tree := &AVL[int]{}
+	tree.InsertAll(vals...)
+
+	// Delete every value in a different random order, which removes
+	// leaves, nodes with one child and nodes with two along the way.
+	remaining := slices.Clone(vals)
+	slices.Sort(remaining)
+	for _, v := range r.Perm(300) {
+		if !tree.Delete(v) {
+			t.Fatalf("Delete(%d) = false, want true", v)
+		}
+		if tree.Search(v) {
+			t.Fatalf("Search(%d) after Delete(%d) = true, want false", v, v)
+		}
+		i, _ := slices.BinarySearch(remaining, v)
+		remaining = slices.Delete(remaining, i, i+1)
+
+		if tree.root != nil && tree.root.parent != nil {
+			t.Fatalf("after Delete(%d), the root has a parent", v)
+		}
+		if _, ok := checkAVL(tree.root); !ok || !checkAVLSizes(tree.root) || !checkAVLHeights(tree.root) {
+			t.Fatalf("after Delete(%d), the tree is not a valid AVL tree", v)
+		}
+		if got := tree.Size(); got != len(remaining) {
+			t.Fatalf("Size() after Delete(%d) = %d, want %d", v, got, len(remaining))
+		}
+		if got := ToSlice[int](tree); !slices.Equal(got, remaining) {
+			t.Fatalf("after Delete(%d), the tree holds %v, want %v", v, got, remaining)
+		}
+	}
+	if !tree.IsEmpty() {
+		t.Errorf("after deleting every value, Size() = %d, want 0", tree.Size())
+	}
+	if tree.Delete(1) {
+		t.Errorf("Delete(1) on an empty tree = true, want false")
+	}
+
+	// Trees keeping duplicates remove one copy at a time, and Descending
+	// trees find the value by their own ordering.
+	multi := NewAVL[int](Descending(), IgnoreDuplicates(false)).(*AVL[int])
+	multi.InsertAll(5, 3, 5, 8, 5, 1)
+	if !multi.Delete(5) || multi.Count(5) != 2 || multi.Size() != 5 {
+		t.Errorf("Delete(5) left Count(5) = %d, Size() = %d, want 2, 5", multi.Count(5), multi.Size())
+	}
+	if got, want := ToSlice[int](multi), []int{8, 5, 5, 3, 1}; !slices.Equal(got, want) {
+		t.Errorf("after Delete(5), the descending tree holds %v, want %v", got, want)
+	}
+}
+
+func TestNewAVLFromSorted(t *testing.T) {
+	tests := []struct {
+		vals []int
+		want []int
+	}{
+		{
+			vals: nil,
+			want: nil,
+		},
+		{
+			vals: []int{7},
+			want: []int{7},
+		},
+		{
+			vals: []int{1, 2, 3, 4, 5, 6, 7, 8},
+			want: []int{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		{
+			// Duplicates are collapsed.
+			vals: []int{-3, -3, 0, 2, 2, 2, 5, 8, 8, 13},
+			want: []int{-3, 0, 2, 5, 8, 13},
+		},
+	}
+
+	for _, test := range tests {
+		tree := NewAVLFromSorted(test.vals)
+
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("NewAVLFromSorted(%v) in order = %v, want %v", test.vals, got, test.want)
+		}
+		if got := tree.Size(); got != len(test.want) {
+			t.Errorf("NewAVLFromSorted(%v).Size() = %d, want %d", test.vals, got, len(test.want))
+		}
+		if got, want := tree.Height(), minHeight(len(test.want)); got != want {
+			t.Errorf("NewAVLFromSorted(%v).Height() = %d, want %d", test.vals, got, want)
+		}
+		if _, ok := checkAVL(tree.root); !ok {
+			t.Errorf("NewAVLFromSorted(%v) does not hold the AVL invariants", test.vals)
+		}
+		if !checkAVLSizes(tree.root) {
+			t.Errorf("NewAVLFromSorted(%v) has incorrect subtree sizes", test.vals)
+		}
+
+		// The tree should carry on working as usual.
+		if !tree.Insert(100) || !tree.Search(100) {
+			t.Errorf("NewAVLFromSorted(%v).Insert(100) failed", test.vals)
+		}
+	}
+}
+
+func TestAVLSelectRank(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	// Use only even values so there are gaps to test absent keys.
+	seen := map[int]bool{}
+	var vals []int
+	for len(vals) < 500 {
+		v := 2 * r.Intn(5000)
+		if !seen[v] {
+			seen[v] = true
+			vals = append(vals, v)
+		}
+	}
+	sort.Ints(vals)
+
+	ch := make(chan int)
+	go func() {
+		for _, v := range vals {
+			ch <- v
+		}
+		close(ch)
+	}()
+	tree := &AVL[int]{}
+	tree.InsertSortedStream(ch)
+
+	if !checkAVLSizes(tree.root) {
+		t.Fatalf("subtree sizes do not match the tree after InsertSortedStream")
+	}
+
+	for k, want := range vals {
+		if got, ok := tree.Select(k); got != want || !ok {
+			t.Errorf("Select(%d) = %v, %v, want %v, true", k, got, ok, want)
+		}
+		// Present keys have the rank of their position.
+		if got := tree.Rank(want); got != k {
+			t.Errorf("Rank(%d) = %d, want %d", want, got, k)
+		}
+		// Absent keys just above a present one rank after it.
+		if got := tree.Rank(want + 1); got != k+1 {
+			t.Errorf("Rank(%d) = %d, want %d", want+1, got, k+1)
+		}
+	}
+
+	for _, k := range []int{-1, len(vals), len(vals) + 10} {
+		if _, ok := tree.Select(k); ok {
+			t.Errorf("Select(%d) = _, true, want false", k)
+		}
+	}
+	if got := tree.Rank(-1); got != 0 {
+		t.Errorf("Rank(-1) = %d, want 0", got)
+	}
+	if got := tree.Rank(1 << 20); got != len(vals) {
+		t.Errorf("Rank(%d) = %d, want %d", 1<<20, got, len(vals))
+	}
+
+	// Sizes must stay right through individual inserts and the rotations
+	// they trigger, with Select following the in order values.
+	for i := 0; i < 100; i++ {
+		tree.Insert(2*r.Intn(5000) + 1)
+	}
+	if !checkAVLSizes(tree.root) {
+		t.Fatalf("subtree sizes do not match the tree after Insert")
+	}
+	var inOrder []int
+	InOrderInto(tree.Root(), &inOrder)
+	for k, want := range inOrder {
+		if got, ok := tree.Select(k); got != want || !ok {
+			t.Errorf("after Insert Select(%d) = %v, %v, want %v, true", k, got, ok, want)
+		}
+	}
+
+	// The empty tree has nothing to select.
+	empty := &AVL[int]{}
+	if _, ok := empty.Select(0); ok {
+		t.Errorf("Select(0) on an empty tree = _, true, want false")
+	}
+	if got := empty.Rank(5); got != 0 {
+		t.Errorf("Rank(5) on an empty tree = %d, want 0", got)
+	}
+}
+
+// BenchmarkAVLInsertSorted compares inserting already sorted values one at a
+// time against InsertSortedStream, reporting the rotations performed for each.
+func BenchmarkAVLInsertSorted(b *testing.B) {
+	var rotations int
+	avlRotationHook = func() { rotations++ }
+	defer func() { avlRotationHook = nil }()
+
+	for _, n := range insertSteps {
+		// Skip any tests that are outside the limit.
+		if n > *treeSizeUpperLimit {
+			break
+		}
+
+		b.Run(fmt.Sprintf("Insert-%06d", n), func(b *testing.B) {
+			rotations = 0
+			for i := 0; i < b.N; i++ {
+				tree := &AVL[int]{}
+				for v := 0; v < n; v++ {
+					tree.Insert(v)
+				}
+			}
+			b.ReportMetric(float64(rotations)/float64(b.N), "rotations/op")
+		})
+
+		b.Run(fmt.Sprintf("InsertSortedStream-%06d", n), func(b *testing.B) {
+			rotations = 0
+			for i := 0; i < b.N; i++ {
+				tree := &AVL[int]{}
+				ch := make(chan int, 64)
+				go func() {
+					for v := 0; v < n; v++ {
+						ch <- v
+					}
+					close(ch)
+				}()
+				tree.InsertSortedStream(ch)
+			}
+			b.ReportMetric(float64(rotations)/float64(b.N), "rotations/op")
+		})
+	}
+}
+
+// BenchmarkAVLInsertHeights compares filling a tree of n random values with
+// the heights tracked on the nodes against the work insert did before they
+// were, when the balance factor of every node on the path to the new value
+// was found by walking both of its subtrees. That cost grows with the size
+// of the tree, so only smaller trees are run.
+func BenchmarkAVLInsertHeights(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		// Skip any tests that are outside the limit.
+		if n > *treeSizeUpperLimit {
+			break
+		}
+		vals := testIntVals[:n]
+
+		b.Run(fmt.Sprintf("Tracked-%06d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree := &AVL[int]{}
+				for _, v := range vals {
+					tree.Insert(v)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("Computed-%06d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree := &AVL[int]{}
+				for _, v := range vals {
+					tree.Insert(v)
+					for x := tree.root; x != nil && x.value != v; {
+						x.bf = x.right.computeHeight() - x.left.computeHeight()
+						if v < x.value {
+							x = x.left
+						} else {
+							x = x.right
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkAVLHeight compares reading the tracked height of a tree with
+// walking the whole tree to find it.
+func BenchmarkAVLHeight(b *testing.B) {
+	for _, n := range insertSteps {
+		// Skip any tests that are outside the limit.
+		if n > *treeSizeUpperLimit {
+			break
+		}
+
+		tree := &AVL[int]{}
+		for v := 0; v < n; v++ {
+			tree.Insert(v)
+		}
+
+		b.Run(fmt.Sprintf("Tracked-%06d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree.Height()
+			}
+		})
+
+		b.Run(fmt.Sprintf("Computed-%06d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree.root.computeHeight()
+			}
+		})
+	}
 }
@@ -1,16 +1,155 @@
 package tree
 
-import "golang.org/x/exp/constraints"
+import (
+	"math/bits"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
 
 // BST is the simplest binary tree type. A node value and left and right
 // pointers. No balancing or shuffling.
-type BST[T constraints.Ordered] struct {
+type BST[T any] struct {
 	root *bstNode[T]
+
+	// size is the count of values in the tree, kept up to date by the
+	// Insert and Delete methods.
+	size int
+
+	// order defines how the values in the tree are arranged.
+	order ordering[T]
+
+	// rebalanceFactor, if not zero, is how many times taller than a
+	// balanced tree of the same size this tree may grow before it is
+	// rebuilt in balanced shape.
+	rebalanceFactor float64
 }
 
 // NewBST returns an empty BST tree ready to use.
-func NewBST[T constraints.Ordered]() Tree[T] {
-	return &BST[T]{}
+//
+// Options can include Descending to order the values largest first,
+// IgnoreDuplicates(false) to keep every copy of equal values inserted, and
+// AutoRebalanceThreshold to rebuild the tree when it grows too tall.
+func NewBST[T constraints.Ordered](opts ...treeOptionFunc) Tree[T] {
+	treeOpts := defaultOptions()
+	for _, opt := range opts {
+		opt(treeOpts)
+	}
+
+	return &BST[T]{
+		order:           newOrdering[T](treeOpts),
+		rebalanceFactor: treeOpts.rebalanceFactor,
+	}
+}
+
+// NewBSTFunc returns an empty BST tree ready to use, with its values ordered
+// by the given comparison function instead of their natural order. cmp must
+// return a negative number when a is less than b, a positive number when a
+// is greater than b, and zero when they are equal, as cmp.Compare does.
+//
+// This allows values without a natural order, such as structs, or values
+// needing a custom order, such as case insensitive strings, to be stored.
+func NewBSTFunc[T any](cmp func(a, b T) int) Tree[T] {
+	return &BST[T]{order: ordering[T]{cmp: cmp}}
+}
+
+// NewBSTFromSorted returns a BST holding the given values, which must be in
+// ascending order, with the nodes arranged into a height balanced shape. This
+// takes O(n) time, rather than the O(n log n) of inserting them one at a time,
+// which for sorted values would also leave a plain BST degenerate.
+//
+// Repeated values are only stored once.
+func NewBSTFromSorted[T constraints.Ordered](vals []T) *BST[T] {
+	return newBSTFromSorted(slices.Compact(slices.Clone(vals)))
+}
+
+// newBSTFromSorted returns a BST holding the given values, which must be in
+// sorted order, with the nodes arranged into a balanced shape.
+func newBSTFromSorted[T any](vals []T) *BST[T] {
+	return &BST[T]{
+		root:  bstNodeFromSorted(vals),
+		size:  len(vals),
+		order: ordering[T]{}.resolve(),
+	}
+}
+
+// NewBSTFromSortedSeq returns a BST holding the values produced by seq, which
+// must be strictly increasing, with the nodes arranged into a balanced shape.
+// seq has the same type as iter.Seq[T], so any such iterator may be passed.
+//
+// The tree is built in a single pass as values arrive, without knowing how
+// many there will be and without holding a copy of them, making this suited
+// for inputs too large to first gather into a slice. Beyond the nodes
+// themselves, only O(log n) memory is used.
+//
+// If a value is not larger than the one before it, iteration stops and false
+// is returned.
+func NewBSTFromSortedSeq[T constraints.Ordered](seq func(yield func(T) bool)) (Tree[T], bool) {
+	var b bstSeqBuilder[T]
+	ok := true
+	seq(func(v T) bool {
+		ok = b.add(v)
+		return ok
+	})
+	if !ok {
+		return nil, false
+	}
+
+	root, size := b.finish()
+	return &BST[T]{
+		root:  root,
+		size:  size,
+		order: ordering[T]{}.resolve(),
+	}, true
+}
+
+// NewBSTFromLevelOrder returns a BST holding the given values, inserted one at
+// a time in the order given rather than sorted first. Each value settles below
+// the ones before it, so listing the values of a tree breadth first, as Walk
+// does with TraverseLevelOrder, reproduces that exact shape. Unlike
+// NewBSTFromSorted, no balancing is done, so values in ascending order give a
+// degenerate tree.
+//
+// Repeated values are only stored once.
+func NewBSTFromLevelOrder[T constraints.Ordered](vals []T) *BST[T] {
+	t := &BST[T]{order: ordering[T]{}.resolve()}
+	for _, v := range vals {
+		t.Insert(v)
+	}
+	return t
+}
+
+// FromLevelArray returns a BST with the shape and values given in the array
+// form of ToLevelArray. The shape is taken as given, so the values must
+// already be in binary search tree order for the tree to work as one. See
+// IsValidBST.
+//
+// Entries below a nil entry have no parent to hang from and are ignored.
+func FromLevelArray[T constraints.Ordered](arr []*T) *BST[T] {
+	t := &BST[T]{order: ordering[T]{}.resolve()}
+	nodes := make([]*bstNode[T], len(arr))
+	for i, v := range arr {
+		if v == nil {
+			continue
+		}
+		node := &bstNode[T]{value: *v}
+		if i == 0 {
+			t.root = node
+		} else {
+			parent := nodes[(i-1)/2]
+			switch {
+			case parent == nil:
+				continue
+			case i%2 == 1:
+				parent.left = node
+			default:
+				parent.right = node
+			}
+		}
+		nodes[i] = node
+		t.size++
+	}
+	return t
 }
 
 // Root returns the root node of the tree.
@@ -21,23 +160,142 @@ func (t *BST[T]) Root() BinaryTree[T] {
 // Insert inserts the node into the tree, growing as needed, and reports
 // if the operation was successful.
 func (t *BST[T]) Insert(v T) bool {
+	return t.InsertErr(v) == nil
+}
+
+// InsertErr inserts the node into the tree, growing as needed. If the value
+// is already in the tree, and the tree does not keep duplicates, ErrDuplicate
+// is returned.
+func (t *BST[T]) InsertErr(v T) error {
 	if t.root == nil {
 		t.root = &bstNode[T]{
 			value: v,
 		}
-		return true
+		t.size++
+		return nil
+	}
+	depth, ok := t.root.insert(v, t.order.resolve())
+	if !ok {
+		return ErrDuplicate
+	}
+	t.size++
+	t.checkHeight(depth + 1)
+	return nil
+}
+
+// checkHeight rebuilds the tree in balanced shape if the path down to the
+// value just inserted, holding the given number of nodes, has grown past the
+// limit set by AutoRebalanceThreshold.
+//
+// Only the new path needs checking, as every other path was within the limit
+// before, and the limit only rises as the tree grows.
+func (t *BST[T]) checkHeight(height int) {
+	if t.rebalanceFactor == 0 {
+		return
+	}
+	// bits.Len gives the height of a balanced tree of this size, which is
+	// log2(size) rounded up, so a freshly rebuilt tree is never over the
+	// limit even when the factor is 1.
+	if float64(height) <= t.rebalanceFactor*float64(bits.Len(uint(t.size))) {
+		return
+	}
+
+	vals := make([]T, 0, t.size)
+	InOrderInto[T](t.root, &vals)
+	t.root = bstNodeFromSorted(vals)
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+func (t *BST[T]) InsertAll(vals ...T) int {
+	var n int
+	for _, v := range vals {
+		if t.Insert(v) {
+			n++
+		}
 	}
-	return t.root.Insert(v)
+	return n
 }
 
 // Delete the requested node from the tree and reports if it was successful.
 // If the value is not in the tree, the tree is unchanged and false is returned.
 // If the node is not a leaf the trees internal structure may be updated.
 func (t *BST[T]) Delete(v T) bool {
+	return t.DeleteErr(v) == nil
+}
+
+// DeleteErr removes the requested node from the tree. If the tree is empty
+// ErrEmptyTree is returned, and if the value is not in the tree ErrNotFound
+// is returned, leaving the tree unchanged.
+//
+// If the tree keeps duplicates, only one copy of the value is removed.
+func (t *BST[T]) DeleteErr(v T) error {
 	if t.root == nil {
-		return false
+		return ErrEmptyTree
+	}
+	root, ok := t.root.remove(v, t.order.resolve())
+	if !ok {
+		return ErrNotFound
+	}
+	t.root = root
+	t.size--
+	return nil
+}
+
+// DeleteOne removes a single copy of v from the tree, leaving any other copies
+// in place, and reports if one was removed. It is the same as Delete, named
+// for clarity alongside DeleteAll in trees that keep duplicates.
+func (t *BST[T]) DeleteOne(v T) bool {
+	return t.Delete(v)
+}
+
+// DeleteAll removes every copy of v from the tree, returning the number of
+// copies removed. Use Count to find how many there are without removing them.
+//
+// Unlike a Treap, which counts the copies in one node, this tree keeps each
+// copy in a node of its own, as they may be rotated apart, so removing k
+// copies takes O(k log n) time.
+func (t *BST[T]) DeleteAll(v T) int {
+	var n int
+	for t.Delete(v) {
+		n++
+	}
+	return n
+}
+
+// Count returns the number of copies of v in the tree. Unless the tree was
+// created to keep duplicates, this is at most one.
+func (t *BST[T]) Count(v T) int {
+	if t.root == nil {
+		return 0
+	}
+	return countValue[T](t.root, v, t.order.resolve())
+}
+
+// CountFunc returns the number of values in the tree for which pred returns
+// true. Every value is checked, but none are collected along the way.
+func (t *BST[T]) CountFunc(pred func(T) bool) int {
+	if t.root == nil {
+		return 0
+	}
+	return countMatching[T](t.root, pred)
+}
+
+// DeleteFunc removes every value in the tree for which pred returns true,
+// returning the number of values removed. The values that are kept are
+// collected and rebuilt into a balanced tree, so this takes O(n) time however
+// many are removed.
+func (t *BST[T]) DeleteFunc(pred func(T) bool) int {
+	if t.root == nil {
+		return 0
 	}
-	return t.root.Delete(v)
+	kept, removed := valuesExcept[T](t.root, pred, make([]T, 0, t.size))
+	if removed == 0 {
+		return 0
+	}
+	t.root = bstNodeFromSorted(kept)
+	t.size = len(kept)
+	return removed
 }
 
 // Search reports if the given value is in the tree.
@@ -45,21 +303,156 @@ func (t *BST[T]) Search(v T) bool {
 	if t.root == nil {
 		return false
 	}
-	return t.root.Search(v)
+	return t.root.search(v, t.order.resolve())
 }
 
-// Walk traverse the tree in the specified order emitting the values to
+// Traverse traverses the tree in the specified order emitting the values to
 // the channel. Channel is closed once the final value is emitted.
 func (t *BST[T]) Traverse(tOrder TraverseOrder) <-chan T {
 	ch := make(chan T)
 	go func() {
-		traverseBinaryTree(t.root, tOrder, ch)
+		if !t.IsEmpty() {
+			traverseBinaryTree(t.root, tOrder, ch)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// TraverseBuffered is like Traverse, but the channel holds up to bufSize values,
+// letting the traversal run ahead of the reader instead of handing over each
+// value in turn. This gives better throughput when the reader does little
+// work per value. A bufSize below 1 gives an unbuffered channel, as Traverse.
+func (t *BST[T]) TraverseBuffered(tOrder TraverseOrder, bufSize int) <-chan T {
+	ch := make(chan T, max(bufSize, 0))
+	go func() {
+		if t.root != nil {
+			traverseBinaryTree[T](t.root, tOrder, ch)
+		}
 		close(ch)
 	}()
 
 	return ch
 }
 
+// Get returns the value stored in the tree that is equal to v. If there is
+// no such value, false is returned.
+func (t *BST[T]) Get(v T) (T, bool) {
+	var zero T
+	o := t.order.resolve()
+	for n := t.root; n != nil; {
+		c := o.compare(v, n.value)
+		if c == 0 {
+			return n.value, true
+		}
+		if c < 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return zero, false
+}
+
+// Walk calls visit with each value of the tree in the given order, stopping
+// as soon as visit returns false. It reports if every value was visited.
+//
+// Unlike Traverse, no goroutine or channel is involved, so stopping early
+// leaves nothing behind.
+func (t *BST[T]) Walk(tOrder TraverseOrder, visit func(T) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	return walkBinaryTree[T](t.root, tOrder, visit)
+}
+
+// AppendValues appends the values of the tree in the given order onto dst and
+// returns the extended slice, in the manner of append. Reusing dst across
+// calls saves allocating a new slice each time as ToSlice does.
+func (t *BST[T]) AppendValues(dst []T, tOrder TraverseOrder) []T {
+	if t.root == nil {
+		return dst
+	}
+	return appendValues[T](dst, t.root, tOrder)
+}
+
+// TraverseSlice returns the values of the tree in the given order as a slice.
+// Unlike Traverse, no goroutine or channel is used, which for small trees
+// costs more than the walk itself, and nothing is left running if the caller
+// stops reading early. Use Traverse to stream the values of large trees.
+func (t *BST[T]) TraverseSlice(tOrder TraverseOrder) []T {
+	if t.root == nil {
+		return nil
+	}
+	return appendValues[T](make([]T, 0, t.size), t.root, tOrder)
+}
+
+// At returns the value at the given 0-based position in order, i.e., At(0)
+// is the smallest value, or the largest if the tree is Descending. The values
+// are counted off from the start, stopping once the index is reached, so this
+// takes O(index) time. If index is out of range, false is returned.
+func (t *BST[T]) At(index int) (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return nthValue[T](t.root, TraverseInOrder, index)
+}
+
+// SelectDescending returns the k-th largest value (0-indexed), or the k-th
+// smallest if the tree is Descending. The values are counted off in reverse
+// order, taking O(k) time. If k is out of range, false is returned.
+func (t *BST[T]) SelectDescending(k int) (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return nthValue[T](t.root, TraverseReverseOrder, k)
+}
+
+// MorrisInOrder returns the values of the tree in order, found with a Morris
+// traversal that needs no stack, recursion or goroutine, only O(1) space
+// beyond the returned slice. The tree is temporarily rethreaded along the way,
+// but left exactly as it was, so it must not be read by anything else while
+// this runs.
+func (t *BST[T]) MorrisInOrder() []T {
+	if t.root == nil {
+		return nil
+	}
+	return morrisInOrder(t.root, (*bstNode[T]).childFields, (*bstNode[T]).Value, make([]T, 0, t.size))
+}
+
+// LeafCount returns the number of nodes in the tree with no children.
+func (t *BST[T]) LeafCount() int {
+	if t.root == nil {
+		return 0
+	}
+	leaves, _ := countNodeKinds[T](t.root)
+	return leaves
+}
+
+// InternalCount returns the number of nodes in the tree with at least one
+// child, which is every node that is not a leaf.
+func (t *BST[T]) InternalCount() int {
+	if t.root == nil {
+		return 0
+	}
+	_, internal := countNodeKinds[T](t.root)
+	return internal
+}
+
+// Boundary returns the values around the edge of the tree, going
+// anti-clockwise from the root: the root, the left boundary from the top
+// down, every leaf from left to right, and then the right boundary from the
+// bottom up. Values where the boundaries meet are only included once.
+func (t *BST[T]) Boundary() []T {
+	if t.root == nil {
+		return nil
+	}
+	return boundaryValues[T](t.root)
+}
+
 // Height returns the height of the longest path in the tree from the
 // root node to the farthest leaf.
 func (t *BST[T]) Height() int {
@@ -68,3 +461,343 @@ func (t *BST[T]) Height() int {
 	}
 	return t.root.Height()
 }
+
+// MinDepth returns the number of edges from the root to the nearest leaf,
+// found with a breadth first search that stops at the first leaf reached. Set
+// against Height, it shows how lopsided the tree is. An empty tree, like one
+// with only a root, returns 0.
+func (t *BST[T]) MinDepth() int {
+	if t.root == nil {
+		return 0
+	}
+	return minLeafDepth[T](t.root)
+}
+
+// BalanceInfo returns the Height, MinDepth and Size of the tree together with
+// its SkewRatio, to show at a glance how far from balanced the tree is.
+func (t *BST[T]) BalanceInfo() BalanceInfo {
+	return newBalanceInfo(t.Height(), t.MinDepth(), t.size)
+}
+
+// Diameter returns the number of nodes on the longest path between any two
+// leaves of the tree, which may or may not pass through the root.
+func (t *BST[T]) Diameter() int {
+	if t.root == nil {
+		return 0
+	}
+	_, d := binaryTreeDiameter[T](t.root)
+	return d
+}
+
+// Size returns the number of values held in the tree.
+func (t *BST[T]) Size() int {
+	return t.size
+}
+
+// IsEmpty reports if the tree holds no values.
+func (t *BST[T]) IsEmpty() bool {
+	return t.root == nil
+}
+
+// String returns the values of the tree indented one level per depth, with
+// the root first and each child after its parent, for use when printing the
+// tree. Large trees are cut short after the first few dozen nodes.
+func (t *BST[T]) String() string {
+	return treeString[T](t.root, t.size)
+}
+
+// Min returns the smallest value in the tree, or the largest if the tree is
+// Descending. If the tree is empty, false is returned.
+func (t *BST[T]) Min() (T, bool) {
+	var zero T
+	if t.root == nil {
+		return zero, false
+	}
+
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+	return n.value, true
+}
+
+// Max returns the largest value in the tree, or the smallest if the tree is
+// Descending. If the tree is empty, false is returned.
+func (t *BST[T]) Max() (T, bool) {
+	var zero T
+	if t.root == nil {
+		return zero, false
+	}
+
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.value, true
+}
+
+// Predecessor returns the value in the tree that comes before v in order. If v
+// is not in the tree, or is the first value, false is returned.
+func (t *BST[T]) Predecessor(v T) (T, bool) {
+	var zero T
+
+	// BST nodes have no parent pointers, so track the nearest preceding
+	// ancestor on the way down.
+	var candidate *bstNode[T]
+	o := t.order.resolve()
+	n := t.root
+	for n != nil && o.compare(v, n.value) != 0 {
+		if o.compare(v, n.value) < 0 {
+			n = n.left
+		} else {
+			candidate = n
+			n = n.right
+		}
+	}
+	if n == nil {
+		return zero, false
+	}
+
+	// The last value in the left subtree comes immediately before.
+	if n.left != nil {
+		n = n.left
+		for n.right != nil {
+			n = n.right
+		}
+		return n.value, true
+	}
+
+	if candidate == nil {
+		return zero, false
+	}
+	return candidate.value, true
+}
+
+// Successor returns the value in the tree that comes after v in order. If v
+// is not in the tree, or is the last value, false is returned.
+func (t *BST[T]) Successor(v T) (T, bool) {
+	var zero T
+
+	// BST nodes have no parent pointers, so track the nearest following
+	// ancestor on the way down.
+	var candidate *bstNode[T]
+	o := t.order.resolve()
+	n := t.root
+	for n != nil && o.compare(v, n.value) != 0 {
+		if o.compare(v, n.value) < 0 {
+			candidate = n
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	if n == nil {
+		return zero, false
+	}
+
+	// The first value in the right subtree comes immediately after.
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n.value, true
+	}
+
+	if candidate == nil {
+		return zero, false
+	}
+	return candidate.value, true
+}
+
+// LCA returns the lowest common ancestor of a and b, the value of the deepest
+// node that has both of them in its subtree. If one value is an ancestor of
+// the other, it is the lowest common ancestor. If either value is not in the
+// tree, false is returned.
+func (t *BST[T]) LCA(a, b T) (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return lowestCommonAncestor[T](t.root, a, b, t.order.resolve())
+}
+
+// PathTo returns the values from the root of the tree down to the node
+// holding v, in the order a search for v visits them. The path starts with
+// the root and ends with v. If v is not in the tree, false is returned.
+func (t *BST[T]) PathTo(v T) ([]T, bool) {
+	if t.root == nil {
+		return nil, false
+	}
+	return valuePath[T](t.root, v, t.order.resolve())
+}
+
+// Ancestors returns the values of the nodes above v in the tree, from its
+// parent up to the root, which is PathTo reversed and without v. The root has
+// no ancestors, giving an empty slice. If v is not in the tree, false is
+// returned.
+func (t *BST[T]) Ancestors(v T) ([]T, bool) {
+	if t.root == nil {
+		return nil, false
+	}
+	return valueAncestors[T](t.root, v, t.order.resolve())
+}
+
+// Depth returns the number of edges between the root of the tree and the
+// node holding v, with the root at depth 0. If v is not in the tree, false
+// is returned.
+func (t *BST[T]) Depth(v T) (int, bool) {
+	if t.root == nil {
+		return 0, false
+	}
+	return valueDepth[T](t.root, v, t.order.resolve())
+}
+
+// SameSubtree reports if a and b are both in the tree and lie on the same
+// side of the root, either both in its left subtree or both in its right. If
+// either value is missing, or is the root itself, false is returned.
+func (t *BST[T]) SameSubtree(a, b T) bool {
+	if t.root == nil {
+		return false
+	}
+	return sameSubtree[T](t.root, a, b, t.order.resolve())
+}
+
+// From returns an iterator over the values of the tree in the given order,
+// starting at v rather than at the start of the tree. In order, the first
+// value is the first one not before v, and in reverse order it is the last one
+// not after v, so v need not be in the tree. Only the search path for v is
+// looked at before the first value is yielded, making it cheap to scan a few
+// values from a point in a large tree.
+//
+// The other orders have no position to start from, and yield nothing.
+func (t *BST[T]) From(v T, tOrder TraverseOrder) func(yield func(T) bool) {
+	return valuesFrom[T](t.root, v, tOrder, t.order.resolve())
+}
+
+// RangeSearch returns the values v in the tree with lo <= v <= hi, in the
+// order of the tree. Subtrees that can not hold values in the range are not
+// visited, making this much cheaper than a full traversal for narrow ranges.
+func (t *BST[T]) RangeSearch(lo, hi T) []T {
+	return t.rangeSearch(lo, hi, nil)
+}
+
+// rangeSearch is the worker for RangeSearch, calling visit, if not nil, on
+// each node looked at.
+func (t *BST[T]) rangeSearch(lo, hi T, visit func(BinaryTree[T])) []T {
+	o := t.order.resolve()
+	if t.root == nil || o.cmp(hi, lo) < 0 {
+		return nil
+	}
+
+	var out []T
+	rangeAppend(t.Root(), lo, hi, o, visit, &out)
+	return out
+}
+
+// RecoverBST repairs a BST in which exactly two nodes have had their values
+// swapped, breaking the ordering of the tree. The misplaced nodes are found
+// with an in order scan and their values swapped back.
+//
+// Reports if a repair was made. If the tree is already in order, or is out of
+// order in a way that a single swap can't explain, the tree is unchanged and
+// false is returned.
+func RecoverBST[T any](t *BST[T]) bool {
+	if t == nil || t.root == nil {
+		return false
+	}
+
+	o := t.order.resolve()
+	var prev, first, second *bstNode[T]
+	var inversions int
+	t.root.walkInOrder(func(n *bstNode[T]) {
+		if prev != nil && o.compare(n.value, prev.value) < 0 {
+			inversions++
+			// The first out of order pair gives the first misplaced node.
+			// The second node is the latter of the final inversion. If the
+			// swapped nodes were adjacent there is only one inversion.
+			if first == nil {
+				first = prev
+			}
+			second = n
+		}
+		prev = n
+	})
+
+	if first == nil || inversions > 2 {
+		return false
+	}
+
+	first.value, second.value = second.value, first.value
+	return true
+}
+
+// BalanceDSW rebalances the BST in place using the Day-Stout-Warren
+// algorithm. The tree is first unwound by right rotations into a vine, a
+// chain of right children in order, which is then folded back up by rounds
+// of left rotations into a complete tree of minimal height, with the bottom
+// level filled from the left.
+//
+// This takes O(n) time like Rebalance, but only O(1) extra space, as the
+// existing nodes are rotated into place rather than the values being
+// collected and built into new ones.
+func BalanceDSW[T any](t *BST[T]) {
+	if t == nil || t.root == nil {
+		return
+	}
+
+	// A pseudo root above the real one lets the rotations at the top of the
+	// tree work just like those below it.
+	pseudo := &bstNode[T]{right: t.root}
+	size := pseudo.treeToVine()
+
+	// Fold the nodes that will be the partial bottom level first, leaving
+	// a vine that folds evenly into a perfect tree.
+	full := 1
+	for full*2 <= size+1 {
+		full *= 2
+	}
+	pseudo.compressVine(size + 1 - full)
+	for size = full - 1; size > 1; size /= 2 {
+		pseudo.compressVine(size / 2)
+	}
+
+	t.root = pseudo.right
+}
+
+// treeToVine rotates the tree to the right of this node into a vine, with
+// every node the right child of the one before it in order, returning the
+// number of nodes in the vine.
+func (t *bstNode[T]) treeToVine() int {
+	var size int
+	tail := t
+	for rest := tail.right; rest != nil; {
+		if rest.left == nil {
+			tail = rest
+			rest = rest.right
+			size++
+			continue
+		}
+		// Rotate the left child up in place of rest.
+		l := rest.left
+		rest.left = l.right
+		l.right = rest
+		rest = l
+		tail.right = l
+	}
+	return size
+}
+
+// compressVine makes count left rotations down the vine to the right of this
+// node, at every other node, halving the length of the vine by moving each
+// node skipped over down to be the left child of the one after it.
+func (t *bstNode[T]) compressVine(count int) {
+	scanner := t
+	for i := 0; i < count; i++ {
+		child := scanner.right
+		scanner.right = child.right
+		scanner = scanner.right
+		child.right = scanner.left
+		scanner.left = child
+	}
+}
@@ -3,13 +3,89 @@ package tree
 import "golang.org/x/exp/constraints"
 
 // bstNode is the basic node in a binary search tree.
-type bstNode[T constraints.Ordered] struct {
+type bstNode[T any] struct {
 	value T
 
 	// The two children nodes.
 	left, right *bstNode[T]
 }
 
+// bstNodeFromSorted builds a balanced subtree from the given sorted values by
+// recursively using the middle value as the root of each subtree.
+func bstNodeFromSorted[T any](vals []T) *bstNode[T] {
+	if len(vals) == 0 {
+		return nil
+	}
+
+	mid := len(vals) / 2
+	return &bstNode[T]{
+		value: vals[mid],
+		left:  bstNodeFromSorted(vals[:mid]),
+		right: bstNodeFromSorted(vals[mid+1:]),
+	}
+}
+
+// bstSeqBuilder incrementally builds a balanced subtree from values arriving
+// in increasing order.
+//
+// Values alternate between becoming leaves and becoming the roots of the
+// perfect subtrees completed so far, much like carrying in a binary counter.
+// Roots still waiting on their right subtree are kept on a stack down the
+// right spine of the tree, with heights strictly decreasing toward the top.
+type bstSeqBuilder[T constraints.Ordered] struct {
+	// pending are nodes with a complete left subtree of the matching height
+	// in heights, waiting for a right subtree of the same height.
+	pending []*bstNode[T]
+	heights []int
+
+	// carry is a completed perfect subtree of height carryHeight waiting for
+	// the next value to become its parent.
+	carry       *bstNode[T]
+	carryHeight int
+
+	last T
+	size int
+}
+
+// add adds the next value to the tree under construction. It reports false
+// if v is not larger than the previous value.
+func (b *bstSeqBuilder[T]) add(v T) bool {
+	if b.size > 0 && v <= b.last {
+		return false
+	}
+	b.last = v
+	b.size++
+
+	// The next value becomes the parent of the carried subtree.
+	if b.carry != nil {
+		b.pending = append(b.pending, &bstNode[T]{value: v, left: b.carry})
+		b.heights = append(b.heights, b.carryHeight)
+		b.carry = nil
+		return true
+	}
+
+	// Otherwise it is a leaf, completing any pending nodes it fills in.
+	node, h := &bstNode[T]{value: v}, 1
+	for n := len(b.pending) - 1; n >= 0 && b.heights[n] == h; n-- {
+		b.pending[n].right = node
+		node, h = b.pending[n], h+1
+		b.pending, b.heights = b.pending[:n], b.heights[:n]
+	}
+	b.carry, b.carryHeight = node, h
+	return true
+}
+
+// finish attaches the remaining pending nodes down the right spine and
+// returns the root of the tree along with the number of values in it.
+func (b *bstSeqBuilder[T]) finish() (*bstNode[T], int) {
+	node := b.carry
+	for n := len(b.pending) - 1; n >= 0; n-- {
+		b.pending[n].right = node
+		node = b.pending[n]
+	}
+	return node, b.size
+}
+
 // HasLeft reports if this node has a Left child.
 func (t *bstNode[T]) HasLeft() bool {
 	if t == nil {
@@ -36,6 +112,18 @@ func (t *bstNode[T]) Right() BinaryTree[T] {
 	return t.right
 }
 
+// Parent always returns nil, as these nodes do not keep track of their
+// parent.
+func (t *bstNode[T]) Parent() BinaryTree[T] {
+	return nil
+}
+
+// childFields returns the addresses of this nodes child pointers, so that
+// generic helpers such as flattenNodes can rearrange them.
+func (t *bstNode[T]) childFields() (left, right **bstNode[T]) {
+	return &t.left, &t.right
+}
+
 // Value returns this nodes Value.
 func (t *bstNode[T]) Value() T {
 	return t.value
@@ -50,55 +138,131 @@ func (t *bstNode[T]) Metadata() string {
 // Insert inserts the value into the tree, growing as needed, and reports
 // if the operation was successful.
 func (t *bstNode[T]) Insert(v T) bool {
+	_, ok := t.insert(v, ordering[T]{}.resolve())
+	return ok
+}
+
+// insert is the worker for Insert, placing v according to the given ordering
+// of the tree. It returns how many levels below this node v was placed, and
+// reports if it was added.
+func (t *bstNode[T]) insert(v T, o ordering[T]) (int, bool) {
 	if t == nil {
-		return false
+		return 0, false
 	}
 
-	// Duplicates are not allowed.
-	if v == t.value {
-		return false
+	// Duplicates are only allowed if the tree keeps them, in which case
+	// they go to the right.
+	c := o.compare(v, t.value)
+	if c == 0 && !o.duplicates {
+		return 0, false
 	}
 
 	// If we need to go farther left, add a new node if needed,
 	// otherwise recurse!
-	if v < t.value {
+	if c < 0 {
 		if t.left == nil {
 			t.left = &bstNode[T]{value: v}
-			return true
+			return 1, true
 		}
-		return t.left.Insert(v)
+		depth, ok := t.left.insert(v, o)
+		return depth + 1, ok
 	}
 
 	if t.right == nil {
 		t.right = &bstNode[T]{value: v}
-		return true
+		return 1, true
 	}
-	return t.right.Insert(v)
+	depth, ok := t.right.insert(v, o)
+	return depth + 1, ok
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+func (t *bstNode[T]) InsertAll(vals ...T) int {
+	var n int
+	for _, v := range vals {
+		if t.Insert(v) {
+			n++
+		}
+	}
+	return n
 }
 
 // Delete the requested node from the tree and reports if it was successful.
 // If the value is not in the tree, the tree is unchanged and false is returned.
 // If the node is not a leaf the trees internal structure may be updated.
+//
+// When this node is the one removed and it has a single child, the child's
+// contents are moved up into this node. The last node of a tree can not
+// remove itself, so false is returned for it.
 func (t *bstNode[T]) Delete(v T) bool {
-	if t == nil {
+	if t == nil || (t.left == nil && t.right == nil) {
 		return false
 	}
-	return false
+	root, ok := t.remove(v, ordering[T]{}.resolve())
+	if ok && root != t {
+		*t = *root
+	}
+	return ok
+}
+
+// remove is the worker for Delete, finding v according to the given ordering
+// of the tree. It returns the root of the subtree once v is removed, and
+// reports if v was found. A node with two children takes the value of its
+// in order successor, whose node is removed in its place.
+func (t *bstNode[T]) remove(v T, o ordering[T]) (*bstNode[T], bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	var ok bool
+	switch c := o.compare(v, t.value); {
+	case c < 0:
+		t.left, ok = t.left.remove(v, o)
+		return t, ok
+	case c > 0:
+		t.right, ok = t.right.remove(v, o)
+		return t, ok
+	case t.left == nil:
+		return t.right, true
+	case t.right == nil:
+		return t.left, true
+	}
+
+	t.value, t.right = t.right.removeMin()
+	return t, true
+}
+
+// removeMin removes the first node in order from the subtree, returning its
+// value and the root of what is left of the subtree.
+func (t *bstNode[T]) removeMin() (T, *bstNode[T]) {
+	if t.left == nil {
+		return t.value, t.right
+	}
+	var v T
+	v, t.left = t.left.removeMin()
+	return v, t
 }
 
 // Search reports if the given value is in the tree.
 func (t *bstNode[T]) Search(v T) bool {
+	return t.search(v, ordering[T]{}.resolve())
+}
+
+// search is the worker for Search, following the given ordering of the tree.
+func (t *bstNode[T]) search(v T, o ordering[T]) bool {
 	if t == nil {
 		return false
 	}
-	if v == t.value {
+	c := o.compare(v, t.value)
+	if c == 0 {
 		return true
 	}
 
-	if v < t.value {
-		return t.left.Search(v)
+	if c < 0 {
+		return t.left.search(v, o)
 	}
-	return t.right.Search(v)
+	return t.right.search(v, o)
 }
 
 // Traverse traverses the tree in the specified order emitting the values to
@@ -109,13 +273,25 @@ func (t *bstNode[T]) Search(v T) bool {
 func (t *bstNode[T]) Traverse(tOrder TraverseOrder) <-chan T {
 	ch := make(chan T)
 	go func() {
-		traverseBinaryTree(t, tOrder, ch)
+		if !t.IsEmpty() {
+			traverseBinaryTree(t, tOrder, ch)
+		}
 		close(ch)
 	}()
 
 	return ch
 }
 
+// walkInOrder calls fn on each node of the subtree in order.
+func (t *bstNode[T]) walkInOrder(fn func(*bstNode[T])) {
+	if t == nil {
+		return
+	}
+	t.left.walkInOrder(fn)
+	fn(t)
+	t.right.walkInOrder(fn)
+}
+
 // Height returns the height of the longest path in the tree from the
 // root node to the farthest leaf.
 func (t *bstNode[T]) Height() int {
@@ -129,3 +305,17 @@ func (t *bstNode[T]) Height() int {
 	}
 	return rh + 1
 }
+
+// Size returns the number of values in the tree rooted at this node.
+func (t *bstNode[T]) Size() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + t.left.Size() + t.right.Size()
+}
+
+// IsEmpty reports if the tree rooted at this node holds no values, which is
+// only the case for a nil node.
+func (t *bstNode[T]) IsEmpty() bool {
+	return t == nil
+}
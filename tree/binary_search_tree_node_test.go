@@ -153,6 +153,30 @@ func TestBSTNodeDelete(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			// The only node of a tree can not remove itself.
+			tree: &bstNode[int]{value: 42},
+			val:  42,
+			want: false,
+		},
+		{
+			// A root with one child takes on the child's contents.
+			tree: &bstNode[int]{
+				value: 42,
+				left:  &bstNode[int]{value: 21},
+			},
+			val:  42,
+			want: true,
+		},
+		{
+			tree: &bstNode[int]{
+				value: 42,
+				left:  &bstNode[int]{value: 21},
+				right: &bstNode[int]{value: 84},
+			},
+			val:  42,
+			want: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -335,7 +359,7 @@ func TestBSTNodeTraverse(t *testing.T) {
 		{
 			tree:  tree,
 			order: TraverseLevelOrder,
-			want:  nil,
+			want:  []int{42, 21, 84, 1, 30, 57, 29},
 		},
 	}
 
@@ -357,6 +381,10 @@ func TestBSTNodeTraverse(t *testing.T) {
 				got, test.want, cmp.Diff(test.want, got))
 		}
 	}
+
+	if err := CheckTraversalConsistency(tree.Root()); err != nil {
+		t.Errorf("CheckTraversalConsistency() = %v", err)
+	}
 }
 
 func TestBSTNodeBasics(t *testing.T) {
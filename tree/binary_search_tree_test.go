@@ -1,6 +1,8 @@
 package tree
 
 import (
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -84,7 +86,7 @@ func TestBSTInsert(t *testing.T) {
 			t.Errorf("Insert(%v) = %v, want %v", test.val, got, test.want)
 		}
 
-		if !Equal(test.tree.Root(), test.wantStructure.Root()) {
+		if !Equal[int](test.tree, test.wantStructure) {
 			t.Errorf("value was inserted but the resulting tree was not as expected.")
 		}
 	}
@@ -107,18 +109,16 @@ func TestBSTDelete(t *testing.T) {
 			val:  5,
 			want: false,
 		},
-		/*
-			TODO(rsned): Once Delete is implemented, add this case.
-			{
-				tree: &BST[int]{
-					root: &bstNode[int]{
-						value: 42,
-					},
-				}
-				val: 42,
-				want: false,
+		{
+			tree: &BST[int]{
+				root: &bstNode[int]{
+					value: 42,
+				},
+				size: 1,
 			},
-		*/
+			val:  42,
+			want: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -128,6 +128,47 @@ func TestBSTDelete(t *testing.T) {
 	}
 }
 
+func TestBSTDeleteShapes(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	vals := r.Perm(200)
+
+	tree := NewBST[int]().(*BST[int])
+	tree.InsertAll(vals...)
+
+	// Delete every value in a different random order, which removes
+	// leaves, nodes with one child and nodes with two along the way.
+	remaining := slices.Clone(vals)
+	slices.Sort(remaining)
+	for _, v := range r.Perm(200) {
+		if !tree.Delete(v) {
+			t.Fatalf("Delete(%d) = false, want true", v)
+		}
+		i, _ := slices.BinarySearch(remaining, v)
+		remaining = slices.Delete(remaining, i, i+1)
+
+		if !IsValidBST[int](tree) {
+			t.Fatalf("after Delete(%d), the tree is not a valid BST", v)
+		}
+		if got := ToSlice[int](tree); !slices.Equal(got, remaining) {
+			t.Fatalf("after Delete(%d), the tree holds %v, want %v", v, got, remaining)
+		}
+		if got := tree.Size(); got != len(remaining) {
+			t.Fatalf("Size() after Delete(%d) = %d, want %d", v, got, len(remaining))
+		}
+	}
+
+	// Trees keeping duplicates remove one copy at a time, and Descending
+	// trees find the value by their own ordering.
+	multi := NewBST[int](Descending(), IgnoreDuplicates(false))
+	multi.InsertAll(5, 3, 5, 8, 5, 1)
+	if !multi.Delete(5) || multi.(*BST[int]).Count(5) != 2 || multi.Size() != 5 {
+		t.Errorf("Delete(5) left Count(5) = %d, Size() = %d, want 2, 5", multi.(*BST[int]).Count(5), multi.Size())
+	}
+	if got, want := ToSlice(multi), []int{8, 5, 5, 3, 1}; !slices.Equal(got, want) {
+		t.Errorf("after Delete(5), the descending tree holds %v, want %v", got, want)
+	}
+}
+
 func TestBSTSearch(t *testing.T) {
 	tests := []struct {
 		tree Tree[int]
@@ -290,7 +331,7 @@ func TestBSTTraverse(t *testing.T) {
 		{
 			tree:  tree,
 			order: TraverseLevelOrder,
-			want:  nil,
+			want:  []int{42, 21, 84, 1, 30, 57, 29},
 		},
 	}
 
@@ -312,4 +353,393 @@ func TestBSTTraverse(t *testing.T) {
 				got, test.want, cmp.Diff(test.want, got))
 		}
 	}
+
+	if err := CheckTraversalConsistency(tree.Root()); err != nil {
+		t.Errorf("CheckTraversalConsistency() = %v", err)
+	}
+}
+
+func TestRecoverBST(t *testing.T) {
+	tests := []struct {
+		tree *BST[int]
+		want bool
+		vals []int
+	}{
+		{
+			tree: &BST[int]{},
+			want: false,
+			vals: nil,
+		},
+		{
+			// Already valid.
+			tree: newBSTWith(21, 1, 42),
+			want: false,
+			vals: []int{1, 21, 42},
+		},
+		{
+			// Adjacent nodes swapped, parent and left child.
+			//
+			//   1
+			//  / \
+			// 21  42
+			tree: &BST[int]{
+				root: &bstNode[int]{
+					value: 1,
+					left: &bstNode[int]{
+						value: 21,
+					},
+					right: &bstNode[int]{
+						value: 42,
+					},
+				},
+			},
+			want: true,
+			vals: []int{1, 21, 42},
+		},
+		{
+			// Non-adjacent nodes swapped.
+			//
+			//       21
+			//      /  \
+			//    84    42
+			//   / \   /  \
+			// -13 11 30   1
+			tree: &BST[int]{
+				root: &bstNode[int]{
+					value: 21,
+					left: &bstNode[int]{
+						value: 84,
+						left: &bstNode[int]{
+							value: -13,
+						},
+						right: &bstNode[int]{
+							value: 11,
+						},
+					},
+					right: &bstNode[int]{
+						value: 42,
+						left: &bstNode[int]{
+							value: 30,
+						},
+						right: &bstNode[int]{
+							value: 1,
+						},
+					},
+				},
+			},
+			want: true,
+			vals: []int{-13, 1, 11, 21, 30, 42, 84},
+		},
+	}
+
+	for _, test := range tests {
+		if got := RecoverBST(test.tree); got != test.want {
+			t.Errorf("RecoverBST() = %v, want %v", got, test.want)
+		}
+
+		var got []int
+		InOrderInto(test.tree.Root(), &got)
+		if !cmp.Equal(got, test.vals) {
+			t.Errorf("after RecoverBST() tree = %v, want %v", got, test.vals)
+		}
+	}
+}
+
+// intRange returns an iterator producing the values [0, n) in order.
+func intRange(n int) func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestNewBSTFromSortedSeq(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 4, 5, 7, 8, 100, 1023, 1024, 100000} {
+		tree, ok := NewBSTFromSortedSeq(intRange(n))
+		if !ok {
+			t.Errorf("NewBSTFromSortedSeq(%d values) = _, false, want true", n)
+			continue
+		}
+
+		if got := tree.Size(); got != n {
+			t.Errorf("NewBSTFromSortedSeq(%d values).Size() = %d, want %d", n, got, n)
+		}
+		// The output tree should be balanced.
+		if h, maxH := tree.Height(), minHeight(n); h > maxH {
+			t.Errorf("NewBSTFromSortedSeq(%d values).Height() = %d, want <= %d", n, h, maxH)
+		}
+
+		var got []int
+		InOrderInto(tree.(*BST[int]).Root(), &got)
+		if len(got) != n {
+			t.Errorf("NewBSTFromSortedSeq(%d values) has %d values in order", n, len(got))
+			continue
+		}
+		for i, v := range got {
+			if v != i {
+				t.Errorf("NewBSTFromSortedSeq(%d values) value[%d] = %d, want %d", n, i, v, i)
+				break
+			}
+		}
+	}
+}
+
+func TestNewBSTFromSortedSeqMemory(t *testing.T) {
+	// Other than one allocation per node, only the O(log n) bookkeeping
+	// and the tree itself should be allocated, never a copy of the values.
+	const n = 100000
+	allocs := testing.AllocsPerRun(5, func() {
+		NewBSTFromSortedSeq(intRange(n))
+	})
+	if maxAllocs := float64(n + 64); allocs > maxAllocs {
+		t.Errorf("NewBSTFromSortedSeq(%d values) made %v allocations, want <= %v",
+			n, allocs, maxAllocs)
+	}
+}
+
+func TestNewBSTFromSortedSeqUnsorted(t *testing.T) {
+	tests := [][]int{
+		{1, 3, 2},
+		{1, 2, 2, 3},
+		{5, 4},
+	}
+
+	for _, vals := range tests {
+		seq := func(yield func(int) bool) {
+			for _, v := range vals {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+		if _, ok := NewBSTFromSortedSeq(seq); ok {
+			t.Errorf("NewBSTFromSortedSeq(%v) = _, true, want false", vals)
+		}
+	}
+}
+
+func TestNewBSTFromSorted(t *testing.T) {
+	tests := []struct {
+		vals []int
+		want []int
+	}{
+		{
+			vals: nil,
+			want: nil,
+		},
+		{
+			vals: []int{7},
+			want: []int{7},
+		},
+		{
+			vals: []int{1, 2, 3, 4, 5, 6, 7},
+			want: []int{1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			vals: []int{-5, 0, 3, 8, 13, 21, 34, 55, 89, 144},
+			want: []int{-5, 0, 3, 8, 13, 21, 34, 55, 89, 144},
+		},
+		{
+			// Duplicates are collapsed.
+			vals: []int{1, 1, 2, 3, 3, 3, 4, 9, 9},
+			want: []int{1, 2, 3, 4, 9},
+		},
+	}
+
+	for _, test := range tests {
+		vals := slices.Clone(test.vals)
+		tree := NewBSTFromSorted(test.vals)
+
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("NewBSTFromSorted(%v) in order = %v, want %v", test.vals, got, test.want)
+		}
+		if got := tree.Size(); got != len(test.want) {
+			t.Errorf("NewBSTFromSorted(%v).Size() = %d, want %d", test.vals, got, len(test.want))
+		}
+		if got, want := tree.Height(), minHeight(len(test.want)); got != want {
+			t.Errorf("NewBSTFromSorted(%v).Height() = %d, want %d", test.vals, got, want)
+		}
+		if !slices.Equal(test.vals, vals) {
+			t.Errorf("NewBSTFromSorted modified its input, got %v, want %v", test.vals, vals)
+		}
+	}
+}
+
+func TestNewBSTFromLevelOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []int
+		want []string
+	}{
+		{
+			name: "empty",
+			vals: nil,
+			want: nil,
+		},
+		{
+			name: "single",
+			vals: []int{7},
+			want: []string{"V"},
+		},
+		{
+			name: "complete",
+			vals: []int{4, 2, 6, 1, 3, 5, 7},
+			want: []string{
+				"↓L", "↓L", "V", "↑", "V", "↓R", "V", "↑", "↑",
+				"V",
+				"↓R", "↓L", "V", "↑", "V", "↓R", "V", "↑", "↑",
+			},
+		},
+		{
+			name: "right leaning chain",
+			vals: []int{1, 2, 3},
+			want: []string{"V", "↓R", "V", "↓R", "V", "↑", "↑"},
+		},
+		{
+			name: "left leaning chain",
+			vals: []int{3, 2, 1},
+			want: []string{"↓L", "↓L", "V", "↑", "V", "↑", "V"},
+		},
+		{
+			name: "sparse",
+			vals: []int{10, 5, 20, 15},
+			want: []string{"↓L", "V", "↑", "V", "↓R", "↓L", "V", "↑", "V", "↑"},
+		},
+		{
+			// Repeats are dropped without changing the shape.
+			name: "repeats",
+			vals: []int{10, 5, 20, 5, 15, 10},
+			want: []string{"↓L", "V", "↑", "V", "↓R", "↓L", "V", "↑", "V", "↑"},
+		},
+	}
+
+	for _, test := range tests {
+		tree := NewBSTFromLevelOrder(test.vals)
+		if got := binaryTreeStructure[int](tree.Root()); !slices.Equal(got, test.want) {
+			t.Errorf("%s: NewBSTFromLevelOrder(%v) structure = %v, want %v", test.name, test.vals, got, test.want)
+		}
+
+		want := slices.Clone(test.vals)
+		slices.Sort(want)
+		want = slices.Compact(want)
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: NewBSTFromLevelOrder(%v) in order = %v, want %v", test.name, test.vals, got, want)
+		}
+		if got := tree.Size(); got != len(want) {
+			t.Errorf("%s: NewBSTFromLevelOrder(%v).Size() = %d, want %d", test.name, test.vals, got, len(want))
+		}
+	}
+
+	// The level order of any BST rebuilds that same tree.
+	orig := newBSTWith(21, 1, 42, -13, 11, 30, 84, 57, 8, 99)
+	var level []int
+	orig.Walk(TraverseLevelOrder, func(v int) bool {
+		level = append(level, v)
+		return true
+	})
+	if got := NewBSTFromLevelOrder(level); !binaryTreesEqual(got.Root(), orig.Root()) {
+		t.Errorf("NewBSTFromLevelOrder(%v) = %v, want %v", level, got, orig)
+	}
+}
+
+func TestBSTAutoRebalanceThreshold(t *testing.T) {
+	const n = 2000
+
+	for _, factor := range []float64{1, 2, 3.5} {
+		tree := NewBST[int](AutoRebalanceThreshold(factor))
+		for i := 0; i < n; i++ {
+			if !tree.Insert(i) {
+				t.Fatalf("factor %v: Insert(%d) = false, want true", factor, i)
+			}
+
+			size := i + 1
+			if got := tree.Size(); got != size {
+				t.Fatalf("factor %v: after inserting %d values, Size() = %d, want %d", factor, size, got, size)
+			}
+			if h, maxH := tree.Height(), int(factor*float64(minHeight(size))); h > maxH {
+				t.Fatalf("factor %v: after inserting %d sorted values, Height() = %d, want <= %d", factor, size, h, maxH)
+			}
+			// Check a spread of the values inserted so far are still found.
+			for _, v := range []int{0, i / 2, i} {
+				if !tree.Search(v) {
+					t.Fatalf("factor %v: after inserting %d values, Search(%d) = false, want true", factor, size, v)
+				}
+			}
+		}
+
+		var got []int
+		InOrderInto(tree.(*BST[int]).Root(), &got)
+		if len(got) != n || !slices.IsSorted(got) {
+			t.Errorf("factor %v: in order values are not the %d values inserted", factor, n)
+		}
+	}
+
+	// Without the option sorted values still leave a single chain.
+	tree := NewBST[int]()
+	for i := 0; i < 100; i++ {
+		tree.Insert(i)
+	}
+	if got := tree.Height(); got != 100 {
+		t.Errorf("NewBST() after 100 sorted inserts Height() = %d, want 100", got)
+	}
+}
+
+func TestBalanceDSW(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 4, 7, 8, 100, 1000} {
+		// Sorted inserts leave the tree as one long chain.
+		tree := NewBST[int]().(*BST[int])
+		for i := 0; i < n; i++ {
+			tree.Insert(i)
+		}
+		if n > 0 && tree.Height() != n {
+			t.Fatalf("sorted inserts of %d values gave Height() %d, want %d", n, tree.Height(), n)
+		}
+
+		BalanceDSW(tree)
+
+		if got, want := tree.Height(), minHeight(n); got != want {
+			t.Errorf("BalanceDSW(%d values) Height() = %d, want %d", n, got, want)
+		}
+		if got := tree.Size(); got != n {
+			t.Errorf("BalanceDSW(%d values) Size() = %d, want %d", n, got, n)
+		}
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		if len(got) != n || !slices.IsSorted(got) || (n > 0 && (got[0] != 0 || got[n-1] != n-1)) {
+			t.Errorf("BalanceDSW(%d values) did not keep the values in order", n)
+		}
+		if !IsValidBST(tree.Root()) {
+			t.Errorf("BalanceDSW(%d values) is not a valid BST", n)
+		}
+		for _, v := range []int{0, n / 2, n - 1} {
+			if n > 0 && !tree.Search(v) {
+				t.Errorf("BalanceDSW(%d values) Search(%d) = false, want true", n, v)
+			}
+		}
+	}
+
+	// A descending tree stays descending.
+	tree := NewBST[int](Descending()).(*BST[int])
+	for i := 0; i < 20; i++ {
+		tree.Insert(i)
+	}
+	BalanceDSW(tree)
+	if got, want := tree.Height(), minHeight(20); got != want {
+		t.Errorf("BalanceDSW(Descending) Height() = %d, want %d", got, want)
+	}
+	if got, ok := tree.Min(); got != 19 || !ok {
+		t.Errorf("BalanceDSW(Descending) Min() = %v, %v, want 19, true", got, ok)
+	}
+	if !tree.Search(7) {
+		t.Errorf("BalanceDSW(Descending) Search(7) = false, want true")
+	}
+
+	BalanceDSW[int](nil)
 }
@@ -1,11 +1,9 @@
 package tree
 
-import "golang.org/x/exp/constraints"
-
 // BinaryTree is the simplest tree node type.
 //
 // A node value and two children (left and right).
-type BinaryTree[T constraints.Ordered] interface {
+type BinaryTree[T any] interface {
 	Tree[T]
 
 	// Value returns the value at this node in the tree.
@@ -23,6 +21,11 @@ type BinaryTree[T constraints.Ordered] interface {
 	// Right returns the Right child, if any, of this node.
 	Right() BinaryTree[T]
 
+	// Parent returns the node this node is a child of. It returns nil for
+	// the root, and for every node of a type that does not keep track of
+	// its parent, as only AVL nodes do.
+	Parent() BinaryTree[T]
+
 	// metadata returns a metadata string, if any, for this node in the tree.
 	//
 	// Some examples include Balance Factor for an AVL tree, or Red/Black for a
@@ -32,52 +35,708 @@ type BinaryTree[T constraints.Ordered] interface {
 	Metadata() string
 }
 
-// traverseBinaryTree is a recursive function that traverses a BinaryTree in the given
-// order emitting values to the given channel.
+// countedNode is implemented by nodes that can hold several copies of an
+// equal value in one node, as those of a Treap keeping duplicates do.
+type countedNode interface {
+	// copies returns the number of copies of the value held by the node.
+	copies() int
+}
+
+// copiesOf returns the number of copies of its value held by the given node,
+// which is one for nodes that keep each copy in a node of its own.
+func copiesOf(node any) int {
+	if c, ok := node.(countedNode); ok {
+		return c.copies()
+	}
+	return 1
+}
+
+// visitCopies calls visit with the value of the given node once for each
+// copy it holds, stopping as soon as visit returns false. It reports if every
+// copy was visited.
+func visitCopies[T any](node BinaryTree[T], visit func(T) bool) bool {
+	v := node.Value()
+	for i := copiesOf(node); i > 0; i-- {
+		if !visit(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendCopies appends v onto out once for each of the given number of
+// copies.
+func appendCopies[T any](out []T, v T, copies int) []T {
+	for ; copies > 0; copies-- {
+		out = append(out, v)
+	}
+	return out
+}
+
+// traverseBinaryTree traverses a BinaryTree in the given order emitting
+// values to the given channel.
 //
 // It does NOT close the channel when it is finished.
 //
 // Best usage is to kick this off in a goroutine.
-func traverseBinaryTree[T constraints.Ordered](tree BinaryTree[T], tOrder TraverseOrder, ch chan T) {
-	// What to do if the type underlying the tree is nil?
-	// We can't nil check a pointer to an interface
+func traverseBinaryTree[T any](tree BinaryTree[T], tOrder TraverseOrder, ch chan T) {
+	// A nil node, such as the missing child of another node, has nothing
+	// to emit and can't be asked for its value.
+	if isTreeNil(tree) {
+		return
+	}
+
+	switch tOrder {
+	case TraverseInOrder, TraversePreOrder, TraversePostOrder, TraverseReverseOrder, TraverseLevelOrder:
+		walkBinaryTree(tree, tOrder, func(v T) bool {
+			ch <- v
+			return true
+		})
+	default:
+		// TODO(rsned): There aren't other choices, so should this be
+		// an error or panic as well?
+	}
+}
+
+// walkBinaryTree calls visit with each value of the given tree in the given
+// order, stopping as soon as visit returns false. It reports if every value
+// was visited.
+//
+// The tree is walked with an explicit stack rather than by recursion, so a
+// degenerate tree as tall as it is large does not grow the goroutine stack.
+func walkBinaryTree[T any](tree BinaryTree[T], tOrder TraverseOrder, visit func(T) bool) bool {
 	switch tOrder {
 	case TraverseInOrder:
-		if tree.HasLeft() {
-			traverseBinaryTree(tree.Left(), tOrder, ch)
+		return walkInOrder(tree, false, visit)
+	case TraverseReverseOrder:
+		return walkInOrder(tree, true, visit)
+	case TraversePreOrder:
+		stack := []BinaryTree[T]{tree}
+		for len(stack) > 0 {
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !visitCopies(node, visit) {
+				return false
+			}
+			// The right is pushed first so the left comes off first.
+			if node.HasRight() {
+				stack = append(stack, node.Right())
+			}
+			if node.HasLeft() {
+				stack = append(stack, node.Left())
+			}
 		}
-		ch <- tree.Value()
-		if tree.HasRight() {
-			traverseBinaryTree(tree.Right(), tOrder, ch)
+		return true
+	case TraversePostOrder:
+		// Each node is seen twice, first to push its children above it,
+		// and then once they are done, to be visited.
+		type frame struct {
+			node     BinaryTree[T]
+			expanded bool
 		}
-	case TraversePreOrder:
-		ch <- tree.Value()
-		if tree.HasLeft() {
-			traverseBinaryTree(tree.Left(), tOrder, ch)
+		stack := []frame{{node: tree}}
+		for len(stack) > 0 {
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if f.expanded {
+				if !visitCopies(f.node, visit) {
+					return false
+				}
+				continue
+			}
+			stack = append(stack, frame{node: f.node, expanded: true})
+			if f.node.HasRight() {
+				stack = append(stack, frame{node: f.node.Right()})
+			}
+			if f.node.HasLeft() {
+				stack = append(stack, frame{node: f.node.Left()})
+			}
 		}
-		if tree.HasRight() {
-			traverseBinaryTree(tree.Right(), tOrder, ch)
+		return true
+	case TraverseLevelOrder:
+		queue := []BinaryTree[T]{tree}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			if !visitCopies(node, visit) {
+				return false
+			}
+			if node.HasLeft() {
+				queue = append(queue, node.Left())
+			}
+			if node.HasRight() {
+				queue = append(queue, node.Right())
+			}
 		}
-	case TraversePostOrder:
-		if tree.HasLeft() {
-			traverseBinaryTree(tree.Left(), tOrder, ch)
+		return true
+	}
+	return true
+}
+
+// walkInOrder is the in order, or reverse order, case of walkBinaryTree.
+func walkInOrder[T any](tree BinaryTree[T], reverse bool, visit func(T) bool) bool {
+	return unwindInOrder(pushFirstSide(nil, tree, reverse), reverse, visit)
+}
+
+// walkInOrderFrom is walkInOrder starting at v, skipping the values that come
+// before v in the order of the walk according to o. Subtrees holding only
+// skipped values are never looked at.
+func walkInOrderFrom[T any](tree BinaryTree[T], v T, o ordering[T], reverse bool, visit func(T) bool) bool {
+	// Follow the search path for v, keeping only the nodes that are not
+	// skipped. These are the next nodes to visit, with the path to the
+	// nearest one on top, just as pushFirstSide would have left them.
+	var stack []BinaryTree[T]
+	for node := tree; node != nil; {
+		c := o.compare(node.Value(), v)
+		if reverse {
+			c = -c
 		}
-		if tree.HasRight() {
-			traverseBinaryTree(tree.Right(), tOrder, ch)
+		if c >= 0 {
+			stack = append(stack, node)
+			node = childOn(node, reverse)
+		} else {
+			node = childOn(node, !reverse)
 		}
-		ch <- tree.Value()
-	case TraverseReverseOrder:
-		if tree.HasRight() {
-			traverseBinaryTree(tree.Right(), tOrder, ch)
+	}
+	return unwindInOrder(stack, reverse, visit)
+}
+
+// pushFirstSide appends node, and the path down its first side, the left or
+// the right when reversed, as far as it goes onto the stack.
+func pushFirstSide[T any](stack []BinaryTree[T], node BinaryTree[T], reverse bool) []BinaryTree[T] {
+	for node != nil {
+		stack = append(stack, node)
+		node = childOn(node, reverse)
+	}
+	return stack
+}
+
+// unwindInOrder visits the nodes on the stack from the top down, each
+// followed by its subtree on the far side, which is pushed in turn.
+func unwindInOrder[T any](stack []BinaryTree[T], reverse bool, visit func(T) bool) bool {
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !visitCopies(node, visit) {
+			return false
 		}
-		ch <- tree.Value()
-		if tree.HasLeft() {
-			traverseBinaryTree(tree.Left(), tOrder, ch)
+		stack = pushFirstSide(stack, childOn(node, !reverse), reverse)
+	}
+	return true
+}
+
+// childOn returns the right child of the node if right is set and the left
+// otherwise, or nil if there is no child on that side.
+func childOn[T any](node BinaryTree[T], right bool) BinaryTree[T] {
+	if right {
+		if node.HasRight() {
+			return node.Right()
 		}
-	case TraverseLevelOrder:
-		//panic("Level Order traversal not implemented")
-	default:
-		// TODO(rsned): There aren't other choices, so should this be
-		// an error or panic as well?
+		return nil
+	}
+	if node.HasLeft() {
+		return node.Left()
+	}
+	return nil
+}
+
+// valuesFrom returns an iterator over the values of the given tree in the
+// given order, starting at v as described by the From methods of the trees.
+func valuesFrom[T any](t BinaryTree[T], v T, tOrder TraverseOrder, o ordering[T]) func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		if isTreeNil(t) {
+			return
+		}
+		switch tOrder {
+		case TraverseInOrder:
+			walkInOrderFrom(t, v, o, false, yield)
+		case TraverseReverseOrder:
+			walkInOrderFrom(t, v, o, true, yield)
+		}
+	}
+}
+
+// InOrderInto appends the values of the given tree in order directly onto the
+// slice pointed to by out, reusing its existing capacity where possible.
+//
+// This avoids the goroutine and channel overhead of Traverse and is the
+// cheapest way to collect the values of a tree.
+func InOrderInto[T any](t BinaryTree[T], out *[]T) {
+	if isTreeNil(t) {
+		return
+	}
+	inOrderAppend(t, out)
+}
+
+// inOrderAppend is the recursive worker for InOrderInto.
+func inOrderAppend[T any](t BinaryTree[T], out *[]T) {
+	if t.HasLeft() {
+		inOrderAppend(t.Left(), out)
+	}
+	*out = appendCopies(*out, t.Value(), copiesOf(t))
+	if t.HasRight() {
+		inOrderAppend(t.Right(), out)
+	}
+}
+
+// appendValues appends the values of the given tree onto dst in the given
+// order, returning the extended slice.
+func appendValues[T any](dst []T, t BinaryTree[T], tOrder TraverseOrder) []T {
+	walkBinaryTree(t, tOrder, func(v T) bool {
+		dst = append(dst, v)
+		return true
+	})
+	return dst
+}
+
+// rangeAppend appends the values v of the given tree with lo <= v <= hi onto
+// out in the order of the tree, skipping any subtrees that can not hold
+// values in the range. If visit is not nil, it is called on each node looked
+// at along the way.
+func rangeAppend[T any](t BinaryTree[T], lo, hi T, o ordering[T], visit func(BinaryTree[T]), out *[]T) {
+	if visit != nil {
+		visit(t)
+	}
+
+	// The bound reached first depends on which way the tree is ordered.
+	first, last := lo, hi
+	if o.descending {
+		first, last = hi, lo
+	}
+
+	v := t.Value()
+	if t.HasLeft() && o.compare(first, v) < 0 {
+		rangeAppend(t.Left(), lo, hi, o, visit, out)
+	}
+	if o.cmp(lo, v) <= 0 && o.cmp(v, hi) <= 0 {
+		*out = appendCopies(*out, v, copiesOf(t))
+	}
+	if t.HasRight() && o.compare(v, last) < 0 {
+		rangeAppend(t.Right(), lo, hi, o, visit, out)
+	}
+}
+
+// lowestCommonAncestor returns the value of the lowest node in the given tree
+// that has both a and b in its subtree, where a node is counted as being in
+// its own subtree. It descends while both values are on the same side of the
+// current node, then confirms both values are below the node it stopped on.
+// If either value is not in the tree, false is returned.
+func lowestCommonAncestor[T any](t BinaryTree[T], a, b T, o ordering[T]) (T, bool) {
+	var zero T
+	for {
+		ca, cb := o.compare(a, t.Value()), o.compare(b, t.Value())
+		switch {
+		case ca < 0 && cb < 0 && t.HasLeft():
+			t = t.Left()
+		case ca > 0 && cb > 0 && t.HasRight():
+			t = t.Right()
+		default:
+			if !containsValue(t, a, o) || !containsValue(t, b, o) {
+				return zero, false
+			}
+			return t.Value(), true
+		}
+	}
+}
+
+// containsValue reports if v is in the subtree rooted at the given node.
+func containsValue[T any](t BinaryTree[T], v T, o ordering[T]) bool {
+	for {
+		c := o.compare(v, t.Value())
+		switch {
+		case c == 0:
+			return true
+		case c < 0 && t.HasLeft():
+			t = t.Left()
+		case c > 0 && t.HasRight():
+			t = t.Right()
+		default:
+			return false
+		}
+	}
+}
+
+// nthValue returns the k-th value (0-indexed) of the given tree in the given
+// order, walking the tree until it is reached and stopping there. If k is out
+// of range, false is returned.
+func nthValue[T any](t BinaryTree[T], tOrder TraverseOrder, k int) (T, bool) {
+	var got T
+	if k < 0 {
+		return got, false
+	}
+
+	var i int
+	found := !walkBinaryTree(t, tOrder, func(v T) bool {
+		if i == k {
+			got = v
+			return false
+		}
+		i++
+		return true
+	})
+	return got, found
+}
+
+// countValue returns the number of copies in the given tree of a value equal
+// to v. Copies of v may end up on either side of one another after
+// rotations, so both sides of every equal node are searched.
+func countValue[T any](t BinaryTree[T], v T, o ordering[T]) int {
+	var n int
+	c := o.compare(v, t.Value())
+	if c == 0 {
+		n += copiesOf(t)
+	}
+	if c <= 0 && t.HasLeft() {
+		n += countValue(t.Left(), v, o)
+	}
+	if c >= 0 && t.HasRight() {
+		n += countValue(t.Right(), v, o)
+	}
+	return n
+}
+
+// countMatching returns the number of values in the given tree for which pred
+// returns true.
+func countMatching[T any](t BinaryTree[T], pred func(T) bool) int {
+	var n int
+	if pred(t.Value()) {
+		n += copiesOf(t)
+	}
+	if t.HasLeft() {
+		n += countMatching(t.Left(), pred)
+	}
+	if t.HasRight() {
+		n += countMatching(t.Right(), pred)
+	}
+	return n
+}
+
+// valuesExcept returns the values of the given tree in order, leaving out
+// those for which pred returns true, along with the count left out.
+func valuesExcept[T any](t BinaryTree[T], pred func(T) bool, dst []T) ([]T, int) {
+	var removed int
+	walkBinaryTree(t, TraverseInOrder, func(v T) bool {
+		if pred(v) {
+			removed++
+		} else {
+			dst = append(dst, v)
+		}
+		return true
+	})
+	return dst, removed
+}
+
+// minLeafDepth returns the number of edges between the root of the given tree
+// and its nearest leaf. The tree is searched breadth first, level by level,
+// stopping at the first leaf found, so only the levels above it are visited.
+func minLeafDepth[T any](t BinaryTree[T]) int {
+	level := []BinaryTree[T]{t}
+	for depth := 0; ; depth++ {
+		var next []BinaryTree[T]
+		for _, node := range level {
+			if !node.HasLeft() && !node.HasRight() {
+				return depth
+			}
+			if node.HasLeft() {
+				next = append(next, node.Left())
+			}
+			if node.HasRight() {
+				next = append(next, node.Right())
+			}
+		}
+		level = next
+	}
+}
+
+// countNodeKinds returns the number of leaf nodes in the given tree, which
+// have no children, and the number of internal nodes, which have at least
+// one.
+func countNodeKinds[T any](t BinaryTree[T]) (leaves, internal int) {
+	if !t.HasLeft() && !t.HasRight() {
+		return 1, 0
+	}
+	internal = 1
+	if t.HasLeft() {
+		l, i := countNodeKinds(t.Left())
+		leaves += l
+		internal += i
+	}
+	if t.HasRight() {
+		l, i := countNodeKinds(t.Right())
+		leaves += l
+		internal += i
+	}
+	return leaves, internal
+}
+
+// boundaryValues returns the values around the edge of the given tree, going
+// anti-clockwise from the root. That is the root, then the left boundary from
+// the top down, then every leaf from left to right, and then the right
+// boundary from the bottom up. Each value appears once, even where the
+// boundaries meet the leaves.
+//
+// The left boundary is the path from the left child of the root that takes
+// the left child wherever there is one, and the right child otherwise, down
+// to but not including the leaf it ends at. The right boundary is the mirror
+// of this down from the right child of the root.
+func boundaryValues[T any](t BinaryTree[T]) []T {
+	isLeaf := func(n BinaryTree[T]) bool { return !n.HasLeft() && !n.HasRight() }
+
+	vals := []T{t.Value()}
+	if isLeaf(t) {
+		return vals
+	}
+
+	if t.HasLeft() {
+		for n := t.Left(); !isLeaf(n); {
+			vals = append(vals, n.Value())
+			if n.HasLeft() {
+				n = n.Left()
+			} else {
+				n = n.Right()
+			}
+		}
+	}
+
+	// The leaves, in pre order to keep them left to right.
+	stack := []BinaryTree[T]{t}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if isLeaf(n) {
+			vals = append(vals, n.Value())
+			continue
+		}
+		if n.HasRight() {
+			stack = append(stack, n.Right())
+		}
+		if n.HasLeft() {
+			stack = append(stack, n.Left())
+		}
+	}
+
+	if t.HasRight() {
+		var right []T
+		for n := t.Right(); !isLeaf(n); {
+			right = append(right, n.Value())
+			if n.HasRight() {
+				n = n.Right()
+			} else {
+				n = n.Left()
+			}
+		}
+		for i := len(right) - 1; i >= 0; i-- {
+			vals = append(vals, right[i])
+		}
+	}
+
+	return vals
+}
+
+// valuePath returns the values of the nodes visited while descending from the
+// root of the given tree down to the node holding v, ending with v itself. If
+// v is not in the tree, false is returned.
+func valuePath[T any](t BinaryTree[T], v T, o ordering[T]) ([]T, bool) {
+	var path []T
+	for {
+		path = append(path, t.Value())
+		c := o.compare(v, t.Value())
+		switch {
+		case c == 0:
+			return path, true
+		case c < 0 && t.HasLeft():
+			t = t.Left()
+		case c > 0 && t.HasRight():
+			t = t.Right()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// valueAncestors returns the values of the nodes above the one holding v in
+// the given tree, from its parent up to the root. If v is not in the tree,
+// false is returned.
+func valueAncestors[T any](t BinaryTree[T], v T, o ordering[T]) ([]T, bool) {
+	path, ok := valuePath(t, v, o)
+	if !ok {
+		return nil, false
+	}
+	path = path[:len(path)-1]
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, true
+}
+
+// valueDepth returns the number of edges between the root of the given tree
+// and the node holding v, counting the steps taken by a search for v. If v is
+// not in the tree, false is returned.
+func valueDepth[T any](t BinaryTree[T], v T, o ordering[T]) (int, bool) {
+	for depth := 0; ; depth++ {
+		c := o.compare(v, t.Value())
+		switch {
+		case c == 0:
+			return depth, true
+		case c < 0 && t.HasLeft():
+			t = t.Left()
+		case c > 0 && t.HasRight():
+			t = t.Right()
+		default:
+			return 0, false
+		}
+	}
+}
+
+// sameSubtree reports if a and b are both in the given tree, and both below
+// the same child of its root. The root itself is in neither subtree.
+func sameSubtree[T any](t BinaryTree[T], a, b T, o ordering[T]) bool {
+	da, okA := valueDepth(t, a, o)
+	db, okB := valueDepth(t, b, o)
+	if !okA || !okB || da == 0 || db == 0 {
+		return false
+	}
+	return (o.compare(a, t.Value()) < 0) == (o.compare(b, t.Value()) < 0)
+}
+
+// binaryTreeDiameter returns the height of the given tree along with its
+// diameter, the number of nodes on the longest path between any two of its
+// nodes. Both are found together in a single post order pass, with the
+// longest path through each node being the heights of its two subtrees plus
+// the node itself.
+func binaryTreeDiameter[T any](t BinaryTree[T]) (height, diameter int) {
+	var lh, rh, ld, rd int
+	if t.HasLeft() {
+		lh, ld = binaryTreeDiameter(t.Left())
+	}
+	if t.HasRight() {
+		rh, rd = binaryTreeDiameter(t.Right())
+	}
+	return max(lh, rh) + 1, max(lh+rh+1, ld, rd)
+}
+
+// SuccinctEncode returns a compact encoding of the given tree as a bit
+// sequence describing its shape along with its values in pre order.
+//
+// The shape is written in pre order with a 1 bit for each node and a 0 bit
+// for each missing child, for a total of 2n+1 bits for a tree of n nodes.
+// Bits are packed most significant first into the returned bytes. An empty
+// tree is encoded as a single 0 bit. Each node gives one value, so copies
+// counted within a single node, as a Treap keeping duplicates holds them,
+// are encoded as one.
+func SuccinctEncode[T any](t BinaryTree[T]) (shape []byte, values []T) {
+	var bits int
+	var emit func(node BinaryTree[T], present bool)
+	emit = func(node BinaryTree[T], present bool) {
+		if bits%8 == 0 {
+			shape = append(shape, 0)
+		}
+		if !present {
+			bits++
+			return
+		}
+		shape[bits/8] |= 0x80 >> (bits % 8)
+		bits++
+		values = append(values, node.Value())
+
+		emit(node.Left(), node.HasLeft())
+		emit(node.Right(), node.HasRight())
+	}
+	emit(t, !isTreeNil(t))
+
+	return shape, values
+}
+
+// SuccinctDecode rebuilds a tree from the shape and values produced by
+// SuccinctEncode. The returned tree has the same structure and values as the
+// encoded one, but is backed by plain binary search tree nodes regardless of
+// the type of the original tree.
+//
+// If the shape and values are inconsistent with each other, false is returned.
+func SuccinctDecode[T any](shape []byte, values []T) (BinaryTree[T], bool) {
+	var bits, next int
+	ok := true
+	var build func() *bstNode[T]
+	build = func() *bstNode[T] {
+		if bits >= len(shape)*8 {
+			ok = false
+			return nil
+		}
+		present := shape[bits/8]&(0x80>>(bits%8)) != 0
+		bits++
+		if !present {
+			return nil
+		}
+		if next >= len(values) {
+			ok = false
+			return nil
+		}
+		node := &bstNode[T]{value: values[next]}
+		next++
+
+		node.left = build()
+		node.right = build()
+		return node
+	}
+	root := build()
+
+	// All of the values must be used and only the padding bits in the
+	// final byte may remain.
+	if !ok || next != len(values) || (bits+7)/8 != len(shape) {
+		return nil, false
+	}
+	return root, true
+}
+
+// MaxLevelArrayLen is the longest slice ToLevelArray will return.
+const MaxLevelArrayLen = 1 << 24
+
+// ToLevelArray returns the tree in the array form used for binary heaps, with
+// the root at index 0 and the children of the node at index i at 2i+1 and
+// 2i+2. Places with no node hold nil. Trailing nils are trimmed, and an empty
+// tree gives an empty slice.
+//
+// Every level takes twice the room of the one above it whether or not it is
+// full, so a tree of height h needs up to 2^h - 1 entries. This suits
+// balanced trees and small test fixtures, but not tall, sparse trees. If any
+// node would land past MaxLevelArrayLen, nil is returned.
+func ToLevelArray[T any](t BinaryTree[T]) []*T {
+	if isTreeNil(t) {
+		return nil
+	}
+
+	// Find where every node goes first, so the array is only as long as
+	// the last index used.
+	type placed struct {
+		index int
+		value T
+	}
+	var nodes []placed
+	last := 0
+	fits := true
+	var place func(n BinaryTree[T], i int)
+	place = func(n BinaryTree[T], i int) {
+		if i >= MaxLevelArrayLen {
+			fits = false
+			return
+		}
+		nodes = append(nodes, placed{index: i, value: n.Value()})
+		last = max(last, i)
+		if fits && n.HasLeft() {
+			place(n.Left(), 2*i+1)
+		}
+		if fits && n.HasRight() {
+			place(n.Right(), 2*i+2)
+		}
+	}
+	place(t, 0)
+	if !fits {
+		return nil
+	}
+
+	arr := make([]*T, last+1)
+	for k := range nodes {
+		arr[nodes[k].index] = &nodes[k].value
 	}
+	return arr
 }
@@ -63,6 +63,18 @@ func binaryTreeStructureEqual[T constraints.Ordered](a, b BinaryTree[T]) bool {
 	return slices.Equal(aForm, bForm)
 }
 
+// binaryTreesMetadataEqual reports if the nodes in the same positions of the
+// two trees have the same Metadata, and that neither tree has a node where
+// the other does not.
+func binaryTreesMetadataEqual[T any](a, b BinaryTree[T]) bool {
+	if isTreeNil(a) || isTreeNil(b) {
+		return isTreeNil(a) == isTreeNil(b)
+	}
+	return a.Metadata() == b.Metadata() &&
+		binaryTreesMetadataEqual(a.Left(), b.Left()) &&
+		binaryTreesMetadataEqual(a.Right(), b.Right())
+}
+
 // binaryTreeStructure returns a string representation of the structure and
 // an in order path through the given tree.
 func binaryTreeStructure[T constraints.Ordered](tree BinaryTree[T]) []string {
@@ -105,6 +117,372 @@ func traverseBinaryTreeStructure[T constraints.Ordered](tree BinaryTree[T], ch c
 
 }
 
+// binaryTreePath returns the nodes visited while descending from the root of
+// the given tree down to the node holding v, ending with that node, following
+// the tree's ordering. If v is not in the tree, false is returned.
+func binaryTreePath[T any](t Tree[T], v T) ([]BinaryTree[T], bool) {
+	node := rootOf(t)
+	if isTreeNil(node) {
+		return nil, false
+	}
+
+	o := orderingOf(t).resolve()
+	var path []BinaryTree[T]
+	for {
+		path = append(path, node)
+		switch c := o.compare(v, node.Value()); {
+		case c == 0:
+			return path, true
+		case c < 0 && node.HasLeft():
+			node = node.Left()
+		case c > 0 && node.HasRight():
+			node = node.Right()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// Sibling returns the value of the other child of v's parent in the given tree.
+//
+// If v is not in the tree, is the root, or its parent has only the one child,
+// false is returned.
+//
+// The tree is searched by its own ordering. A node on its own is taken to be
+// in ascending natural order, so pass the tree itself, rather than its root,
+// for any other. The same goes for Grandparent, Uncle and InsertSide.
+func Sibling[T any](t Tree[T], v T) (T, bool) {
+	var zero T
+	path, ok := binaryTreePath(t, v)
+	if !ok || len(path) < 2 {
+		return zero, false
+	}
+
+	return childOpposite(path[len(path)-2], path[len(path)-1])
+}
+
+// Grandparent returns the value of the parent of v's parent in the given tree.
+//
+// If v is not in the tree or is less than two levels below the root, false
+// is returned.
+func Grandparent[T any](t Tree[T], v T) (T, bool) {
+	var zero T
+	path, ok := binaryTreePath(t, v)
+	if !ok || len(path) < 3 {
+		return zero, false
+	}
+
+	return path[len(path)-3].Value(), true
+}
+
+// Uncle returns the value of the sibling of v's parent in the given tree.
+//
+// If v has no grandparent, or its grandparent has only the one child,
+// false is returned.
+func Uncle[T any](t Tree[T], v T) (T, bool) {
+	var zero T
+	path, ok := binaryTreePath(t, v)
+	if !ok || len(path) < 3 {
+		return zero, false
+	}
+
+	return childOpposite(path[len(path)-3], path[len(path)-2])
+}
+
+// InsertSide reports where v would be placed if it were inserted into the
+// given tree, without changing the tree. side is "left" or "right" and parent
+// is the value of the existing node v would become a child of.
+//
+// If v is already in the tree, or the tree is empty so v would become the
+// root, false is returned.
+func InsertSide[T any](t Tree[T], v T) (side string, parent T, ok bool) {
+	var zero T
+	node := rootOf(t)
+	if isTreeNil(node) {
+		return "", zero, false
+	}
+
+	o := orderingOf(t).resolve()
+	for {
+		switch c := o.compare(v, node.Value()); {
+		case c == 0:
+			return "", zero, false
+		case c < 0:
+			if !node.HasLeft() {
+				return "left", node.Value(), true
+			}
+			node = node.Left()
+		default:
+			if !node.HasRight() {
+				return "right", node.Value(), true
+			}
+			node = node.Right()
+		}
+	}
+}
+
+// childOpposite returns the value of the child of parent other than child, if
+// there is one.
+func childOpposite[T any](parent, child BinaryTree[T]) (T, bool) {
+	var zero T
+	if parent.HasLeft() && parent.Left() == child {
+		if !parent.HasRight() {
+			return zero, false
+		}
+		return parent.Right().Value(), true
+	}
+
+	if !parent.HasLeft() {
+		return zero, false
+	}
+	return parent.Left().Value(), true
+}
+
+// IsValidBST reports if the binary search tree ordering holds at every node
+// of the given tree, with all the values in each left subtree before the
+// node, and all the values in each right subtree after it.
+//
+// The tree's own ordering is used, so Descending trees and those created with
+// a comparison function are checked in their order. In trees that keep
+// duplicates, equal values may be on either side, as rotations can move them
+// there. A node on its own is checked in ascending natural order without
+// duplicates, so pass the tree itself, rather than its root, for any other.
+//
+// Rather than only comparing each node to its immediate children, the bounds
+// set by every ancestor are carried down, so a value placed on the wrong
+// side of a distant ancestor is caught. An empty tree is valid, as are trees
+// without nodes of their own to check, such as SyncTree.
+func IsValidBST[T any](t Tree[T]) bool {
+	root := rootOf(t)
+	if isTreeNil(root) {
+		return true
+	}
+	return validBSTBounds(root, nil, nil, orderingOf(t).resolve())
+}
+
+// validBSTBounds is the worker for IsValidBST, checking that every value in
+// the subtree is within the given bounds according to o, where nil is
+// unbounded. The bounds are exclusive unless o keeps duplicates.
+func validBSTBounds[T any](t BinaryTree[T], lo, hi *T, o ordering[T]) bool {
+	v := t.Value()
+	if lo != nil {
+		if c := o.compare(v, *lo); c < 0 || (c == 0 && !o.duplicates) {
+			return false
+		}
+	}
+	if hi != nil {
+		if c := o.compare(v, *hi); c > 0 || (c == 0 && !o.duplicates) {
+			return false
+		}
+	}
+	if t.HasLeft() && !validBSTBounds(t.Left(), lo, &v, o) {
+		return false
+	}
+	if t.HasRight() && !validBSTBounds(t.Right(), &v, hi, o) {
+		return false
+	}
+	return true
+}
+
+// IsBalanced reports if the heights of the two subtrees of every node in the
+// given tree differ by at most one. An empty tree is balanced.
+func IsBalanced[T any](t BinaryTree[T]) bool {
+	if isTreeNil(t) {
+		return true
+	}
+	_, ok := balancedHeight(t, nil)
+	return ok
+}
+
+// IsValidAVL reports if the given tree holds all the AVL tree invariants: it
+// must be a valid binary search tree, it must be balanced, and the balance
+// factor stored in each node must match the actual heights of its subtrees.
+//
+// Only trees made of AVL nodes can be valid, as other nodes have no balance
+// factors to check. As with IsValidBST, the tree's own ordering is used. An
+// empty tree is valid.
+func IsValidAVL[T any](t Tree[T]) bool {
+	root := rootOf(t)
+	if isTreeNil(root) {
+		return true
+	}
+	if !IsValidBST(t) {
+		return false
+	}
+
+	_, ok := balancedHeight(root, func(n BinaryTree[T], lh, rh int) bool {
+		node, isAVL := n.(*avlNode[T])
+		return isAVL && node.bf == rh-lh
+	})
+	return ok
+}
+
+// balancedHeight is the worker for IsBalanced and IsValidAVL, returning the
+// height of the subtree and if every node in it is balanced. The heights are
+// found bottom up in a single pass, rather than calling Height at each node.
+// If check is not nil, it is also called with every node and the heights of
+// its two subtrees, and must return true for the subtree to be balanced.
+func balancedHeight[T any](t BinaryTree[T], check func(n BinaryTree[T], lh, rh int) bool) (int, bool) {
+	var lh, rh int
+	if t.HasLeft() {
+		h, ok := balancedHeight(t.Left(), check)
+		if !ok {
+			return 0, false
+		}
+		lh = h
+	}
+	if t.HasRight() {
+		h, ok := balancedHeight(t.Right(), check)
+		if !ok {
+			return 0, false
+		}
+		rh = h
+	}
+
+	if lh-rh > 1 || rh-lh > 1 {
+		return 0, false
+	}
+	if check != nil && !check(t, lh, rh) {
+		return 0, false
+	}
+	return max(lh, rh) + 1, true
+}
+
+// Flatten rearranges the given tree in place into a right leaning chain of
+// its nodes in pre order, with every left child nil, and returns the root of
+// the chain, which is the same node as the root of the tree. This is the
+// first step of some rebalancing algorithms, such as Day-Stout-Warren.
+//
+// A chain in pre order is not in search order, so the tree can no longer be
+// searched. The colors of a Red-Black tree and the priorities of a Treap no
+// longer hold their invariants either, while AVL nodes have their balance
+// factors, sizes, heights and parents updated to match the chain.
+//
+// Nodes of types outside this package can't be changed in place, so for them
+// a flattened copy backed by plain nodes is returned instead.
+func Flatten[T any](t BinaryTree[T]) BinaryTree[T] {
+	if isTreeNil(t) {
+		return t
+	}
+
+	switch n := t.(type) {
+	case *bstNode[T]:
+		flattenNodes(n, (*bstNode[T]).childFields)
+	case *treapNode[T]:
+		flattenNodes(n, (*treapNode[T]).childFields)
+	case *redBlackNode[T]:
+		flattenNodes(n, (*redBlackNode[T]).childFields)
+	case *avlNode[T]:
+		flattenNodes(n, (*avlNode[T]).childFields)
+
+		// Each node is now the only child of the one before it.
+		var size int
+		for node := n; node != nil; node = node.right {
+			size++
+		}
+		for node, parent := n, n.parent; node != nil; node, parent = node.right, node {
+			node.parent = parent
+			node.size = size
+			node.height = size
+			node.bf = size - 1
+			size--
+		}
+	default:
+		var head, tail *bstNode[T]
+		walkBinaryTree(t, TraversePreOrder, func(v T) bool {
+			node := &bstNode[T]{value: v}
+			if head == nil {
+				head = node
+			} else {
+				tail.right = node
+			}
+			tail = node
+			return true
+		})
+		return head
+	}
+	return t
+}
+
+// flattenNodes is the worker for Flatten, given the childFields method of the
+// type of node, which gives the addresses of the child pointers to set.
+// Each left subtree is moved in between its parent and the right subtree,
+// hung from the right end of its own right spine. This takes O(n) time and no
+// extra space.
+func flattenNodes[N comparable](root N, children func(N) (left, right *N)) {
+	var none N
+	for n := root; n != none; {
+		left, right := children(n)
+		if *left != none {
+			last := *left
+			for {
+				_, r := children(last)
+				if *r == none {
+					break
+				}
+				last = *r
+			}
+			_, lastRight := children(last)
+			*lastRight = *right
+			*right = *left
+			*left = none
+		}
+		n = *right
+	}
+}
+
+// morrisInOrder appends the values of the tree with the given root onto out
+// in order, and returns the extended slice, using Morris traversal. Rather
+// than keeping a stack of the nodes to come back to, each node is reached
+// again by a temporary thread, a right pointer set from the last node of its
+// left subtree back up to it. Each thread is removed on the second visit, so
+// the tree is left exactly as it was, having used O(1) extra space.
+//
+// children is the childFields method of the type of node, as for
+// flattenNodes.
+func morrisInOrder[N comparable, T any](root N, children func(N) (left, right *N), value func(N) T, out []T) []T {
+	var none N
+	for n := root; n != none; {
+		left, right := children(n)
+		if *left == none {
+			out = appendCopies(out, value(n), copiesOf(n))
+			n = *right
+			continue
+		}
+
+		// Find the last node of the left subtree, which comes just before
+		// n in order, or is already threaded back to it.
+		pred := *left
+		for {
+			_, r := children(pred)
+			if *r == none || *r == n {
+				break
+			}
+			pred = *r
+		}
+
+		_, predRight := children(pred)
+		if *predRight == none {
+			*predRight = n
+			n = *left
+		} else {
+			// The left subtree is done, so remove the thread.
+			*predRight = none
+			out = appendCopies(out, value(n), copiesOf(n))
+			n = *right
+		}
+	}
+	return out
+}
+
+// IsNil reports if the given tree is nil. This is the case both for a nil
+// interface, and for an interface holding a nil node, such as the root of an
+// empty tree or a missing child of a node, which a plain t == nil check does
+// not catch.
+func IsNil[T any](t BinaryTree[T]) bool {
+	return isTreeNil(t)
+}
+
 // isTreeNil checks if the tree generic instance the interface type is
 // pointing to a nil.
 //
@@ -112,8 +490,8 @@ func traverseBinaryTreeStructure[T constraints.Ordered](tree BinaryTree[T], ch c
 // https://go.dev/doc/faq#nil_error.
 //
 // But we can check the Value of the type with reflection. Ideally this will
-// only be used in testing and debugging code such as dumpBinaryTree and not
-// in the normal code paths.
+// only be used once per call, such as at the start of a traversal or in
+// debugging code such as dumpBinaryTree, and not for every node visited.
 func isTreeNil(a any) bool {
 	if a == nil {
 		return true
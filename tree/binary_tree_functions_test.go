@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -255,6 +256,695 @@ func TestBinaryTreesEquivalentAndEqual(t *testing.T) {
 	}
 }
 
+var (
+	//        21
+	//       /  \
+	//      1    42
+	//     / \     \
+	//   -13  11    84
+	//              /
+	//             57
+	bstNavTestTree = &BST[int]{
+		root: &bstNode[int]{
+			value: 21,
+			left: &bstNode[int]{
+				value: 1,
+				left: &bstNode[int]{
+					value: -13,
+				},
+				right: &bstNode[int]{
+					value: 11,
+				},
+			},
+			right: &bstNode[int]{
+				value: 42,
+				right: &bstNode[int]{
+					value: 84,
+					left: &bstNode[int]{
+						value: 57,
+					},
+				},
+			},
+		},
+	}
+)
+
+func TestIsValidBST(t *testing.T) {
+	tests := []struct {
+		name string
+		tree Tree[int]
+		want bool
+	}{
+		{
+			name: "empty",
+			tree: (&BST[int]{}).Root(),
+			want: true,
+		},
+		{
+			name: "single node",
+			tree: &bstNode[int]{value: 5},
+			want: true,
+		},
+		{
+			name: "ordered",
+			tree: bstNavTestTree.Root(),
+			want: true,
+		},
+		{
+			name: "child on the wrong side",
+			tree: &bstNode[int]{
+				value: 10,
+				left:  &bstNode[int]{value: 15},
+			},
+			want: false,
+		},
+		{
+			name: "duplicate value",
+			tree: &bstNode[int]{
+				value: 10,
+				right: &bstNode[int]{value: 10},
+			},
+			want: false,
+		},
+		{
+			// Every node is in order with its own children, but 25 is in
+			// the left subtree of 21.
+			//
+			//        21
+			//       /  \
+			//      1    42
+			//     / \
+			//   -13  11
+			//          \
+			//           25
+			name: "deep violation",
+			tree: &bstNode[int]{
+				value: 21,
+				left: &bstNode[int]{
+					value: 1,
+					left:  &bstNode[int]{value: -13},
+					right: &bstNode[int]{
+						value: 11,
+						right: &bstNode[int]{value: 25},
+					},
+				},
+				right: &bstNode[int]{value: 42},
+			},
+			want: false,
+		},
+		{
+			name: "AVL",
+			tree: avlTestTree.Root(),
+			want: true,
+		},
+		{
+			name: "descending",
+			tree: func() Tree[int] {
+				tree := NewAVL[int](Descending())
+				tree.InsertAll(21, 1, 42, -13, 11, 30, 84, 57)
+				return tree
+			}(),
+			want: true,
+		},
+		{
+			// The same nodes are out of order for an ascending tree.
+			name: "descending nodes on their own",
+			tree: func() Tree[int] {
+				tree := NewBST[int](Descending())
+				tree.InsertAll(21, 1, 42)
+				return rootOf(tree)
+			}(),
+			want: false,
+		},
+		{
+			// Rotations leave equal values on both sides of each other.
+			name: "duplicates",
+			tree: func() Tree[int] {
+				tree := NewAVL[int](IgnoreDuplicates(false))
+				tree.InsertAll(5, 5, 5, 5, 3, 3, 8)
+				return tree
+			}(),
+			want: true,
+		},
+		{
+			name: "duplicates out of order",
+			tree: &BST[int]{
+				root: &bstNode[int]{
+					value: 10,
+					right: &bstNode[int]{
+						value: 10,
+						left:  &bstNode[int]{value: 9},
+					},
+				},
+				order: ordering[int]{duplicates: true},
+			},
+			want: false,
+		},
+		{
+			name: "comparison function",
+			tree: func() Tree[int] {
+				tree := NewBSTFunc(func(a, b int) int { return a%10 - b%10 })
+				tree.InsertAll(15, 21, 9, 33)
+				return tree
+			}(),
+			want: true,
+		},
+	}
+
+	for _, test := range tests {
+		if got := IsValidBST(test.tree); got != test.want {
+			t.Errorf("%s: IsValidBST() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestIsBalanced(t *testing.T) {
+	tests := []struct {
+		name string
+		tree BinaryTree[int]
+		want bool
+	}{
+		{
+			name: "empty",
+			tree: (&BST[int]{}).Root(),
+			want: true,
+		},
+		{
+			name: "single node",
+			tree: &bstNode[int]{value: 5},
+			want: true,
+		},
+		{
+			name: "full",
+			tree: newBSTWith(42, 21, 84, 1, 30, 57, 99).Root(),
+			want: true,
+		},
+		{
+			name: "AVL",
+			tree: avlTestTree.Root(),
+			want: true,
+		},
+		{
+			name: "chain of three",
+			tree: newBSTWith(1, 2, 3).Root(),
+			want: false,
+		},
+		{
+			// The root is balanced, with both subtrees of height 3, but 1
+			// is not.
+			//
+			//        21
+			//       /  \
+			//      1    42
+			//       \   / \
+			//       11 30  84
+			//        \      \
+			//        12     99
+			name: "unbalanced below the root",
+			tree: &bstNode[int]{
+				value: 21,
+				left: &bstNode[int]{
+					value: 1,
+					right: &bstNode[int]{
+						value: 11,
+						right: &bstNode[int]{value: 12},
+					},
+				},
+				right: &bstNode[int]{
+					value: 42,
+					left:  &bstNode[int]{value: 30},
+					right: &bstNode[int]{
+						value: 84,
+						right: &bstNode[int]{value: 99},
+					},
+				},
+			},
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := IsBalanced(test.tree); got != test.want {
+			t.Errorf("%s: IsBalanced() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestIsValidAVL(t *testing.T) {
+	inserted := &AVL[int]{}
+	for i := 0; i < 100; i++ {
+		inserted.Insert((i * 37) % 101)
+	}
+
+	tests := []struct {
+		name string
+		tree Tree[int]
+		want bool
+	}{
+		{
+			name: "empty",
+			tree: (&AVL[int]{}).Root(),
+			want: true,
+		},
+		{
+			name: "hand built",
+			tree: avlTestTree.Root(),
+			want: true,
+		},
+		{
+			name: "built by insert",
+			tree: inserted.Root(),
+			want: true,
+		},
+		{
+			name: "built from sorted",
+			tree: NewAVLFromSorted([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}).Root(),
+			want: true,
+		},
+		{
+			name: "descending",
+			tree: func() Tree[int] {
+				tree := NewAVL[int](Descending())
+				for i := 0; i < 100; i++ {
+					tree.Insert((i * 37) % 101)
+				}
+				return tree
+			}(),
+			want: true,
+		},
+		{
+			name: "not AVL nodes",
+			tree: newBSTWith(2, 1, 3).Root(),
+			want: false,
+		},
+		{
+			name: "out of order",
+			tree: &avlNode[int]{
+				value: 2,
+				left:  &avlNode[int]{value: 3},
+				right: &avlNode[int]{value: 1},
+			},
+			want: false,
+		},
+		{
+			name: "unbalanced",
+			tree: &avlNode[int]{
+				value: 1,
+				bf:    2,
+				right: &avlNode[int]{
+					value: 2,
+					bf:    1,
+					right: &avlNode[int]{value: 3},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "wrong balance factor",
+			tree: &avlNode[int]{
+				value: 2,
+				bf:    0,
+				left:  &avlNode[int]{value: 1},
+			},
+			want: false,
+		},
+		{
+			name: "wrong balance factor below the root",
+			tree: &avlNode[int]{
+				value: 2,
+				bf:    -1,
+				left: &avlNode[int]{
+					value: 1,
+					bf:    1,
+				},
+			},
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := IsValidAVL(test.tree); got != test.want {
+			t.Errorf("%s: IsValidAVL() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSibling(t *testing.T) {
+	//          21
+	//        /    \
+	//      42      1
+	//     /  \    / \
+	//    57  30  11 -13
+	//   /
+	//  84
+	desc := NewAVL[int](Descending())
+	desc.InsertAll(21, 1, 42, -13, 11, 30, 84, 57)
+
+	tests := []struct {
+		tree   Tree[int]
+		val    int
+		want   int
+		wantOK bool
+	}{
+		{
+			// Empty tree.
+			tree:   (&BST[int]{}).Root(),
+			val:    5,
+			wantOK: false,
+		},
+		{
+			// Root has no sibling.
+			tree:   bstNavTestTree.Root(),
+			val:    21,
+			wantOK: false,
+		},
+		{
+			tree:   bstNavTestTree.Root(),
+			val:    1,
+			want:   42,
+			wantOK: true,
+		},
+		{
+			tree:   bstNavTestTree.Root(),
+			val:    11,
+			want:   -13,
+			wantOK: true,
+		},
+		{
+			// Only child.
+			tree:   bstNavTestTree.Root(),
+			val:    84,
+			wantOK: false,
+		},
+		{
+			// Value not in the tree.
+			tree:   bstNavTestTree.Root(),
+			val:    50,
+			wantOK: false,
+		},
+		{
+			// A Descending tree is searched in its own order.
+			tree:   desc,
+			val:    30,
+			want:   57,
+			wantOK: true,
+		},
+		{
+			tree:   desc,
+			val:    -13,
+			want:   11,
+			wantOK: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := Sibling(test.tree, test.val)
+		if ok != test.wantOK || got != test.want {
+			t.Errorf("Sibling(%v) = %v, %v, want %v, %v",
+				test.val, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestGrandparentAndUncle(t *testing.T) {
+	tests := []struct {
+		tree            BinaryTree[int]
+		val             int
+		wantGrandparent int
+		wantGrandOK     bool
+		wantUncle       int
+		wantUncleOK     bool
+	}{
+		{
+			// Empty tree.
+			tree: (&BST[int]{}).Root(),
+			val:  5,
+		},
+		{
+			// The root has neither.
+			tree: bstNavTestTree.Root(),
+			val:  21,
+		},
+		{
+			// Children of the root have neither.
+			tree: bstNavTestTree.Root(),
+			val:  42,
+		},
+		{
+			tree:            bstNavTestTree.Root(),
+			val:             11,
+			wantGrandparent: 21,
+			wantGrandOK:     true,
+			wantUncle:       42,
+			wantUncleOK:     true,
+		},
+		{
+			tree:            bstNavTestTree.Root(),
+			val:             84,
+			wantGrandparent: 21,
+			wantGrandOK:     true,
+			wantUncle:       1,
+			wantUncleOK:     true,
+		},
+		{
+			// Grandparent exists, but the parent is an only child.
+			tree:            bstNavTestTree.Root(),
+			val:             57,
+			wantGrandparent: 42,
+			wantGrandOK:     true,
+			wantUncleOK:     false,
+		},
+		{
+			// Value not in the tree.
+			tree: bstNavTestTree.Root(),
+			val:  60,
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := Grandparent(test.tree, test.val)
+		if ok != test.wantGrandOK || got != test.wantGrandparent {
+			t.Errorf("Grandparent(%v) = %v, %v, want %v, %v",
+				test.val, got, ok, test.wantGrandparent, test.wantGrandOK)
+		}
+
+		got, ok = Uncle(test.tree, test.val)
+		if ok != test.wantUncleOK || got != test.wantUncle {
+			t.Errorf("Uncle(%v) = %v, %v, want %v, %v",
+				test.val, got, ok, test.wantUncle, test.wantUncleOK)
+		}
+	}
+}
+
+func TestInsertSide(t *testing.T) {
+	tests := []struct {
+		tree       Tree[int]
+		val        int
+		wantSide   string
+		wantParent int
+		wantOK     bool
+	}{
+		{
+			// Empty tree, the value would be the root.
+			tree: (&BST[int]{}).Root(),
+			val:  5,
+		},
+		{
+			// Already present.
+			tree: bstNavTestTree.Root(),
+			val:  42,
+		},
+		{
+			tree:       bstNavTestTree.Root(),
+			val:        5,
+			wantSide:   "left",
+			wantParent: 11,
+			wantOK:     true,
+		},
+		{
+			tree:       bstNavTestTree.Root(),
+			val:        30,
+			wantSide:   "left",
+			wantParent: 42,
+			wantOK:     true,
+		},
+		{
+			tree:       bstNavTestTree.Root(),
+			val:        -20,
+			wantSide:   "left",
+			wantParent: -13,
+			wantOK:     true,
+		},
+		{
+			tree:       bstNavTestTree.Root(),
+			val:        60,
+			wantSide:   "right",
+			wantParent: 57,
+			wantOK:     true,
+		},
+		{
+			tree:       bstNavTestTree.Root(),
+			val:        100,
+			wantSide:   "right",
+			wantParent: 84,
+			wantOK:     true,
+		},
+		{
+			// The direction is read from the tree, not guessed from
+			// the values of a root that has no children to go by.
+			tree: func() Tree[int] {
+				tree := NewBST[int](Descending())
+				tree.Insert(5)
+				return tree
+			}(),
+			val:        7,
+			wantSide:   "left",
+			wantParent: 5,
+			wantOK:     true,
+		},
+		{
+			// Nor from equal values.
+			tree: func() Tree[int] {
+				tree := NewBST[int](Descending(), IgnoreDuplicates(false))
+				tree.InsertAll(5, 5)
+				return tree
+			}(),
+			val:        7,
+			wantSide:   "left",
+			wantParent: 5,
+			wantOK:     true,
+		},
+		{
+			tree: func() Tree[int] {
+				tree := NewAVL[int](Descending())
+				tree.InsertAll(21, 1, 42, -13, 11, 30, 84, 57)
+				return tree
+			}(),
+			val:        60,
+			wantSide:   "right",
+			wantParent: 84,
+			wantOK:     true,
+		},
+		{
+			// Trees with a comparison function are walked by it.
+			tree: func() Tree[int] {
+				tree := NewBSTFunc(byAbs)
+				tree.InsertAll(3, -5)
+				return tree
+			}(),
+			val:        -4,
+			wantSide:   "left",
+			wantParent: -5,
+			wantOK:     true,
+		},
+		{
+			tree: func() Tree[int] {
+				tree := NewBSTFunc(byAbs)
+				tree.InsertAll(3, -5)
+				return tree
+			}(),
+			val: 5,
+		},
+	}
+
+	for _, test := range tests {
+		side, parent, ok := InsertSide(test.tree, test.val)
+		if side != test.wantSide || parent != test.wantParent || ok != test.wantOK {
+			t.Errorf("InsertSide(%v) = %q, %v, %v, want %q, %v, %v",
+				test.val, side, parent, ok, test.wantSide, test.wantParent, test.wantOK)
+		}
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	avl := &AVL[int]{}
+	for _, v := range vals {
+		avl.Insert(v)
+	}
+	rb := NewRedBlack[int]()
+	rb.InsertAll(vals...)
+	treap := NewTreap[int](WithRandSource(rand.NewSource(1)))
+	treap.InsertAll(vals...)
+
+	tests := []struct {
+		name string
+		root BinaryTree[int]
+	}{
+		{name: "BST", root: newBSTWith(vals...).Root()},
+		{name: "AVL", root: avl.Root()},
+		{name: "RedBlack", root: rb.(*RedBlack[int]).Root()},
+		{name: "Treap", root: treap.(*Treap[int]).Root()},
+		{name: "single node", root: &bstNode[int]{value: 7}},
+		{name: "already flat", root: newBSTWith(1, 2, 3, 4).Root()},
+		// A wrapped node is not one Flatten can change in place.
+		{name: "other node type", root: withSizes(newBSTWith(vals...).Root())},
+	}
+
+	for _, test := range tests {
+		want := appendValues(nil, test.root, TraversePreOrder)
+
+		got := Flatten(test.root)
+		if _, ok := test.root.(*sizedNode[int]); !ok && got != test.root {
+			t.Errorf("%s: Flatten() returned a different root", test.name)
+		}
+
+		var chain []int
+		for n := got; !isTreeNil(n); n = n.Right() {
+			if n.HasLeft() {
+				t.Errorf("%s: Flatten() left node %v with a left child", test.name, n.Value())
+			}
+			chain = append(chain, n.Value())
+		}
+		if !cmp.Equal(chain, want) {
+			t.Errorf("%s: Flatten() chain = %v, want the pre order %v", test.name, chain, want)
+		}
+		if got := appendValues(nil, got, TraversePreOrder); !cmp.Equal(got, want) {
+			t.Errorf("%s: pre order after Flatten() = %v, want %v", test.name, got, want)
+		}
+	}
+
+	// The AVL nodes are kept consistent with their new shape.
+	if got, want := avl.Height(), len(vals); got != want {
+		t.Errorf("AVL Height() after Flatten() = %d, want %d", got, want)
+	}
+	if got, ok := avl.Select(0); got != 21 || !ok {
+		t.Errorf("AVL Select(0) after Flatten() = %v, %v, want 21, true", got, ok)
+	}
+	for n := avl.root; n.right != nil; n = n.right {
+		if n.right.parent != n {
+			t.Errorf("AVL node %v after Flatten() has parent %v, want %v", n.right.value, n.right.parent.value, n.value)
+		}
+	}
+
+	if got := Flatten[int](nil); got != nil {
+		t.Errorf("Flatten(nil) = %v, want nil", got)
+	}
+}
+
+func TestIsNil(t *testing.T) {
+	tests := []struct {
+		name string
+		tree BinaryTree[int]
+		want bool
+	}{
+		{name: "nil interface", tree: nil, want: true},
+		{name: "nil BST node", tree: (*bstNode[int])(nil), want: true},
+		{name: "nil AVL node", tree: (*avlNode[int])(nil), want: true},
+		{name: "empty tree root", tree: (&RedBlack[int]{}).Root(), want: true},
+		{name: "missing child", tree: (&bstNode[int]{value: 1}).Left(), want: true},
+		{name: "node", tree: &treapNode[int]{value: 1}, want: false},
+		{name: "populated tree root", tree: newBSTWith(2, 1).Root(), want: false},
+	}
+
+	for _, test := range tests {
+		if got := IsNil(test.tree); got != test.want {
+			t.Errorf("IsNil(%s) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
 func TestBinaryTreeStructure(t *testing.T) {
 	tests := []struct {
 		tree *BST[int]
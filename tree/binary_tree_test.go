@@ -1,4 +1,338 @@
 package tree
 
-// TODO(rsned): Remaining methods to test.
-// traverseBinaryTree
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/exp/constraints"
+)
+
+// consistencyOrders are the traverse orders compared by
+// CheckTraversalConsistency.
+var consistencyOrders = []TraverseOrder{
+	TraverseInOrder,
+	TraversePreOrder,
+	TraversePostOrder,
+	TraverseReverseOrder,
+	TraverseLevelOrder,
+}
+
+// CheckTraversalConsistency is a regression guard that checks the different
+// traverse orders of the given tree agree with each other. Every order must
+// emit the same values, the reverse order must be the exact reverse of the in
+// order traversal, and the root must come first in pre order and last in post
+// order.
+func CheckTraversalConsistency[T constraints.Ordered](t BinaryTree[T]) error {
+	if isTreeNil(t) {
+		return nil
+	}
+
+	got := map[TraverseOrder][]T{}
+	for _, order := range consistencyOrders {
+		for v := range t.Traverse(order) {
+			got[order] = append(got[order], v)
+		}
+	}
+
+	in := got[TraverseInOrder]
+	reversed := slices.Clone(in)
+	slices.Reverse(reversed)
+	if rev := got[TraverseReverseOrder]; !slices.Equal(rev, reversed) {
+		return fmt.Errorf("%v values %v are not the reverse of %v values %v",
+			TraverseReverseOrder, rev, TraverseInOrder, in)
+	}
+
+	// Compare the values as multisets.
+	want := slices.Clone(in)
+	slices.Sort(want)
+	for _, order := range consistencyOrders {
+		vals := slices.Clone(got[order])
+		slices.Sort(vals)
+		if !slices.Equal(vals, want) {
+			return fmt.Errorf("%v values %v do not match %v values %v",
+				order, got[order], TraverseInOrder, in)
+		}
+	}
+
+	root := t.Value()
+	if pre := got[TraversePreOrder]; pre[0] != root {
+		return fmt.Errorf("%v starts with %v, want the root %v", TraversePreOrder, pre[0], root)
+	}
+	if post := got[TraversePostOrder]; post[len(post)-1] != root {
+		return fmt.Errorf("%v ends with %v, want the root %v", TraversePostOrder, post[len(post)-1], root)
+	}
+
+	return nil
+}
+
+func TestTraverseBinaryTreeDegenerate(t *testing.T) {
+	const n = 100000
+
+	// Inserting sorted values leaves a BST as one long right leaning chain.
+	// It is built directly, as inserting each value would walk the chain.
+	vals := make([]int, n)
+	for i := range vals {
+		vals[i] = i
+	}
+	root := &bstNode[int]{value: vals[0]}
+	for node, i := root, 1; i < n; i++ {
+		node.right = &bstNode[int]{value: vals[i]}
+		node = node.right
+	}
+	tree := &BST[int]{root: root, size: n}
+
+	reversed := slices.Clone(vals)
+	slices.Reverse(reversed)
+
+	tests := []struct {
+		order TraverseOrder
+		want  []int
+	}{
+		{order: TraverseInOrder, want: vals},
+		{order: TraversePreOrder, want: vals},
+		{order: TraversePostOrder, want: reversed},
+		{order: TraverseReverseOrder, want: reversed},
+	}
+	for _, test := range tests {
+		got := make([]int, 0, n)
+		for v := range tree.Traverse(test.order) {
+			got = append(got, v)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Traverse(%v) of a %d node chain did not give the expected values", test.order, n)
+		}
+	}
+}
+
+func TestTraverseBinaryTreeNil(t *testing.T) {
+	nils := []BinaryTree[int]{
+		nil,
+		(*bstNode[int])(nil),
+		(*avlNode[int])(nil),
+		(*redBlackNode[int])(nil),
+		(*treapNode[int])(nil),
+	}
+
+	for _, tree := range nils {
+		for _, order := range consistencyOrders {
+			ch := make(chan int)
+			go func() {
+				traverseBinaryTree(tree, order, ch)
+				close(ch)
+			}()
+
+			var got []int
+			for v := range ch {
+				got = append(got, v)
+			}
+			if len(got) != 0 {
+				t.Errorf("traverseBinaryTree(%T(nil), %v) = %v, want no values", tree, order, got)
+			}
+		}
+	}
+}
+
+func TestInOrderInto(t *testing.T) {
+	tests := []struct {
+		tree BinaryTree[int]
+		have []int
+		want []int
+	}{
+		{
+			tree: (&BST[int]{}).Root(),
+			want: nil,
+		},
+		{
+			tree: bstNavTestTree.Root(),
+			want: []int{-13, 1, 11, 21, 42, 57, 84},
+		},
+		{
+			// Values are appended after any existing contents.
+			tree: avlTestTree.Root(),
+			have: []int{-100},
+			want: []int{-100, -13, 1, 11, 21, 30, 42, 57, 84, 90},
+		},
+	}
+
+	for _, test := range tests {
+		got := test.have
+		InOrderInto(test.tree, &got)
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("InOrderInto(%v) = %v, want %v", test.have, got, test.want)
+		}
+	}
+}
+
+func TestSuccinctEncodeDecode(t *testing.T) {
+	tests := []struct {
+		tree      BinaryTree[int]
+		wantShape []byte
+	}{
+		{
+			tree:      (&BST[int]{}).Root(),
+			wantShape: []byte{0x00},
+		},
+		{
+			tree: (&BST[int]{
+				root: &bstNode[int]{
+					value: 42,
+				},
+			}).Root(),
+			// 100
+			wantShape: []byte{0x80},
+		},
+		{
+			// 111001001011000
+			tree:      bstNavTestTree.Root(),
+			wantShape: []byte{0xe4, 0xb0},
+		},
+		{
+			tree: avlTestTree.Root(),
+		},
+	}
+
+	for _, test := range tests {
+		shape, values := SuccinctEncode(test.tree)
+		if test.wantShape != nil && !cmp.Equal(shape, test.wantShape) {
+			t.Errorf("SuccinctEncode(%v) shape = %08b, want %08b",
+				test.tree, shape, test.wantShape)
+		}
+
+		got, ok := SuccinctDecode(shape, values)
+		if !ok {
+			t.Errorf("SuccinctDecode(%08b, %v) failed", shape, values)
+			continue
+		}
+		if !binaryTreesEqual(got, test.tree) {
+			t.Errorf("SuccinctDecode(SuccinctEncode(%v)) = %v, want trees to be equal",
+				test.tree, got)
+		}
+	}
+}
+
+func TestSuccinctDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		shape  []byte
+		values []int
+	}{
+		{
+			// No shape at all.
+			shape: nil,
+		},
+		{
+			// Node without values.
+			shape: []byte{0x80},
+		},
+		{
+			// Extra values.
+			shape:  []byte{0x80},
+			values: []int{1, 2},
+		},
+		{
+			// Shape ends before the tree is complete.
+			shape:  []byte{0xff},
+			values: []int{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		{
+			// Trailing bytes after the tree.
+			shape:  []byte{0x80, 0x00},
+			values: []int{1},
+		},
+	}
+
+	for _, test := range tests {
+		if _, ok := SuccinctDecode(test.shape, test.values); ok {
+			t.Errorf("SuccinctDecode(%08b, %v) = _, true, want false",
+				test.shape, test.values)
+		}
+	}
+}
+
+func TestToFromLevelArray(t *testing.T) {
+	p := func(v int) *int { return &v }
+
+	tests := []struct {
+		name string
+		tree *BST[int]
+		want []*int
+	}{
+		{
+			name: "empty",
+			tree: &BST[int]{},
+			want: nil,
+		},
+		{
+			name: "single node",
+			tree: newBSTWith(42),
+			want: []*int{p(42)},
+		},
+		{
+			name: "balanced",
+			tree: newBSTWith(4, 2, 6, 1, 3, 5, 7),
+			want: []*int{p(4), p(2), p(6), p(1), p(3), p(5), p(7)},
+		},
+		{
+			name: "sparse",
+			tree: newBSTWith(10, 5, 20, 15),
+			want: []*int{p(10), p(5), p(20), nil, nil, p(15)},
+		},
+		{
+			name: "right leaning",
+			tree: newBSTWith(1, 2, 3),
+			want: []*int{p(1), nil, p(2), nil, nil, nil, p(3)},
+		},
+	}
+
+	for _, test := range tests {
+		got := ToLevelArray(test.tree.Root())
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("%s: ToLevelArray() = %v, want %v", test.name, got, test.want)
+		}
+
+		back := FromLevelArray(got)
+		if !binaryTreesEqual(back.Root(), test.tree.Root()) {
+			t.Errorf("%s: FromLevelArray(ToLevelArray()) = %v, want %v", test.name, back, test.tree)
+		}
+		if back.Size() != test.tree.Size() {
+			t.Errorf("%s: FromLevelArray(ToLevelArray()).Size() = %d, want %d", test.name, back.Size(), test.tree.Size())
+		}
+	}
+
+	// Entries with no parent are dropped.
+	tree := FromLevelArray([]*int{p(2), nil, p(3), p(1)})
+	if got, want := ToLevelArray(tree.Root()), []*int{p(2), nil, p(3)}; !cmp.Equal(got, want) {
+		t.Errorf("FromLevelArray(orphaned entry) = %v, want %v", got, want)
+	}
+	if got := tree.Size(); got != 2 {
+		t.Errorf("FromLevelArray(orphaned entry).Size() = %d, want 2", got)
+	}
+}
+
+func TestToLevelArrayDegenerate(t *testing.T) {
+	// A right leaning chain of n nodes puts its last node at 2^n - 2.
+	chain := func(n int) *BST[int] {
+		tree := &BST[int]{}
+		for i := 0; i < n; i++ {
+			tree.Insert(i)
+		}
+		return tree
+	}
+
+	arr := ToLevelArray(chain(16).Root())
+	if got, want := len(arr), 1<<16-1; got != want {
+		t.Fatalf("len(ToLevelArray(16 node chain)) = %d, want %d", got, want)
+	}
+	if got := arr[len(arr)-1]; got == nil || *got != 15 {
+		t.Errorf("ToLevelArray(16 node chain) ends with %v, want 15", got)
+	}
+
+	// Taller chains would need more than MaxLevelArrayLen entries, or far
+	// more memory than exists.
+	for _, n := range []int{25, 70} {
+		if got := ToLevelArray(chain(n).Root()); got != nil {
+			t.Errorf("ToLevelArray(%d node chain) has %d entries, want nil", n, len(got))
+		}
+	}
+}
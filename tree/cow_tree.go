@@ -0,0 +1,120 @@
+package tree
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/exp/constraints"
+)
+
+// COWTree is a copy-on-write tree that is safe for concurrent use by multiple
+// goroutines. It holds the current version of a PersistentBST behind an
+// atomic pointer. Readers load the pointer and read that version without
+// taking any lock, while writers build the next version and publish it in
+// one store.
+//
+// Writers are serialized by a mutex, so none of their work is thrown away,
+// but they never block readers. This suits trees that are read far more
+// often than they are changed. As with PersistentBST, no balancing is done.
+type COWTree[T any] struct {
+	// mu serializes the writers.
+	mu sync.Mutex
+
+	current atomic.Pointer[PersistentBST[T]]
+}
+
+// NewCOWTree returns an empty COWTree ready to use.
+//
+// Options can include Descending to order the values largest first, and
+// IgnoreDuplicates(false) to keep every copy of equal values inserted.
+func NewCOWTree[T constraints.Ordered](opts ...treeOptionFunc) *COWTree[T] {
+	t := &COWTree[T]{}
+	t.current.Store(NewPersistentBST[T](opts...))
+	return t
+}
+
+// Snapshot returns the current version of the tree. It is never changed, so
+// it can be read at leisure while writers carry on.
+func (t *COWTree[T]) Snapshot() *PersistentBST[T] {
+	return t.current.Load()
+}
+
+// update stores the version fn returns given the current one, under the
+// writer lock.
+func (t *COWTree[T]) update(fn func(*PersistentBST[T]) *PersistentBST[T]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current.Store(fn(t.current.Load()))
+}
+
+// Insert adds the given value into the tree.
+// If the value could not be added, false is returned.
+func (t *COWTree[T]) Insert(v T) bool {
+	var ok bool
+	t.update(func(cur *PersistentBST[T]) *PersistentBST[T] {
+		next := cur.Insert(v)
+		ok = next != cur
+		return next
+	})
+	return ok
+}
+
+// InsertAll adds the given values into the tree, returning how many of them
+// were added. Readers see either none of the values or all of them.
+func (t *COWTree[T]) InsertAll(vals ...T) int {
+	var n int
+	t.update(func(cur *PersistentBST[T]) *PersistentBST[T] {
+		next := cur.InsertAll(vals...)
+		n = next.Size() - cur.Size()
+		return next
+	})
+	return n
+}
+
+// Delete the requested node from the tree and reports if it was successful.
+// If the value is not in the tree, the tree is unchanged and false is returned.
+func (t *COWTree[T]) Delete(v T) bool {
+	var ok bool
+	t.update(func(cur *PersistentBST[T]) *PersistentBST[T] {
+		next := cur.Delete(v)
+		ok = next != cur
+		return next
+	})
+	return ok
+}
+
+// Search reports if the given value is in the tree.
+func (t *COWTree[T]) Search(v T) bool {
+	return t.Snapshot().Search(v)
+}
+
+// Height returns the height of the longest path in the tree from the
+// root node to the farthest leaf.
+func (t *COWTree[T]) Height() int {
+	return t.Snapshot().Height()
+}
+
+// Size returns the number of values held in the tree.
+func (t *COWTree[T]) Size() int {
+	return t.Snapshot().Size()
+}
+
+// IsEmpty reports if the tree holds no values.
+func (t *COWTree[T]) IsEmpty() bool {
+	return t.Snapshot().IsEmpty()
+}
+
+// Traverse traverses the tree in the specified order emitting the values to
+// the channel. Channel is closed once the final value is emitted.
+//
+// The values are those of the version current when Traverse is called, so
+// changes made to the tree after it returns are not seen.
+func (t *COWTree[T]) Traverse(tOrder TraverseOrder) <-chan T {
+	return t.Snapshot().Traverse(tOrder)
+}
+
+// Root returns the root node of the current version of the tree. The nodes
+// are shared with other versions, so they can not be changed through it.
+func (t *COWTree[T]) Root() BinaryTree[T] {
+	return t.Snapshot().Root()
+}
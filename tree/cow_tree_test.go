@@ -0,0 +1,120 @@
+package tree
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestCOWTree(t *testing.T) {
+	tree := NewCOWTree[int]()
+	if !tree.IsEmpty() {
+		t.Errorf("IsEmpty() on a new tree = false, want true")
+	}
+
+	if got := tree.InsertAll(5, 3, 8, 3); got != 3 {
+		t.Errorf("InsertAll(5, 3, 8, 3) = %d, want 3", got)
+	}
+	if tree.Insert(5) {
+		t.Errorf("Insert(5) again = true, want false")
+	}
+
+	// A snapshot is not changed by later writes.
+	before := tree.Snapshot()
+	if !tree.Insert(1) {
+		t.Errorf("Insert(1) = false, want true")
+	}
+	if !tree.Delete(8) {
+		t.Errorf("Delete(8) = false, want true")
+	}
+	if tree.Delete(8) {
+		t.Errorf("Delete(8) again = true, want false")
+	}
+
+	if got, want := before.TraverseSlice(TraverseInOrder), []int{3, 5, 8}; !slices.Equal(got, want) {
+		t.Errorf("Snapshot() values = %v, want %v", got, want)
+	}
+	if got, want := ToSlice[int](tree), []int{1, 3, 5}; !slices.Equal(got, want) {
+		t.Errorf("values = %v, want %v", got, want)
+	}
+	if got := tree.Size(); got != 3 {
+		t.Errorf("Size() = %d, want 3", got)
+	}
+	if got := tree.Height(); got != 3 {
+		t.Errorf("Height() = %d, want 3", got)
+	}
+	if !tree.Search(1) || tree.Search(8) {
+		t.Errorf("Search(1), Search(8) = %v, %v, want true, false", tree.Search(1), tree.Search(8))
+	}
+
+	// The functions of the package use the ordering of the tree.
+	desc := NewCOWTree[int](Descending())
+	desc.InsertAll(1, 2, 3)
+	if got, want := ToSlice(Filter[int](desc, func(v int) bool { return v != 2 })), []int{3, 1}; !slices.Equal(got, want) {
+		t.Errorf("Filter() of a Descending tree = %v, want %v", got, want)
+	}
+}
+
+// Run with -race to check the readers take no lock.
+func TestCOWTreeConcurrent(t *testing.T) {
+	const (
+		writers   = 8
+		readers   = 8
+		perWriter = 500
+	)
+
+	tree := NewCOWTree[int]()
+
+	var writing sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		writing.Add(1)
+		go func(w int) {
+			defer writing.Done()
+			// Each writer inserts its own interleaved share of values.
+			for i := 0; i < perWriter; i++ {
+				tree.Insert(i*writers + w)
+			}
+			tree.InsertAll(w, w+writers)
+		}(w)
+	}
+
+	done := make(chan struct{})
+	var reading sync.WaitGroup
+	for r := 0; r < readers; r++ {
+		reading.Add(1)
+		go func(r int) {
+			defer reading.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				tree.Search(r)
+				tree.Height()
+
+				// Each version is whole, so its values are in order and
+				// match its size.
+				version := tree.Snapshot()
+				got := version.TraverseSlice(TraverseInOrder)
+				if !sort.IntsAreSorted(got) || len(got) != version.Size() {
+					t.Errorf("Snapshot() holds %d values of Size() %d, want as many sorted values",
+						len(got), version.Size())
+				}
+			}
+		}(r)
+	}
+
+	writing.Wait()
+	close(done)
+	reading.Wait()
+
+	if got, want := tree.Size(), writers*perWriter; got != want {
+		t.Errorf("Size() = %d, want %d", got, want)
+	}
+	got := ToSlice[int](tree)
+	if len(got) != writers*perWriter || !sort.IntsAreSorted(got) {
+		t.Errorf("Traverse() emitted %d values, want %d sorted values", len(got), writers*perWriter)
+	}
+}
@@ -3,6 +3,7 @@ package tree
 import (
 	"bytes"
 	"fmt"
+	"html"
 	"strings"
 
 	"golang.org/x/exp/constraints"
@@ -10,27 +11,21 @@ import (
 
 // TODO(rsned): A list of potential enhancements.
 //
-// * Find widest node value to be able to change the overall height and width of
-//   the output tree. Shorter values/strings don't need as tall or wide of a tree.
 // * Find the breadth of a given subtree and use it to adjust the lateral width
 //   of higher up nodes.  e.g. when one side of a tree is not bushy, or is
 //   unbalanced, there is no need for lateral padding on higher nodes.
+//   (WithAdaptiveSpacing does this, but with a separate simpler layout.)
 // * Allow for pseudo-dynamic heights based on width of largest element in the tree.
 //   e.g. if the tree only has single letter / digit values, a leg height of 2-3
 //   would be plenty.
-// * Format a nodes content to be centered in the alloted space rather than left
-//   or right aligned.
-// * Node value and metadata printing are basically identical code blocks, figure
-//   out a way to refactor that.
 
 const (
 	// How wide is the unit of ascii art we are using.
 	elementWidth = 5
 
-	indent      = "     "
-	nodeFmt     = "~%3d~"
-	nodeFmtT    = "%3v"
-	nodeMetaFmt = "%5s"
+	indent   = "     "
+	nodeFmt  = "~%3d~"
+	nodeFmtT = "%3v"
 
 	leftLegBase  = "/"
 	rightLegBase = "\\"
@@ -131,33 +126,128 @@ type RenderMode int
 // Set of current render modes.
 const (
 	ModeASCII RenderMode = iota
+
+	// ModeSVG renders the tree as a standalone SVG image.
 	ModeSVG
 
+	// ModeDOT renders the tree as a Graphviz DOT graph, suitable for piping
+	// to a command such as dot -Tpng.
+	ModeDOT
+
+	// ModeIndent renders the tree as indented lines of text, one node per
+	// line, which works for trees of any shape and is easy to diff.
+	ModeIndent
+
 	// TODO(rsned): Add more modes?
 )
 
 // RenderBinaryTree returns the given tree in the given mode rendered into string form.
-func RenderBinaryTree[T constraints.Ordered](t BinaryTree[T], height int, mode RenderMode) string {
+//
+// Options can include WithAdaptiveSpacing to render unbalanced trees compactly,
+// and ShowSizes to annotate each node with the size of its subtree.
+func RenderBinaryTree[T constraints.Ordered](t BinaryTree[T], height int, mode RenderMode, opts ...treeOptionFunc) string {
+	treeOpts := defaultOptions()
+	for _, opt := range opts {
+		opt(treeOpts)
+	}
+
+	if treeOpts.showSizes && !isTreeNil(t) {
+		t = withSizes(t)
+	}
+
 	switch mode {
 	case ModeASCII:
+		if treeOpts.adaptiveSpacing {
+			return dumpBinaryTreeAdaptive(t)
+		}
 		return dumpBinaryTree("", t)
+	case ModeSVG:
+		return dumpBinaryTreeSVG(t)
+	case ModeDOT:
+		return dumpBinaryTreeDOT(t)
+	case ModeIndent:
+		return dumpBinaryTreeIndent(t)
 	default:
 		return "Method not implemented yet"
 	}
 }
 
-// dumpBinaryTree is a simple hacky way to output a binary tree up to 5 levels
-// for the purpose of aiding in testing and debugging.
+// sizedNode wraps a node of a tree being rendered, adding the number of nodes
+// in its subtree to its metadata. The children are wrapped in turn, so the
+// whole tree can be handed to any of the renderers unchanged.
+type sizedNode[T any] struct {
+	BinaryTree[T]
+
+	// size is the number of nodes in the subtree rooted at this node.
+	size int
+
+	left, right, parent *sizedNode[T]
+}
+
+// withSizes returns a copy of the shape of the given tree with every node
+// wrapped in a sizedNode. The sizes are counted in a single pass up from the
+// leaves, rather than asking each node for the Size of its subtree.
+func withSizes[T any](t BinaryTree[T]) *sizedNode[T] {
+	n := &sizedNode[T]{BinaryTree: t, size: 1}
+	if t.HasLeft() {
+		n.left = withSizes(t.Left())
+		n.left.parent = n
+		n.size += n.left.size
+	}
+	if t.HasRight() {
+		n.right = withSizes(t.Right())
+		n.right.parent = n
+		n.size += n.right.size
+	}
+	return n
+}
+
+// Left returns the wrapped Left child, if any, of this node.
+func (n *sizedNode[T]) Left() BinaryTree[T] {
+	if n.left == nil {
+		return nil
+	}
+	return n.left
+}
+
+// Right returns the wrapped Right child, if any, of this node.
+func (n *sizedNode[T]) Right() BinaryTree[T] {
+	if n.right == nil {
+		return nil
+	}
+	return n.right
+}
+
+// Parent returns the wrapped parent of this node, or nil for the root.
+func (n *sizedNode[T]) Parent() BinaryTree[T] {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+// Metadata returns the metadata of the wrapped node followed by the size of
+// its subtree.
+func (n *sizedNode[T]) Metadata() string {
+	if meta := n.BinaryTree.Metadata(); meta != "" {
+		return fmt.Sprintf("%s n:%d", meta, n.size)
+	}
+	return fmt.Sprintf("n:%d", n.size)
+}
+
+// dumpBinaryTree is a simple hacky way to output a binary tree for the purpose
+// of aiding in testing and debugging. There is no limit on the height of the
+// tree, but each level doubles the width of the output, so trees more than
+// 6 or 7 levels tall are best rendered with WithAdaptiveSpacing.
 //
-// This method takes a binary tree of integers with an explicit assumption the
-// values in the tree are 3 character or less wide when printed.
-// e.g. -21, 123, 7, etc.
+// The spacing is sized to fit the widest value in the tree when printed, with
+// narrower values centered in the same width.
 //
 // An optional label is output before the tree contents.
 func dumpBinaryTree[T constraints.Ordered](label string, t BinaryTree[T]) string {
 	var buf bytes.Buffer
-	// This doesn't work on interface to generic types.
-	// If the tree is nil, it skips this and crashes later on.
+	// A plain nil check doesn't work on an interface holding a nil node,
+	// which is what an empty tree gives, so isTreeNil is needed here.
 	if isTreeNil(t) {
 		return buf.String()
 	}
@@ -179,7 +269,6 @@ func dumpBinaryTree[T constraints.Ordered](label string, t BinaryTree[T]) string
 		outputLegs(nextNodes, indentOpts, &buf, depthFrom)
 
 		depthFrom--
-		indentOpts = optsForStats(depthFrom, stats.widestValue)
 		nodes = nextNodes
 		outputNodes(nodes, indentOpts, &buf, depthFrom)
 	}
@@ -187,14 +276,444 @@ func dumpBinaryTree[T constraints.Ordered](label string, t BinaryTree[T]) string
 	return buf.String()
 }
 
+// adaptiveNode is a node placed in the adaptive layout.
+type adaptiveNode[T constraints.Ordered] struct {
+	node BinaryTree[T]
+
+	// col is the starting column of the slot holding this node.
+	col int
+
+	// left and right are the columns of the centers of the children's
+	// slots, or -1 if there is no child on that side.
+	left, right int
+}
+
+// dumpBinaryTreeAdaptive renders the tree with each node given its own column
+// slot in its in order position, so every subtree is exactly as wide as the
+// number of nodes in it. Sparse or one-sided subtrees then take up little
+// room, rather than the full 2^n width dumpBinaryTree reserves at each level.
+//
+// Unlike dumpBinaryTree, there is no limit on the height of the tree or the
+// width of the values.
+func dumpBinaryTreeAdaptive[T constraints.Ordered](t BinaryTree[T]) string {
+	var buf bytes.Buffer
+	if isTreeNil(t) {
+		return buf.String()
+	}
+
+	// The slot width fits the widest value or metadata plus a space
+	// between neighbors.
+	hasMeta := false
+	width := 0
+	var widths func(n BinaryTree[T])
+	widths = func(n BinaryTree[T]) {
+		width = max(width, len(fmt.Sprintf(nodeFmtT, n.Value())), len(n.Metadata()))
+		hasMeta = hasMeta || n.Metadata() != ""
+		if n.HasLeft() {
+			widths(n.Left())
+		}
+		if n.HasRight() {
+			widths(n.Right())
+		}
+	}
+	widths(t)
+	slot := width + 1
+
+	// Place the nodes by level, using the in order position for the column.
+	var levels [][]*adaptiveNode[T]
+	var index int
+	var place func(n BinaryTree[T], depth int) int
+	place = func(n BinaryTree[T], depth int) int {
+		if len(levels) <= depth {
+			levels = append(levels, nil)
+		}
+		an := &adaptiveNode[T]{node: n, left: -1, right: -1}
+		levels[depth] = append(levels[depth], an)
+
+		if n.HasLeft() {
+			an.left = place(n.Left(), depth+1)
+		}
+		an.col = index * slot
+		index++
+		if n.HasRight() {
+			an.right = place(n.Right(), depth+1)
+		}
+		return an.col + width/2
+	}
+	place(t, 0)
+
+	for _, level := range levels {
+		line := []byte(strings.Repeat(" ", index*slot))
+		legs := []byte(strings.Repeat(" ", index*slot))
+		meta := []byte(strings.Repeat(" ", index*slot))
+		for _, an := range level {
+			// The underbars run right up to either end of the value.
+			label := centerString(fmt.Sprintf(nodeFmtT, an.node.Value()), " ", width)
+			start := an.col + len(label) - len(strings.TrimLeft(label, " "))
+			end := an.col + len(strings.TrimRight(label, " "))
+
+			copy(line[an.col:], label)
+			if an.left >= 0 {
+				fillBytes(line[an.left+1:start], '_')
+				legs[an.left] = leftLegBase[0]
+			}
+			if an.right >= 0 {
+				fillBytes(line[end:an.right], '_')
+				legs[an.right] = rightLegBase[0]
+			}
+			copy(meta[an.col:], centerString(an.node.Metadata(), " ", width))
+		}
+
+		buf.WriteString(strings.TrimRight(string(line), " ") + "\n")
+		if hasMeta {
+			buf.WriteString(strings.TrimRight(string(meta), " ") + "\n")
+		}
+		if l := strings.TrimRight(string(legs), " "); l != "" {
+			buf.WriteString(l + "\n")
+		}
+	}
+
+	return buf.String()
+}
+
+// Sizes used in the SVG rendering, in pixels.
+const (
+	// svgMinRadius is the smallest radius of the node circles. Nodes grow
+	// beyond this to fit wide values.
+	svgMinRadius = 18
+
+	// svgCharWidth is the approximate width of one character of the value
+	// text, used to size the node circles.
+	svgCharWidth = 9
+
+	// svgGap is the space between neighboring node circles.
+	svgGap = 8
+
+	// svgRowHeight is the vertical distance between levels of the tree.
+	svgRowHeight = 60
+
+	// svgMetaHeight is the space under a node used for its metadata label.
+	svgMetaHeight = 12
+
+	// svgMargin is the space around the edge of the image.
+	svgMargin = 10
+)
+
+// svgNode is a node placed in the SVG rendering.
+type svgNode struct {
+	x, y        int
+	value, meta string
+}
+
+// dumpBinaryTreeSVG renders the tree as a standalone SVG image, with the
+// nodes drawn as circles holding their values and lines to their children.
+// Like dumpBinaryTreeAdaptive, each node is placed in its own column by its
+// in order position and in a row by its depth so nodes never overlap. Any
+// metadata is written in small text under its node.
+func dumpBinaryTreeSVG[T constraints.Ordered](t BinaryTree[T]) string {
+	var nodes []svgNode
+	var edges [][4]int
+	var index, depth int
+	widest := 0
+	hasMeta := false
+
+	if !isTreeNil(t) {
+		// First gather the nodes in their grid positions.
+		var place func(n BinaryTree[T], d int) int
+		place = func(n BinaryTree[T], d int) int {
+			depth = max(depth, d+1)
+
+			left := -1
+			if n.HasLeft() {
+				left = place(n.Left(), d+1)
+			}
+			sn := svgNode{
+				x:     index,
+				y:     d,
+				value: fmt.Sprintf("%v", n.Value()),
+				meta:  n.Metadata(),
+			}
+			index++
+			widest = max(widest, len(sn.value))
+			hasMeta = hasMeta || sn.meta != ""
+			nodes = append(nodes, sn)
+			me := len(nodes) - 1
+
+			if left >= 0 {
+				edges = append(edges, [4]int{sn.x, sn.y, nodes[left].x, nodes[left].y})
+			}
+			if n.HasRight() {
+				right := place(n.Right(), d+1)
+				edges = append(edges, [4]int{sn.x, sn.y, nodes[right].x, nodes[right].y})
+			}
+			return me
+		}
+		place(t, 0)
+	}
+
+	// Then convert the grid positions into pixels, with the columns wide
+	// enough for the largest node.
+	radius := max(svgMinRadius, widest*svgCharWidth/2+4)
+	colWidth := 2*radius + svgGap
+	rowHeight := svgRowHeight
+	if hasMeta {
+		rowHeight += svgMetaHeight
+	}
+	px := func(col int) int { return svgMargin + col*colWidth + colWidth/2 }
+	py := func(row int) int { return svgMargin + radius + row*rowHeight }
+
+	width := 2*svgMargin + index*colWidth
+	height := 0
+	if depth > 0 {
+		height = 2*svgMargin + 2*radius + (depth-1)*rowHeight
+		if hasMeta {
+			height += svgMetaHeight
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+
+	// Edges go first so the nodes are drawn over their ends.
+	for _, e := range edges {
+		fmt.Fprintf(&buf, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n",
+			px(e[0]), py(e[1]), px(e[2]), py(e[3]))
+	}
+	for _, n := range nodes {
+		x, y := px(n.x), py(n.y)
+		fmt.Fprintf(&buf, `<circle cx="%d" cy="%d" r="%d" fill="white" stroke="black"/>`+"\n", x, y, radius)
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" text-anchor="middle" dominant-baseline="central" font-family="monospace" font-size="14">%s</text>`+"\n",
+			x, y, html.EscapeString(n.value))
+		if n.meta != "" {
+			fmt.Fprintf(&buf, `<text x="%d" y="%d" text-anchor="middle" font-family="monospace" font-size="10">%s</text>`+"\n",
+				x, y+radius+svgMetaHeight, html.EscapeString(n.meta))
+		}
+	}
+	buf.WriteString("</svg>\n")
+
+	return buf.String()
+}
+
+// dumpBinaryTreeDOT renders the tree as a Graphviz DOT digraph with an edge
+// from each node to its children. Edges leave from the lower left or lower
+// right of the parent, and a missing child is drawn as an invisible node so
+// that an only child still sits on the correct side. Red-Black nodes are
+// colored to match, and other metadata is shown as an external label.
+func dumpBinaryTreeDOT[T constraints.Ordered](t BinaryTree[T]) string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph tree {\n")
+	buf.WriteString("\tnode [shape=circle];\n")
+
+	var next int
+	var emit func(n BinaryTree[T]) string
+	emit = func(n BinaryTree[T]) string {
+		id := fmt.Sprintf("n%d", next)
+		next++
+
+		attrs := fmt.Sprintf("label=%q", fmt.Sprintf("%v", n.Value()))
+		meta := n.Metadata()
+		node := n
+		if sn, ok := n.(*sizedNode[T]); ok {
+			node = sn.BinaryTree
+		}
+		if rb, ok := node.(*redBlackNode[T]); ok {
+			if rb.isRed {
+				attrs += ", color=red, fontcolor=red"
+			} else {
+				attrs += ", color=black"
+			}
+			// The color is already shown, leaving only any size.
+			meta = strings.TrimSpace(strings.TrimPrefix(meta, rb.Metadata()))
+		}
+		if meta != "" {
+			attrs += fmt.Sprintf(", xlabel=%q", meta)
+		}
+		fmt.Fprintf(&buf, "\t%s [%s];\n", id, attrs)
+
+		if !n.HasLeft() && !n.HasRight() {
+			return id
+		}
+		for _, side := range []struct {
+			port    string
+			present bool
+			child   func() BinaryTree[T]
+		}{
+			{"sw", n.HasLeft(), n.Left},
+			{"se", n.HasRight(), n.Right},
+		} {
+			if side.present {
+				fmt.Fprintf(&buf, "\t%s:%s -> %s;\n", id, side.port, emit(side.child()))
+				continue
+			}
+			nilID := fmt.Sprintf("n%d", next)
+			next++
+			fmt.Fprintf(&buf, "\t%s [style=invis];\n", nilID)
+			fmt.Fprintf(&buf, "\t%s:%s -> %s [style=invis];\n", id, side.port, nilID)
+		}
+		return id
+	}
+	if !isTreeNil(t) {
+		emit(t)
+	}
+
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// dumpBinaryTreeIndent renders the tree with one node per line, the root at
+// the start of the first line and each child indented two spaces further
+// than its parent and prefixed with L: or R: for the side it is on. Any
+// metadata follows the value in parentheses.
+//
+//	2
+//	  L: 1
+//	  R: 3
+func dumpBinaryTreeIndent[T any](t BinaryTree[T]) string {
+	var buf bytes.Buffer
+	writeIndent(&buf, t, -1)
+	return buf.String()
+}
+
+// writeIndent writes the tree to buf in the form of dumpBinaryTreeIndent,
+// stopping after limit nodes unless limit is negative. It returns the number
+// of nodes written.
+func writeIndent[T any](buf *bytes.Buffer, t BinaryTree[T], limit int) int {
+	if isTreeNil(t) {
+		return 0
+	}
+
+	var count int
+	var emit func(n BinaryTree[T], depth int, side string)
+	emit = func(n BinaryTree[T], depth int, side string) {
+		if limit >= 0 && count >= limit {
+			return
+		}
+		count++
+
+		buf.WriteString(strings.Repeat("  ", depth))
+		buf.WriteString(side)
+		fmt.Fprintf(buf, "%v", n.Value())
+		if meta := n.Metadata(); meta != "" {
+			fmt.Fprintf(buf, " (%s)", meta)
+		}
+		buf.WriteString("\n")
+
+		if n.HasLeft() {
+			emit(n.Left(), depth+1, "L: ")
+		}
+		if n.HasRight() {
+			emit(n.Right(), depth+1, "R: ")
+		}
+	}
+	emit(t, 0, "")
+
+	return count
+}
+
+// maxStringNodes is the most nodes written out by the String methods of the
+// trees, keeping them cheap to print for large trees.
+const maxStringNodes = 64
+
+// treeString returns the tree with the given root and size in the indented
+// form of ModeIndent for the String methods of the trees. Only the first
+// maxStringNodes nodes are included, followed by a count of those left out.
+func treeString[T any](root BinaryTree[T], size int) string {
+	if isTreeNil(root) {
+		return "(empty)"
+	}
+
+	var buf bytes.Buffer
+	n := writeIndent(&buf, root, maxStringNodes)
+	if n < size {
+		fmt.Fprintf(&buf, "... %d more values\n", size-n)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// fillBytes sets every byte of b to c.
+func fillBytes(b []byte, c byte) {
+	for i := range b {
+		b[i] = c
+	}
+}
+
+// fill returns n characters of the given padding, which is made up of a
+// single repeated character, extending it if needed.
+func fill(pad string, n int) string {
+	if n <= len(pad) {
+		return pad[:n]
+	}
+	return strings.Repeat(pad[:1], n)
+}
+
+// optsForStats returns the spacing for each level of a tree of depthFrom+1
+// levels whose widest value is the given width.
 func optsForStats(depthFrom, widest int) indentOptionsMap {
-	if widest <= 1 {
-		return binaryTreeSpacingData[1]
+	return spacingForWidth(max(widest, 1), depthFrom)
+}
+
+// spacingForWidth computes the spacing for each level of a tree with the
+// given node width, from the bottom level up to one level above depthFrom.
+//
+// Each node, along with its shoulders and legs, spans a block of
+// width + legDepth + shoulderPadding characters on either side of the
+// centerline between its children. Children must end right where the legs
+// above them do, which fixes the size of each block from the level below:
+//
+//	block(0) = width
+//	block(1) = width + legDepth(1)
+//	block(2) = block(1) + (width + interTreePadding(0))/2
+//	block(d) = 2 * block(d-1)
+//
+// The bottom level is the only one where gaps between cousins differ from
+// gaps between siblings, everything above is evenly spaced, so from there
+// the blocks simply double. The prefix of each level is the sum of the
+// blocks below it.
+func spacingForWidth(width, depthFrom int) indentOptionsMap {
+	// The gap between cousins at the bottom is kept so that half of it
+	// plus the width is a whole number of characters.
+	bottomInter := 2 + width%2
+
+	blocks := make([]int, depthFrom+3)
+	blocks[0] = width
+	blocks[1] = width + legDepthForWidth(width, 1)
+	for d := 2; d < len(blocks); d++ {
+		if d == 2 {
+			blocks[d] = blocks[1] + (width+bottomInter)/2
+		} else {
+			blocks[d] = 2 * blocks[d-1]
+		}
 	}
-	if widest <= 3 {
-		return binaryTreeSpacingData[3]
+
+	opts := indentOptionsMap{}
+	prefix := 0
+	for d := 0; d <= depthFrom+1; d++ {
+		legDepth := min(legDepthForWidth(width, d), blocks[d]-width)
+		inter := bottomInter
+		if d > 0 {
+			inter = 2*blocks[d+1] - 2*blocks[d] + width
+		}
+		opts[d] = indentOptions{
+			indentWidth:      width,
+			prefixPadding:    prefix,
+			intraNodePadding: width,
+			interTreePadding: inter,
+			shoulderPadding:  blocks[d] - width - legDepth,
+			legDepth:         legDepth,
+		}
+		prefix += blocks[d]
 	}
-	return binaryTreeSpacingData[5]
+	return opts
+}
+
+// legDepthForWidth returns how many rows of diagonal legs to draw above the
+// given level, using the entry in indentSizeLegDepths for the nearest width
+// that is not larger.
+func legDepthForWidth(width, depthFrom int) int {
+	key := min(width-(1-width%2), 7)
+	depths := indentSizeLegDepths[key]
+	return depths[min(depthFrom, len(depths)-1)]
 }
 
 // generateLevelsNodes ranges over the given set of nodes generating a new
@@ -258,7 +777,7 @@ func outputLegs[T constraints.Ordered](nodes []BinaryTree[T], indentOptions inde
 	nodeSize := opts.indentWidth
 	lastNode := lastNonNilNode(nodes)
 	for i, ll := range leftLegs[:opts.legDepth] {
-		buf.WriteString(prefixPad[:opts.prefixPadding])
+		buf.WriteString(fill(prefixPad, opts.prefixPadding))
 		for j := 0; j < len(nodes); j++ {
 			if j > lastNode {
 				break
@@ -268,11 +787,11 @@ func outputLegs[T constraints.Ordered](nodes []BinaryTree[T], indentOptions inde
 
 			// offset is based on number of legs to be drawn at this level.
 			// left leg needs to be limited to this legDepth.
-			leftLeg := otherPad[:legDepthPad] + ll
-			writeLeg(nodes[j], leftLeg, indentFull[:opts.legDepth], buf)
+			leftLeg := fill(otherPad, legDepthPad) + ll
+			writeLeg(nodes[j], leftLeg, fill(indentFull, opts.legDepth), buf)
 
 			// If this level has lateral legs, put in blanks to cover.
-			buf.WriteString(shoulderPad[:opts.shoulderPadding])
+			buf.WriteString(fill(shoulderPad, opts.shoulderPadding))
 
 			// Right legs are the next value, so jump forward to them.
 			j++
@@ -287,19 +806,19 @@ func outputLegs[T constraints.Ordered](nodes []BinaryTree[T], indentOptions inde
 			// The spacing between the two legs in the tree.
 			// Higher up nodes in the tree have more spacing to handle
 			// the fanout as the tree grows.
-			buf.WriteString(intraPad[:nodeSize])
+			buf.WriteString(fill(intraPad, nodeSize))
 
 			// If this level has lateral leg elements, put in blanks to cover.
-			buf.WriteString(shoulderPad[:opts.shoulderPadding])
+			buf.WriteString(fill(shoulderPad, opts.shoulderPadding))
 
 			// right leg needs to be limited to legDepth
-			rl := rightLegs[i] + otherPad2[:legDepthPad]
-			writeLeg(nodes[j], rl, indentFull[:opts.legDepth], buf)
+			rl := rightLegs[i] + fill(otherPad2, legDepthPad)
+			writeLeg(nodes[j], rl, fill(indentFull, opts.legDepth), buf)
 
 			// For all but the final node in the list.
 			if j != len(nodes)-1 {
 				// Spacing between subtrees.
-				buf.WriteString(interPad[:opts.interTreePadding])
+				buf.WriteString(fill(interPad, opts.interTreePadding))
 			}
 		}
 		buf.WriteString("\n")
@@ -308,45 +827,52 @@ func outputLegs[T constraints.Ordered](nodes []BinaryTree[T], indentOptions inde
 
 // outputNodes writes out all the nodes and metadata at this level.
 func outputNodes[T constraints.Ordered](nodes []BinaryTree[T], indentOptions indentOptionsMap, buf *bytes.Buffer, depthFrom int) {
+	// Nodes.
+	writeNodeRow(nodes, indentOptions, buf, depthFrom, underbarFull, func(n BinaryTree[T]) string {
+		return fmt.Sprintf(nodeFmtT, n.Value())
+	})
+
+	if !levelHasMetadata(nodes) {
+		return
+	}
+
+	// The metadata goes in the same columns as the values above it, with
+	// blanks in place of the lines going sideways to the legs.
+	writeNodeRow(nodes, indentOptions, buf, depthFrom, shoulderPad, func(n BinaryTree[T]) string {
+		return n.Metadata()
+	})
+}
+
+// writeNodeRow writes one row of the nodes at this level, with the text
+// returned by cell for each node centered in the width of a node, and the
+// sideways spacing on either side of it filled from shoulder.
+func writeNodeRow[T constraints.Ordered](nodes []BinaryTree[T], indentOptions indentOptionsMap, buf *bytes.Buffer, depthFrom int, shoulder string, cell func(BinaryTree[T]) string) {
 	opts := indentOptions[depthFrom]
 	nodeSize := opts.indentWidth
 	parentOpts := indentOptions[depthFrom+1]
 	lastNode := lastNonNilNode(nodes)
 
-	// Nodes.
-	buf.WriteString(prefixPad[:opts.prefixPadding])
+	buf.WriteString(fill(prefixPad, opts.prefixPadding))
 	for j, n := range nodes {
 		// For all rows except the bottom row,  each node potentially has
 		// both left and right legs below it that need to be padded for.
 		if depthFrom != 0 || (depthFrom == 0 && j != 0 && j%2 == 1) {
-			buf.WriteString(legPad[:opts.legDepth])
-			//} else {
-			//buf.WriteString("*")
+			buf.WriteString(fill(legPad, opts.legDepth))
 		}
 
 		// Higher up levels have lines that go sideways to keep the tree
 		// reasonably sized.
-		if n != nil && n.HasLeft() {
-			buf.WriteString(underbarFull[:opts.shoulderPadding])
-		} else {
-			//buf.WriteString(shoulderPad[:opts.shoulderPadding])
-			buf.WriteString(underbarFull[:opts.shoulderPadding])
-		}
+		buf.WriteString(fill(shoulder, opts.shoulderPadding))
 
 		// The actual node value.
 		if n != nil {
-			buf.WriteString(centerString(fmt.Sprintf(nodeFmtT, n.Value()), " ",
-				nodeSize))
+			buf.WriteString(centerString(cell(n), " ", nodeSize))
 		} else {
-			buf.WriteString(indentFull[:nodeSize])
+			buf.WriteString(fill(indentFull, nodeSize))
 		}
 
-		if n != nil && n.HasRight() {
-			buf.WriteString(underbarFull[:opts.shoulderPadding])
-		} else {
-			//buf.WriteString(shoulderPad[:opts.shoulderPadding])
-			buf.WriteString(underbarFull[:opts.shoulderPadding])
-		}
+		buf.WriteString(fill(shoulder, opts.shoulderPadding))
+
 		// If this is the last node, skip all the remaining trailing padding.
 		if j >= lastNode {
 			break
@@ -356,11 +882,7 @@ func outputNodes[T constraints.Ordered](nodes []BinaryTree[T], indentOptions ind
 		// If this is an even index, then we want the padding to match
 		// the number of leg segments leading down into this node
 		// on the inside of the node values.
-		// if j%2 == 0 {
-		buf.WriteString(legPad[:opts.legDepth])
-		// } else {
-		// buf.WriteString("*")
-		// }
+		buf.WriteString(fill(legPad, opts.legDepth))
 
 		// Between the even and odd indexes the spacing breakdown
 		// matches what the outputLegs does (combination of shoulder
@@ -371,49 +893,14 @@ func outputNodes[T constraints.Ordered](nodes []BinaryTree[T], indentOptions ind
 		inter := (parentOpts.legDepth + parentOpts.shoulderPadding) -
 			(opts.legDepth + opts.shoulderPadding)
 		if j%2 == 0 {
-			//buf.WriteString(shoulderPad[:opts.shoulderPadding])
-			buf.WriteString(shoulderPad[:inter])
-			buf.WriteString(intraPad[:nodeSize])
-			buf.WriteString(shoulderPad[:inter])
+			buf.WriteString(fill(shoulderPad, inter))
+			buf.WriteString(fill(intraPad, nodeSize))
+			buf.WriteString(fill(shoulderPad, inter))
 
 		} else {
 			// Finish off with the spacing between the trees.
-			buf.WriteString(interPad[:opts.interTreePadding])
-		}
-	}
-	buf.WriteString("\n")
-
-	if !levelHasMetadata(nodes) {
-		return
-	}
-
-	// Add metadata print
-	buf.WriteString(prefixPad[:opts.prefixPadding])
-	for j, n := range nodes {
-		// This indent lines up with the left leg lines.
-		if depthFrom != 0 {
-			buf.WriteString(indentFull[:nodeSize])
-			// buf.WriteString(indent)
-		}
-		buf.WriteString(shoulderPad[:opts.shoulderPadding])
-		if n != nil {
-			buf.WriteString(fmt.Sprintf(nodeMetaFmt, n.Metadata()))
-		} else {
-			buf.WriteString(indentFull[:nodeSize])
-			// buf.WriteString(indent)
-		}
-		// If this is the last node, skip all the remaining trailing padding.
-		if j >= lastNode {
-			break
+			buf.WriteString(fill(interPad, opts.interTreePadding))
 		}
-
-		buf.WriteString(shoulderPad[:opts.shoulderPadding])
-		// This indent lines up with the right leg lines.
-		if depthFrom != 0 {
-			buf.WriteString(indentFull[:nodeSize])
-			// buf.WriteString(indent)
-		}
-		buf.WriteString(interPad[:opts.interTreePadding])
 	}
 	buf.WriteString("\n")
 }
@@ -435,7 +922,10 @@ func levelHasMetadata[T constraints.Ordered](nodes []BinaryTree[T]) bool {
 // centerString centers the given string into the target size adjusting the space at
 // either end as needed with the given pad character.
 //
-// This method assumes an output width of 50 or less for the purpose of this file.
+// A leading sign is left out when finding the center, and hangs into the
+// padding to the left, so that the digits of negative and positive numbers
+// of the same length line up with each other. When the padding can't be split
+// evenly, the extra goes on the left so values sit slightly right of center.
 func centerString(s, padChar string, width int) string {
 	s = strings.TrimSpace(s)
 	l := len(s)
@@ -445,14 +935,14 @@ func centerString(s, padChar string, width int) string {
 		return s
 	}
 
-	// We attempt to right justify uneven splits so values will sit
-	// slightly more right than left when they can't be balanced.
-	lPad := ((width - l) / 2) + 1
+	body := s
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		body = s[1:]
+	}
+	lPad := max((width-len(body)+1)/2-(l-len(body)), 0)
 	rPad := width - l - lPad
 
-	// TODO(rsned): replace spaces with the padChar
-	const spaces = "                                                                     "
-	return fmt.Sprintf("%s%s%s", spaces[0:lPad], s, spaces[0:rPad])
+	return strings.Repeat(padChar, lPad) + s + strings.Repeat(padChar, rPad)
 }
 
 type dumpTreeStats struct {
@@ -465,11 +955,17 @@ type dumpTreeStats struct {
 // analyzeTree takes the givern tree and attempts to find out relevant details
 // about it to assist in the rendering.
 func analyzeTree[T constraints.Ordered](tree BinaryTree[T]) dumpTreeStats {
-
 	stats := dumpTreeStats{
-		height:      tree.Height(),
-		leftHeight:  tree.Left().Height(),
-		rightHeight: tree.Right().Height(),
+		height: tree.Height(),
+	}
+
+	// A missing child may come back as a nil interface rather than a nil
+	// node, so it can't be asked for its height.
+	if tree.HasLeft() {
+		stats.leftHeight = tree.Left().Height()
+	}
+	if tree.HasRight() {
+		stats.rightHeight = tree.Right().Height()
 	}
 
 	// things we want to find out:
@@ -477,23 +973,20 @@ func analyzeTree[T constraints.Ordered](tree BinaryTree[T]) dumpTreeStats {
 	// width of largest value
 	// lopsidedness / skew   e.g. is this only a one sided binary tree?
 
-	// Walk the tree getting all values and printing them as strings.
-	ch := tree.Traverse(TraverseInOrder)
-
+	// Walk the tree finding the widest value or metadata when printed, as
+	// both are centered in the width of a node.
 	var widest int
-	var got []string
-	for {
-		val, ok := <-ch
-		if ok {
-			s := fmt.Sprintf("%v", val)
-			if len(s) > widest {
-				widest = len(s)
-			}
-			got = append(got, fmt.Sprintf("%v", s))
-		} else {
-			break
+	var walk func(n BinaryTree[T])
+	walk = func(n BinaryTree[T]) {
+		widest = max(widest, len(fmt.Sprintf("%v", n.Value())), len(n.Metadata()))
+		if n.HasLeft() {
+			walk(n.Left())
+		}
+		if n.HasRight() {
+			walk(n.Right())
 		}
 	}
+	walk(tree)
 
 	stats.widestValue = widest
 
@@ -0,0 +1,515 @@
+package tree
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/constraints"
+)
+
+var updateGolden = flag.Bool("update_golden", false,
+	"rewrite the golden files in testdata with the current output")
+
+// checkGolden compares got with the contents of the named file in testdata,
+// rewriting the file instead when -update_golden is set.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// checkLegsAlign checks that each child in the ASCII rendering of the tree
+// sits right at the end of the leg above it, with a left child ending just
+// before its / and a right child starting just after its \. Every value must
+// occur exactly once in the output, so trees with a value that is part of
+// another, like 3 and 33, can not be checked.
+func checkLegsAlign[T constraints.Ordered](t *testing.T, got string, tree BinaryTree[T]) {
+	t.Helper()
+	lines := strings.Split(got, "\n")
+
+	// find returns the row and column of the only occurrence of s.
+	find := func(s string) (int, int) {
+		if n := strings.Count(got, s); n != 1 {
+			t.Fatalf("value %q occurs %d times in the output, want 1:\n%s", s, n, got)
+		}
+		for row, line := range lines {
+			if col := strings.Index(line, s); col >= 0 {
+				return row, col
+			}
+		}
+		return 0, 0
+	}
+
+	var check func(n BinaryTree[T])
+	check = func(n BinaryTree[T]) {
+		if n.HasLeft() {
+			child := fmt.Sprintf("%v", n.Left().Value())
+			row, col := find(child)
+			above := lines[row-1]
+			if end := col + len(child); end >= len(above) || above[end] != '/' {
+				t.Errorf("left child %q ending at column %d is not below a /:\n%s", child, end, got)
+			}
+			check(n.Left())
+		}
+		if n.HasRight() {
+			child := fmt.Sprintf("%v", n.Right().Value())
+			row, col := find(child)
+			above := lines[row-1]
+			if col == 0 || col > len(above) || above[col-1] != '\\' {
+				t.Errorf("right child %q starting at column %d is not below a \\:\n%s", child, col, got)
+			}
+			check(n.Right())
+		}
+	}
+	check(tree)
+}
+
+// widestLine returns the length of the longest line in s.
+func widestLine(s string) int {
+	var widest int
+	for _, line := range strings.Split(s, "\n") {
+		widest = max(widest, len(line))
+	}
+	return widest
+}
+
+func TestRenderBinaryTreeAdaptiveSpacing(t *testing.T) {
+	// A left-heavy tree with a lone right child at the root.
+	//
+	//           42
+	//          /  \
+	//        21    84
+	//       /  \
+	//      1    30
+	//     / \   /
+	//  -13  11 29
+	tree := newBSTWith(42, 21, 84, 1, 30, -13, 11, 29)
+
+	want := `              ___________42__
+             /               \
+      _______21______        84
+     /               \
+  ___1___         ___30
+ /       \       /
+-13      11      29
+`
+
+	got := RenderBinaryTree(tree.Root(), 0, ModeASCII, WithAdaptiveSpacing(true))
+	if got != want {
+		t.Errorf("RenderBinaryTree(WithAdaptiveSpacing(true)) =\n%s\nwant:\n%s", got, want)
+	}
+
+	// The sparse right side should no longer reserve the full width.
+	full := RenderBinaryTree(tree.Root(), 0, ModeASCII)
+	if g, f := widestLine(got), widestLine(full); g >= f {
+		t.Errorf("adaptive spacing is %d wide, want narrower than the default %d", g, f)
+	}
+
+	if got := RenderBinaryTree((&BST[int]{}).Root(), 0, ModeASCII, WithAdaptiveSpacing(true)); got != "" {
+		t.Errorf("RenderBinaryTree(empty tree) = %q, want \"\"", got)
+	}
+}
+
+func TestRenderBinaryTreeAdaptiveSpacingMetadata(t *testing.T) {
+	tree := &AVL[int]{}
+	for _, v := range []int{2, 1, 3} {
+		tree.Insert(v)
+	}
+
+	want := `   _____2_____
+      BF: 0
+  /           \
+  1           3
+BF: 0       BF: 0
+`
+
+	if got := RenderBinaryTree(tree.Root(), 0, ModeASCII, WithAdaptiveSpacing(true)); got != want {
+		t.Errorf("RenderBinaryTree(WithAdaptiveSpacing(true)) =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderBinaryTreeMixedWidths(t *testing.T) {
+	// Negative, single digit and three digit values together.
+	//
+	//         7
+	//       /   \
+	//    -21     123
+	//    / \     / \
+	//  -30  2   8   456
+	tree := newBSTWith(7, -21, 123, -30, 2, 8, 456)
+
+	t.Run("adaptive", func(t *testing.T) {
+		checkGolden(t, "ascii_mixed_widths.txt",
+			RenderBinaryTree(tree.Root(), 0, ModeASCII, WithAdaptiveSpacing(true)))
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		avl := &AVL[int]{}
+		avl.InsertAll(7, -21, 123, -5, 456)
+		checkGolden(t, "ascii_mixed_widths_avl.txt",
+			RenderBinaryTree(avl.Root(), 0, ModeASCII, WithAdaptiveSpacing(true)))
+	})
+}
+
+func TestCenterString(t *testing.T) {
+	tests := []struct {
+		s       string
+		padChar string
+		width   int
+		want    string
+	}{
+		{s: "7", padChar: " ", width: 5, want: "  7  "},
+		{s: "123", padChar: " ", width: 5, want: " 123 "},
+		// The sign hangs to the left of the centered digits.
+		{s: "-21", padChar: " ", width: 5, want: " -21 "},
+		{s: "-7", padChar: " ", width: 5, want: " -7  "},
+		{s: "+7", padChar: " ", width: 5, want: " +7  "},
+		{s: "-7", padChar: " ", width: 3, want: "-7 "},
+		// Uneven splits lean right.
+		{s: "12", padChar: " ", width: 5, want: "  12 "},
+		{s: "-123", padChar: " ", width: 5, want: "-123 "},
+		// Surrounding space is not part of the value.
+		{s: "  7", padChar: " ", width: 3, want: " 7 "},
+		{s: "7", padChar: "_", width: 5, want: "__7__"},
+		{s: "-", padChar: ".", width: 3, want: ".-."},
+		// Values too wide for the cell are left as they are.
+		{s: "12345", padChar: " ", width: 3, want: "12345"},
+	}
+
+	for _, test := range tests {
+		if got := centerString(test.s, test.padChar, test.width); got != test.want {
+			t.Errorf("centerString(%q, %q, %d) = %q, want %q", test.s, test.padChar, test.width, got, test.want)
+		}
+	}
+}
+
+func TestRenderBinaryTreeSVG(t *testing.T) {
+	//     2
+	//    / \
+	//   1   3
+	//        \
+	//         4
+	tree := &AVL[int]{}
+	for _, v := range []int{2, 1, 3, 4} {
+		tree.Insert(v)
+	}
+
+	got := RenderBinaryTree(tree.Root(), 0, ModeSVG)
+	checkGolden(t, "render_avl.svg", got)
+
+	if !strings.HasPrefix(got, "<svg ") || !strings.HasSuffix(got, "</svg>\n") {
+		t.Errorf("RenderBinaryTree(ModeSVG) is not a standalone <svg> element:\n%s", got)
+	}
+	for _, want := range []string{">1</text>", ">4</text>", ">BF: 1</text>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderBinaryTree(ModeSVG) does not contain %q", want)
+		}
+	}
+	if n := strings.Count(got, "<circle "); n != 4 {
+		t.Errorf("RenderBinaryTree(ModeSVG) has %d nodes, want 4", n)
+	}
+	if n := strings.Count(got, "<line "); n != 3 {
+		t.Errorf("RenderBinaryTree(ModeSVG) has %d edges, want 3", n)
+	}
+}
+
+func TestRenderBinaryTreeDOT(t *testing.T) {
+	//     42
+	//    /  \
+	//   21   84
+	//     \
+	//      30
+	tree := newBSTWith(42, 21, 84, 30)
+	got := RenderBinaryTree(tree.Root(), 0, ModeDOT)
+
+	for _, want := range []string{
+		"digraph tree {\n",
+		"\tn0 [label=\"42\"];\n",
+		"\tn1 [label=\"21\"];\n",
+		"\tn0:sw -> n1;\n",
+		// 21 has no left child, so an invisible node holds its place.
+		"\tn2 [style=invis];\n",
+		"\tn1:sw -> n2 [style=invis];\n",
+		"\tn3 [label=\"30\"];\n",
+		"\tn1:se -> n3;\n",
+		"\tn4 [label=\"84\"];\n",
+		"\tn0:se -> n4;\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderBinaryTree(ModeDOT) does not contain %q, got:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "->"); n != 4 {
+		t.Errorf("RenderBinaryTree(ModeDOT) has %d edges, want 4", n)
+	}
+
+	rb := &redBlackNode[int]{
+		value: 2,
+		left:  &redBlackNode[int]{value: 1, isRed: true},
+		right: &redBlackNode[int]{value: 3, isRed: true},
+	}
+	got = RenderBinaryTree[int](rb, 0, ModeDOT)
+	for _, want := range []string{
+		"\tn0 [label=\"2\", color=black];\n",
+		"\tn1 [label=\"1\", color=red, fontcolor=red];\n",
+		"\tn2 [label=\"3\", color=red, fontcolor=red];\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderBinaryTree(ModeDOT) does not contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderBinaryTreeWideValues(t *testing.T) {
+	tree := &BST[string]{}
+	for _, v := range []string{"cherry", "banana", "lychee", "almond", "cashew", "damson", "quince"} {
+		tree.Insert(v)
+	}
+
+	want := `PPPPPPPPPPPPPLLLL_cherry_
+PPPPPPPPPPPPP###/SiiiiiiS\$$$
+PPPPPPPPPPPPP##/ SiiiiiiS \$$
+PPPPPPPPPPPPP#/  SiiiiiiS  \$
+PPPPPPPPPPPPP/   SiiiiiiS   \
+PPPPPPLbananaLSSSSiiiiiiSSSSLlychee
+PPPPPP/iiiiii\IIIIIIIIIIIIII/iiiiii\
+almondSiiiiiiScashewIIdamsonSiiiiiiSquince
+`
+	got := RenderBinaryTree(tree.Root(), 0, ModeASCII)
+	if got != want {
+		t.Errorf("RenderBinaryTree() =\n%s\nwant:\n%s", got, want)
+	}
+
+	checkLegsAlign(t, got, tree.Root())
+}
+
+func TestRenderBinaryTreeWidths(t *testing.T) {
+	// fullTree returns a full three level tree with every value width
+	// digits long.
+	fullTree := func(width int) *BST[int] {
+		base := 1
+		for i := 1; i < width; i++ {
+			base *= 10
+		}
+		tree := &BST[int]{}
+		for _, v := range []int{4, 2, 6, 1, 3, 5, 7} {
+			tree.Insert(v * base)
+		}
+		return tree
+	}
+
+	for _, width := range []int{4, 6, 8} {
+		tree := fullTree(width)
+		got := RenderBinaryTree(tree.Root(), 0, ModeASCII)
+		checkLegsAlign(t, got, tree.Root())
+
+		// Every level is spaced for the actual width, not the next
+		// larger one in the leg depth table.
+		for d, opts := range optsForStats(tree.Height()-1, width) {
+			if opts.indentWidth != width {
+				t.Errorf("optsForStats(%d, %d)[%d].indentWidth = %d, want %d",
+					tree.Height()-1, width, d, opts.indentWidth, width)
+			}
+		}
+
+		// So narrower values take less room than wider ones.
+		wider := RenderBinaryTree(fullTree(width+1).Root(), 0, ModeASCII)
+		if g, w := widestLine(got), widestLine(wider); g >= w {
+			t.Errorf("width %d values render %d wide, want narrower than the %d of width %d values",
+				width, g, w, width+1)
+		}
+	}
+}
+
+func TestRenderBinaryTreeTall(t *testing.T) {
+	// Seven levels, with the deepest path zigzagging down the middle.
+	tree := newBSTWith(50, 25, 75, 12, 37, 62, 87, 30, 40, 35, 33, 34)
+	if h := tree.Height(); h != 7 {
+		t.Fatalf("test tree height = %d, want 7", h)
+	}
+
+	got := RenderBinaryTree(tree.Root(), 0, ModeASCII)
+	checkLegsAlign(t, got, tree.Root())
+}
+
+func TestRenderBinaryTreeOneSided(t *testing.T) {
+	tests := []struct {
+		name string
+		tree BinaryTree[int]
+
+		// Expected heights of the children of the root.
+		wantLeft, wantRight int
+	}{
+		{name: "single BST node", tree: &bstNode[int]{value: 42}},
+		{name: "single AVL node", tree: &avlNode[int]{value: 42}},
+		{name: "single Red-Black node", tree: &redBlackNode[int]{value: 42}},
+		{name: "single Treap node", tree: &treapNode[int]{value: 42}},
+		{name: "left skewed", tree: newBSTWith(50, 40, 30, 20, 10).Root(), wantLeft: 4},
+		{name: "right skewed", tree: newBSTWith(10, 20, 30, 40, 50).Root(), wantRight: 4},
+	}
+
+	for _, test := range tests {
+		stats := analyzeTree(test.tree)
+		if stats.leftHeight != test.wantLeft || stats.rightHeight != test.wantRight {
+			t.Errorf("%s: analyzeTree() child heights = %d, %d, want %d, %d", test.name,
+				stats.leftHeight, stats.rightHeight, test.wantLeft, test.wantRight)
+		}
+
+		got := dumpBinaryTree("", test.tree)
+		if strings.TrimSpace(got) == "" {
+			t.Errorf("%s: dumpBinaryTree() is empty", test.name)
+			continue
+		}
+		checkLegsAlign(t, got, test.tree)
+
+		root := fmt.Sprint(test.tree.Value())
+		for _, mode := range []RenderMode{ModeSVG, ModeDOT, ModeIndent} {
+			if got := RenderBinaryTree(test.tree, 0, mode); !strings.Contains(got, root) {
+				t.Errorf("%s: RenderBinaryTree(mode %d) is missing the root value:\n%s", test.name, mode, got)
+			}
+		}
+		if got := RenderBinaryTree(test.tree, 0, ModeASCII, WithAdaptiveSpacing(true)); strings.TrimSpace(got) == "" {
+			t.Errorf("%s: RenderBinaryTree(WithAdaptiveSpacing) is empty", test.name)
+		}
+	}
+}
+
+func TestRenderBinaryTreeIndent(t *testing.T) {
+	tests := []struct {
+		name   string
+		tree   *BST[int]
+		golden string
+	}{
+		{
+			name:   "balanced",
+			tree:   newBSTWith(42, 21, 84, 1, 30, 57, 99),
+			golden: "indent_balanced.txt",
+		},
+		{
+			name:   "right leaning",
+			tree:   newBSTWith(1, 2, 3, 4, 5),
+			golden: "indent_right_leaning.txt",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkGolden(t, test.golden, RenderBinaryTree(test.tree.Root(), 0, ModeIndent))
+		})
+	}
+
+	// Metadata follows the value.
+	avl := &AVL[int]{}
+	for _, v := range []int{2, 1} {
+		avl.Insert(v)
+	}
+	want := "2 (BF:-1)\n  L: 1 (BF: 0)\n"
+	if got := RenderBinaryTree(avl.Root(), 0, ModeIndent); got != want {
+		t.Errorf("RenderBinaryTree(ModeIndent) = %q, want %q", got, want)
+	}
+
+	if got := RenderBinaryTree((&BST[int]{}).Root(), 0, ModeIndent); got != "" {
+		t.Errorf("RenderBinaryTree(empty tree) = %q, want \"\"", got)
+	}
+}
+
+func TestRenderBinaryTreeShowSizes(t *testing.T) {
+	tree := newBSTWith(42, 21, 84, 1, 30, 57, 99)
+
+	want := `42 (n:7)
+  L: 21 (n:3)
+    L: 1 (n:1)
+    R: 30 (n:1)
+  R: 84 (n:3)
+    L: 57 (n:1)
+    R: 99 (n:1)
+`
+	if got := RenderBinaryTree(tree.Root(), 0, ModeIndent, ShowSizes(true)); got != want {
+		t.Errorf("RenderBinaryTree(ModeIndent, ShowSizes(true)) = %q, want %q", got, want)
+	}
+
+	// Every mode shows the sizes somewhere.
+	for _, opts := range [][]treeOptionFunc{
+		{ShowSizes(true)},
+		{ShowSizes(true), WithAdaptiveSpacing(true)},
+	} {
+		for _, mode := range []RenderMode{ModeASCII, ModeSVG, ModeDOT} {
+			got := RenderBinaryTree(tree.Root(), 0, mode, opts...)
+			for _, size := range []string{"n:7", "n:3", "n:1"} {
+				if !strings.Contains(got, size) {
+					t.Errorf("RenderBinaryTree(mode %d, ShowSizes(true)) is missing %q:\n%s", mode, size, got)
+				}
+			}
+		}
+	}
+
+	if got := RenderBinaryTree(tree.Root(), 0, ModeIndent); strings.Contains(got, "n:") {
+		t.Errorf("RenderBinaryTree(ModeIndent) without ShowSizes = %q, want no sizes", got)
+	}
+
+	// Sizes follow any metadata the node has of its own.
+	avl := &AVL[int]{}
+	for _, v := range []int{2, 1} {
+		avl.Insert(v)
+	}
+	want = "2 (BF:-1 n:2)\n  L: 1 (BF: 0 n:1)\n"
+	if got := RenderBinaryTree(avl.Root(), 0, ModeIndent, ShowSizes(true)); got != want {
+		t.Errorf("RenderBinaryTree(AVL, ModeIndent, ShowSizes(true)) = %q, want %q", got, want)
+	}
+
+	// In ASCII, each node's metadata sits in the same columns as its value
+	// in the row above, however much wider than the value it is.
+	avl = &AVL[int]{}
+	for v := 1; v <= 9; v++ {
+		avl.Insert(v)
+	}
+	got := RenderBinaryTree(avl.Root(), 0, ModeASCII, ShowSizes(true))
+	lines := strings.Split(got, "\n")
+	metaCell := regexp.MustCompile(`BF:[ -]\d n:\d+`)
+	valueCell := regexp.MustCompile(`^ *\d+ *$`)
+	var cells int
+	for row := 1; row < len(lines); row++ {
+		for _, span := range metaCell.FindAllStringIndex(lines[row], -1) {
+			cells++
+			above := lines[row-1]
+			if span[1] > len(above) || !valueCell.MatchString(above[span[0]:span[1]]) {
+				t.Errorf("metadata %q in columns %d-%d is not under a value:\n%s",
+					lines[row][span[0]:span[1]], span[0], span[1], got)
+			}
+		}
+	}
+	if cells != 9 {
+		t.Errorf("RenderBinaryTree(AVL, ModeASCII, ShowSizes(true)) has %d metadata cells, want 9:\n%s", cells, got)
+	}
+
+	// Red-Black nodes keep their colors in DOT, with the size as a label.
+	rb := &redBlackNode[int]{value: 2, left: &redBlackNode[int]{value: 1, isRed: true}}
+	got = RenderBinaryTree[int](rb, 0, ModeDOT, ShowSizes(true))
+	for _, want := range []string{`label="2", color=black, xlabel="n:2"`, `label="1", color=red, fontcolor=red, xlabel="n:1"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderBinaryTree(Red-Black, ModeDOT, ShowSizes(true)) is missing %q:\n%s", want, got)
+		}
+	}
+
+	if got := RenderBinaryTree((&BST[int]{}).Root(), 0, ModeIndent, ShowSizes(true)); got != "" {
+		t.Errorf("RenderBinaryTree(empty tree, ShowSizes(true)) = %q, want \"\"", got)
+	}
+}
@@ -0,0 +1,258 @@
+package tree
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// Interval is the closed range of values from Lo to Hi, including both ends.
+type Interval[T any] struct {
+	Lo, Hi T
+}
+
+// IntervalTree holds a set of intervals and finds those overlapping any given
+// range in O(log n + k) time, for k intervals found.
+//
+// It is a Red-Black tree of the intervals ordered by their low ends, and then
+// their high ends, with each node augmented with the largest high end in its
+// subtree. A search can then skip any subtree whose largest high end falls
+// before the range being searched for, as nothing in it can reach the range.
+type IntervalTree[T constraints.Ordered] struct {
+	root *intervalNode[T]
+	size int
+}
+
+// intervalNode is the node in an IntervalTree.
+type intervalNode[T constraints.Ordered] struct {
+	interval Interval[T]
+
+	// max is the largest high end of any interval in the subtree rooted
+	// here, kept up to date by insert and the rotations.
+	max T
+
+	isRed bool
+
+	parent, left, right *intervalNode[T]
+}
+
+// NewIntervalTree returns an empty IntervalTree ready to use.
+func NewIntervalTree[T constraints.Ordered]() *IntervalTree[T] {
+	return &IntervalTree[T]{}
+}
+
+// Size returns the number of intervals held in the tree.
+func (t *IntervalTree[T]) Size() int {
+	return t.size
+}
+
+// IsEmpty reports if the tree holds no intervals.
+func (t *IntervalTree[T]) IsEmpty() bool {
+	return t.root == nil
+}
+
+// Height returns the number of nodes on the longest path in the tree from the
+// root node to the farthest leaf.
+func (t *IntervalTree[T]) Height() int {
+	return t.root.height()
+}
+
+// height is the worker for Height.
+func (n *intervalNode[T]) height() int {
+	if n == nil {
+		return 0
+	}
+	return 1 + max(n.left.height(), n.right.height())
+}
+
+// Insert adds the interval [lo, hi] to the tree and reports if it was added.
+// If lo is greater than hi, or the same interval is already in the tree, the
+// tree is unchanged and false is returned.
+func (t *IntervalTree[T]) Insert(lo, hi T) bool {
+	if hi < lo {
+		return false
+	}
+
+	iv := Interval[T]{Lo: lo, Hi: hi}
+	var parent *intervalNode[T]
+	var c int
+	for n := t.root; n != nil; {
+		c = compareIntervals(iv, n.interval)
+		if c == 0 {
+			return false
+		}
+		parent = n
+		if c < 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+
+	node := &intervalNode[T]{interval: iv, max: hi, isRed: true, parent: parent}
+	switch {
+	case parent == nil:
+		t.root = node
+	case c < 0:
+		parent.left = node
+	default:
+		parent.right = node
+	}
+
+	// Every node above the new one now has hi in its subtree.
+	for n := parent; n != nil && n.max < hi; n = n.parent {
+		n.max = hi
+	}
+
+	t.fixInsert(node)
+	t.size++
+	return true
+}
+
+// compareIntervals orders intervals by their low ends, and then by their high
+// ends.
+func compareIntervals[T constraints.Ordered](a, b Interval[T]) int {
+	switch {
+	case a.Lo < b.Lo:
+		return -1
+	case b.Lo < a.Lo:
+		return 1
+	case a.Hi < b.Hi:
+		return -1
+	case b.Hi < a.Hi:
+		return 1
+	}
+	return 0
+}
+
+// fixInsert restores the Red-Black invariants after the red node n has been
+// added, recoloring while n has a red parent and a red uncle, and otherwise
+// rotating n or its parent up into the place of its grandparent.
+func (t *IntervalTree[T]) fixInsert(n *intervalNode[T]) {
+	for n.parent != nil && n.parent.isRed {
+		// A red parent is never the root, so there is a grandparent.
+		p, g := n.parent, n.parent.parent
+		if p == g.left {
+			if u := g.right; u != nil && u.isRed {
+				p.isRed, u.isRed, g.isRed = false, false, true
+				n = g
+				continue
+			}
+			if n == p.right {
+				t.rotateLeft(p)
+				n, p = p, n
+			}
+			t.rotateRight(g)
+		} else {
+			if u := g.left; u != nil && u.isRed {
+				p.isRed, u.isRed, g.isRed = false, false, true
+				n = g
+				continue
+			}
+			if n == p.left {
+				t.rotateRight(p)
+				n, p = p, n
+			}
+			t.rotateLeft(g)
+		}
+		p.isRed, g.isRed = false, true
+	}
+	t.root.isRed = false
+}
+
+// rotateLeft rotates the right child of n up into its place.
+//
+//	 [n]             [r]
+//	 / \             / \
+//	a  [r]    =>   [n]  c
+//	   / \         / \
+//	  b   c       a   b
+//
+// The subtree as a whole holds the same intervals, so r takes over its max,
+// while n's is worked out again from what is left below it.
+func (t *IntervalTree[T]) rotateLeft(n *intervalNode[T]) {
+	r := n.right
+	n.right = r.left
+	if r.left != nil {
+		r.left.parent = n
+	}
+	t.replaceChild(n, r)
+	r.left = n
+	n.parent = r
+
+	r.max = n.max
+	n.updateMax()
+}
+
+// rotateRight rotates the left child of n up into its place, the mirror
+// image of rotateLeft.
+func (t *IntervalTree[T]) rotateRight(n *intervalNode[T]) {
+	l := n.left
+	n.left = l.right
+	if l.right != nil {
+		l.right.parent = n
+	}
+	t.replaceChild(n, l)
+	l.right = n
+	n.parent = l
+
+	l.max = n.max
+	n.updateMax()
+}
+
+// replaceChild links new into the place of old under old's parent, or as the
+// root of the tree if old has no parent.
+func (t *IntervalTree[T]) replaceChild(old, new *intervalNode[T]) {
+	new.parent = old.parent
+	switch {
+	case old.parent == nil:
+		t.root = new
+	case old.parent.left == old:
+		old.parent.left = new
+	default:
+		old.parent.right = new
+	}
+}
+
+// updateMax sets the max of n from its own interval and its children.
+func (n *intervalNode[T]) updateMax() {
+	n.max = n.interval.Hi
+	if n.left != nil && n.max < n.left.max {
+		n.max = n.left.max
+	}
+	if n.right != nil && n.max < n.right.max {
+		n.max = n.right.max
+	}
+}
+
+// Overlapping returns the intervals in the tree that share at least one value
+// with [lo, hi], ordered by their low ends. Intervals that only touch the
+// range at one end are included. If lo is greater than hi, nothing overlaps.
+func (t *IntervalTree[T]) Overlapping(lo, hi T) []Interval[T] {
+	if hi < lo {
+		return nil
+	}
+
+	var out []Interval[T]
+	t.root.overlapping(lo, hi, &out)
+	return out
+}
+
+// overlapping is the worker for Overlapping, appending the intervals below n
+// that overlap [lo, hi] onto out.
+func (n *intervalNode[T]) overlapping(lo, hi T, out *[]Interval[T]) {
+	// Nothing in this subtree reaches as far as lo.
+	if n == nil || n.max < lo {
+		return
+	}
+
+	n.left.overlapping(lo, hi, out)
+
+	// Everything to the right starts no earlier than this interval, so once
+	// this one starts after hi, so does all of that.
+	if hi < n.interval.Lo {
+		return
+	}
+	if !(n.interval.Hi < lo) {
+		*out = append(*out, n.interval)
+	}
+	n.right.overlapping(lo, hi, out)
+}
@@ -0,0 +1,208 @@
+package tree
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// checkIntervalTree returns the number of black nodes on every path from n
+// down to a missing child, and reports if that is the same for all of them,
+// no red node has a red child, the parent pointers and ordering of the
+// intervals are consistent, and every max matches the intervals below it.
+func checkIntervalTree(n *intervalNode[int]) (int, bool) {
+	if n == nil {
+		return 1, true
+	}
+	if n.isRed && ((n.left != nil && n.left.isRed) || (n.right != nil && n.right.isRed)) {
+		return 0, false
+	}
+	if (n.left != nil && (n.left.parent != n || compareIntervals(n.left.interval, n.interval) >= 0)) ||
+		(n.right != nil && (n.right.parent != n || compareIntervals(n.right.interval, n.interval) <= 0)) {
+		return 0, false
+	}
+	want := n.interval.Hi
+	if n.left != nil {
+		want = max(want, n.left.max)
+	}
+	if n.right != nil {
+		want = max(want, n.right.max)
+	}
+	if n.max != want {
+		return 0, false
+	}
+
+	lh, lok := checkIntervalTree(n.left)
+	rh, rok := checkIntervalTree(n.right)
+	if !lok || !rok || lh != rh {
+		return 0, false
+	}
+	if !n.isRed {
+		lh++
+	}
+	return lh, true
+}
+
+func TestIntervalTreeOverlapping(t *testing.T) {
+	tree := NewIntervalTree[int]()
+	for _, iv := range []Interval[int]{
+		{Lo: 15, Hi: 20},
+		{Lo: 10, Hi: 30},
+		{Lo: 17, Hi: 19},
+		{Lo: 5, Hi: 20},
+		{Lo: 12, Hi: 15},
+		{Lo: 30, Hi: 40},
+		{Lo: 50, Hi: 60},
+		{Lo: 55, Hi: 55},
+	} {
+		if !tree.Insert(iv.Lo, iv.Hi) {
+			t.Fatalf("Insert(%d, %d) = false, want true", iv.Lo, iv.Hi)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		lo, hi int
+		want   []Interval[int]
+	}{
+		{
+			name: "overlaps several",
+			lo:   14,
+			hi:   16,
+			want: []Interval[int]{{5, 20}, {10, 30}, {12, 15}, {15, 20}},
+		},
+		{
+			name: "inside one",
+			lo:   21,
+			hi:   29,
+			want: []Interval[int]{{10, 30}},
+		},
+		{
+			name: "touching ends",
+			lo:   40,
+			hi:   50,
+			want: []Interval[int]{{30, 40}, {50, 60}},
+		},
+		{
+			name: "single point",
+			lo:   55,
+			hi:   55,
+			want: []Interval[int]{{50, 60}, {55, 55}},
+		},
+		{
+			name: "covers everything",
+			lo:   0,
+			hi:   100,
+			want: []Interval[int]{{5, 20}, {10, 30}, {12, 15}, {15, 20}, {17, 19}, {30, 40}, {50, 60}, {55, 55}},
+		},
+		{
+			name: "in a gap",
+			lo:   41,
+			hi:   49,
+		},
+		{
+			name: "before everything",
+			lo:   -10,
+			hi:   4,
+		},
+		{
+			name: "after everything",
+			lo:   61,
+			hi:   70,
+		},
+		{
+			name: "reversed bounds",
+			lo:   16,
+			hi:   14,
+		},
+	}
+
+	for _, test := range tests {
+		got := tree.Overlapping(test.lo, test.hi)
+		if diff := cmp.Diff(test.want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("%s: Overlapping(%d, %d) differs (-want +got):\n%s", test.name, test.lo, test.hi, diff)
+		}
+	}
+}
+
+func TestIntervalTreeInsert(t *testing.T) {
+	tree := NewIntervalTree[int]()
+	if !tree.IsEmpty() || tree.Size() != 0 || tree.Height() != 0 {
+		t.Errorf("NewIntervalTree() is not empty")
+	}
+	if got := tree.Overlapping(0, 10); len(got) != 0 {
+		t.Errorf("Overlapping(0, 10) on an empty tree = %v, want none", got)
+	}
+
+	if !tree.Insert(3, 7) {
+		t.Errorf("Insert(3, 7) = false, want true")
+	}
+	if tree.Insert(3, 7) {
+		t.Errorf("Insert(3, 7) again = true, want false")
+	}
+	if tree.Insert(7, 3) {
+		t.Errorf("Insert(7, 3) = true, want false")
+	}
+	// Intervals sharing a low end are kept apart.
+	if !tree.Insert(3, 9) {
+		t.Errorf("Insert(3, 9) = false, want true")
+	}
+	if got := tree.Size(); got != 2 {
+		t.Errorf("Size() = %d, want 2", got)
+	}
+
+	// Sorted inserts, the worst case for an unbalanced tree, still leave it
+	// balanced with the max of every subtree tracked through the rotations.
+	const n = 1000
+	tree = NewIntervalTree[int]()
+	for i := 0; i < n; i++ {
+		tree.Insert(i, i+n-i%100)
+		if _, ok := checkIntervalTree(tree.root); !ok || tree.root.isRed {
+			t.Fatalf("after inserting %d intervals, the tree does not hold its invariants", i+1)
+		}
+	}
+	if got, limit := tree.Height(), int(2*math.Log2(n+1)); got > limit {
+		t.Errorf("Height() after %d sorted inserts = %d, want at most %d", n, got, limit)
+	}
+}
+
+func TestIntervalTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	tree := NewIntervalTree[int]()
+	var all []Interval[int]
+	for i := 0; i < 500; i++ {
+		lo := r.Intn(1000)
+		hi := lo + r.Intn(50)
+		if tree.Insert(lo, hi) {
+			all = append(all, Interval[int]{Lo: lo, Hi: hi})
+		}
+	}
+	if _, ok := checkIntervalTree(tree.root); !ok {
+		t.Fatalf("random tree does not hold its invariants")
+	}
+	if got := tree.Size(); got != len(all) {
+		t.Errorf("Size() = %d, want %d", got, len(all))
+	}
+
+	slices.SortFunc(all, compareIntervals[int])
+	for i := 0; i < 200; i++ {
+		lo := r.Intn(1100) - 50
+		hi := lo + r.Intn(30)
+
+		var want []Interval[int]
+		for _, iv := range all {
+			if iv.Lo <= hi && lo <= iv.Hi {
+				want = append(want, iv)
+			}
+		}
+		got := tree.Overlapping(lo, hi)
+		if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("Overlapping(%d, %d) differs (-want +got):\n%s", lo, hi, diff)
+		}
+	}
+}
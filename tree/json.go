@@ -0,0 +1,182 @@
+package tree
+
+import "encoding/json"
+
+// jsonTree is the form trees are marshaled to and from JSON in. The whole
+// structure of the tree is kept, not just its values, so that a reloaded tree
+// has the identical shape.
+//
+// Comparison functions can not be marshaled, so a tree created with one
+// must be unmarshaled into a tree created with the same function.
+type jsonTree[T any] struct {
+	Descending bool         `json:"descending,omitempty"`
+	Duplicates bool         `json:"duplicates,omitempty"`
+	Root       *jsonNode[T] `json:"root"`
+}
+
+// jsonNode is the JSON form of a single node along with its children.
+type jsonNode[T any] struct {
+	Value T `json:"value"`
+
+	// BF is the balance factor of AVL nodes.
+	BF int `json:"bf,omitempty"`
+
+	// Red is the color of Red-Black nodes.
+	Red bool `json:"red,omitempty"`
+
+	Left  *jsonNode[T] `json:"left,omitempty"`
+	Right *jsonNode[T] `json:"right,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t *BST[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonTree[T]{
+		Descending: t.order.descending,
+		Duplicates: t.order.duplicates,
+		Root:       t.root.toJSON(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the contents of the
+// tree with the marshaled tree.
+func (t *BST[T]) UnmarshalJSON(data []byte) error {
+	var jt jsonTree[T]
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+
+	t.root = bstNodeFromJSON(jt.Root)
+	t.size = t.root.Size()
+	t.order.descending = jt.Descending
+	t.order.duplicates = jt.Duplicates
+	return nil
+}
+
+// toJSON returns the JSON form of the subtree rooted at this node.
+func (t *bstNode[T]) toJSON() *jsonNode[T] {
+	if t == nil {
+		return nil
+	}
+	return &jsonNode[T]{
+		Value: t.value,
+		Left:  t.left.toJSON(),
+		Right: t.right.toJSON(),
+	}
+}
+
+// bstNodeFromJSON rebuilds the subtree in the given JSON form.
+func bstNodeFromJSON[T any](n *jsonNode[T]) *bstNode[T] {
+	if n == nil {
+		return nil
+	}
+	return &bstNode[T]{
+		value: n.Value,
+		left:  bstNodeFromJSON(n.Left),
+		right: bstNodeFromJSON(n.Right),
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t *AVL[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonTree[T]{
+		Descending: t.order.descending,
+		Duplicates: t.order.duplicates,
+		Root:       t.root.toJSON(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the contents of the
+// tree with the marshaled tree.
+func (t *AVL[T]) UnmarshalJSON(data []byte) error {
+	var jt jsonTree[T]
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+
+	t.root = avlNodeFromJSON(jt.Root, nil)
+	t.size = t.root.subtreeSize()
+	t.order.descending = jt.Descending
+	t.order.duplicates = jt.Duplicates
+	return nil
+}
+
+// toJSON returns the JSON form of the subtree rooted at this node.
+func (t *avlNode[T]) toJSON() *jsonNode[T] {
+	if t == nil {
+		return nil
+	}
+	return &jsonNode[T]{
+		Value: t.value,
+		BF:    t.bf,
+		Left:  t.left.toJSON(),
+		Right: t.right.toJSON(),
+	}
+}
+
+// avlNodeFromJSON rebuilds the subtree in the given JSON form below the
+// given parent, filling in the subtree sizes and heights as it goes.
+func avlNodeFromJSON[T any](n *jsonNode[T], parent *avlNode[T]) *avlNode[T] {
+	if n == nil {
+		return nil
+	}
+	node := &avlNode[T]{
+		value:  n.Value,
+		bf:     n.BF,
+		parent: parent,
+	}
+	node.left = avlNodeFromJSON(n.Left, node)
+	node.right = avlNodeFromJSON(n.Right, node)
+	node.size = 1 + node.left.subtreeSize() + node.right.subtreeSize()
+	node.updateHeight()
+	return node
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t *RedBlack[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonTree[T]{
+		Descending: t.order.descending,
+		Duplicates: t.order.duplicates,
+		Root:       t.root.toJSON(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the contents of the
+// tree with the marshaled tree.
+func (t *RedBlack[T]) UnmarshalJSON(data []byte) error {
+	var jt jsonTree[T]
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+
+	t.root = redBlackNodeFromJSON(jt.Root)
+	t.size = t.root.Size()
+	t.order.descending = jt.Descending
+	t.order.duplicates = jt.Duplicates
+	return nil
+}
+
+// toJSON returns the JSON form of the subtree rooted at this node.
+func (t *redBlackNode[T]) toJSON() *jsonNode[T] {
+	if t == nil {
+		return nil
+	}
+	return &jsonNode[T]{
+		Value: t.value,
+		Red:   t.isRed,
+		Left:  t.left.toJSON(),
+		Right: t.right.toJSON(),
+	}
+}
+
+// redBlackNodeFromJSON rebuilds the subtree in the given JSON form.
+func redBlackNodeFromJSON[T any](n *jsonNode[T]) *redBlackNode[T] {
+	if n == nil {
+		return nil
+	}
+	return &redBlackNode[T]{
+		value: n.Value,
+		isRed: n.Red,
+		left:  redBlackNodeFromJSON(n.Left),
+		right: redBlackNodeFromJSON(n.Right),
+	}
+}
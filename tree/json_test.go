@@ -0,0 +1,127 @@
+package tree
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	// jsonTester is the subset of methods being tested here.
+	type jsonTester interface {
+		Tree[int]
+		Root() BinaryTree[int]
+		json.Marshaler
+		json.Unmarshaler
+	}
+
+	vals := []int{42, 21, 84, 1, 30, 99, -13, 11}
+
+	avl := &AVL[int]{}
+	bst := &BST[int]{}
+	for _, v := range vals {
+		avl.Insert(v)
+		bst.Insert(v)
+	}
+
+	// Red-Black trees don't color their nodes on insert yet, so build one by
+	// hand.
+	rb := &RedBlack[int]{
+		root: &redBlackNode[int]{
+			value: 21,
+			left: &redBlackNode[int]{
+				value: 1,
+				isRed: true,
+			},
+			right: &redBlackNode[int]{
+				value: 42,
+				isRed: true,
+				left:  &redBlackNode[int]{value: 30},
+				right: &redBlackNode[int]{value: 84},
+			},
+		},
+		size: 5,
+	}
+
+	tests := []struct {
+		name     string
+		tree     jsonTester
+		reloaded jsonTester
+	}{
+		{
+			name:     "BST",
+			tree:     bst,
+			reloaded: &BST[int]{},
+		},
+		{
+			name:     "AVL",
+			tree:     avl,
+			reloaded: &AVL[int]{},
+		},
+		{
+			name:     "RedBlack",
+			tree:     rb,
+			reloaded: &RedBlack[int]{},
+		},
+		{
+			name:     "empty",
+			tree:     &BST[int]{},
+			reloaded: &BST[int]{},
+		},
+	}
+
+	for _, test := range tests {
+		data, err := json.Marshal(test.tree)
+		if err != nil {
+			t.Fatalf("%s: json.Marshal() error: %v", test.name, err)
+		}
+		if err := json.Unmarshal(data, test.reloaded); err != nil {
+			t.Fatalf("%s: json.Unmarshal(%s) error: %v", test.name, data, err)
+		}
+
+		if !Equal[int](test.tree, test.reloaded) {
+			t.Errorf("%s: reloaded tree is not Equal to the original\njson: %s", test.name, data)
+		}
+		if got, want := test.reloaded.Size(), test.tree.Size(); got != want {
+			t.Errorf("%s: reloaded Size() = %d, want %d", test.name, got, want)
+		}
+
+		// The indented rendering includes the metadata of each node.
+		got := RenderBinaryTree(test.reloaded.Root(), 0, ModeIndent)
+		want := RenderBinaryTree(test.tree.Root(), 0, ModeIndent)
+		if got != want {
+			t.Errorf("%s: reloaded tree =\n%s\nwant:\n%s", test.name, got, want)
+		}
+	}
+
+	// The reloaded AVL tree must be fully usable, parent pointers and all.
+	reloaded := tests[1].reloaded.(*AVL[int])
+	if got, ok := reloaded.Successor(11); got != 21 || !ok {
+		t.Errorf("reloaded Successor(11) = %v, %v, want 21, true", got, ok)
+	}
+	if got, ok := reloaded.Select(3); got != 21 || !ok {
+		t.Errorf("reloaded Select(3) = %v, %v, want 21, true", got, ok)
+	}
+}
+
+func TestJSONDescending(t *testing.T) {
+	tree := NewBST[int](Descending())
+	for _, v := range []int{5, 3, 8} {
+		tree.Insert(v)
+	}
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+
+	var reloaded BST[int]
+	if err := json.Unmarshal(data, &reloaded); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", data, err)
+	}
+	if !reloaded.Insert(4) || !reloaded.Search(4) {
+		t.Errorf("Insert(4) into reloaded descending tree failed")
+	}
+	if got, _ := reloaded.Min(); got != 8 {
+		t.Errorf("reloaded Min() = %d, want 8", got)
+	}
+}
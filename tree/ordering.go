@@ -0,0 +1,121 @@
+package tree
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ordering defines how the values in a tree are arranged.
+type ordering[T any] struct {
+	// cmp compares two values, returning a negative number when a is less
+	// than b, a positive number when a is greater than b, and zero when
+	// they are equal. If nil, the natural order of T is used.
+	cmp func(a, b T) int
+
+	// descending indicates the values are ordered largest to smallest.
+	descending bool
+
+	// duplicates indicates equal values are all kept in the tree, as in a
+	// multiset, instead of being rejected. Each new copy is placed after
+	// the equal values already in the tree.
+	duplicates bool
+}
+
+// newOrdering returns the resolved ordering set by the given options.
+func newOrdering[T any](opts *Options) ordering[T] {
+	return ordering[T]{
+		descending: opts.descending,
+		duplicates: !opts.ignoreDuplicates,
+	}.resolve()
+}
+
+// resolve returns the ordering with the natural order of T filled in if no
+// comparison function was given.
+//
+// Finding the natural order can take reflection, so the tree constructors
+// store their ordering already resolved, leaving this a nil check in the
+// methods of the trees. Only trees made from their zero values look up the
+// natural order on each operation.
+func (o ordering[T]) resolve() ordering[T] {
+	if o.cmp == nil {
+		o.cmp = naturalOrder[T]()
+	}
+	return o
+}
+
+// compare compares a and b by their position in the tree, returning a
+// negative number when a comes before b, a positive number when a comes
+// after b, and zero when they are equal. The ordering must be resolved.
+func (o ordering[T]) compare(a, b T) int {
+	c := o.cmp(a, b)
+	if o.descending {
+		return -c
+	}
+	return c
+}
+
+// naturalOrder returns a comparison function for the built in ordering of
+// the values of T, using cmp.Compare for the ordered types and their named
+// variants. It panics if T has no natural order, in which case trees must be
+// created with a comparison function, such as with NewBSTFunc.
+func naturalOrder[T any]() func(a, b T) int {
+	var zero T
+	var f any
+	switch any(zero).(type) {
+	case int:
+		f = cmp.Compare[int]
+	case int8:
+		f = cmp.Compare[int8]
+	case int16:
+		f = cmp.Compare[int16]
+	case int32:
+		f = cmp.Compare[int32]
+	case int64:
+		f = cmp.Compare[int64]
+	case uint:
+		f = cmp.Compare[uint]
+	case uint8:
+		f = cmp.Compare[uint8]
+	case uint16:
+		f = cmp.Compare[uint16]
+	case uint32:
+		f = cmp.Compare[uint32]
+	case uint64:
+		f = cmp.Compare[uint64]
+	case uintptr:
+		f = cmp.Compare[uintptr]
+	case float32:
+		f = cmp.Compare[float32]
+	case float64:
+		f = cmp.Compare[float64]
+	case string:
+		f = strings.Compare
+	}
+	if f != nil {
+		return f.(func(a, b T) int)
+	}
+
+	// Named types, e.g. type Celsius float64, fall back to reflection.
+	switch typ := reflect.TypeFor[T](); typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}
+	case reflect.String:
+		return func(a, b T) int {
+			return strings.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}
+	default:
+		panic(fmt.Sprintf("tree: %v has no natural order, a comparison function is required", typ))
+	}
+}
@@ -0,0 +1,130 @@
+package tree
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// PersistentBST is an immutable binary search tree. Insert and Delete leave
+// the tree they are called on untouched and return a new version of it, so
+// every version stays valid and can be kept as a snapshot, or returned to in
+// order to undo later changes.
+//
+// Versions share structure through path copying. Only the nodes on the path
+// from the root to the change are copied, O(height) of them, and every other
+// subtree is shared as is between the old and new versions. Like BST, no
+// balancing is done.
+type PersistentBST[T any] struct {
+	root *persistentNode[T]
+
+	// size is the count of values in this version of the tree.
+	size int
+
+	// order defines how the values in the tree are arranged.
+	order ordering[T]
+}
+
+// NewPersistentBST returns an empty PersistentBST ready to use.
+//
+// Options can include Descending to order the values largest first, and
+// IgnoreDuplicates(false) to keep every copy of equal values inserted.
+func NewPersistentBST[T constraints.Ordered](opts ...treeOptionFunc) *PersistentBST[T] {
+	treeOpts := defaultOptions()
+	for _, opt := range opts {
+		opt(treeOpts)
+	}
+
+	return &PersistentBST[T]{
+		order: newOrdering[T](treeOpts),
+	}
+}
+
+// Root returns the root node of this version of the tree. The nodes are shared
+// with other versions, so they can not be changed through it.
+func (t *PersistentBST[T]) Root() BinaryTree[T] {
+	return t.root
+}
+
+// Insert returns a new version of the tree with v added, copying the path
+// from the root down to where v is placed and sharing the rest. If the value
+// is already in the tree, and the tree does not keep duplicates, t itself is
+// returned.
+func (t *PersistentBST[T]) Insert(v T) *PersistentBST[T] {
+	root, ok := t.root.insert(v, t.order.resolve())
+	if !ok {
+		return t
+	}
+	return &PersistentBST[T]{root: root, size: t.size + 1, order: t.order}
+}
+
+// InsertAll returns a new version of the tree with each of the values added.
+// Only the final version is kept, the ones between are left for the garbage
+// collector.
+func (t *PersistentBST[T]) InsertAll(vals ...T) *PersistentBST[T] {
+	for _, v := range vals {
+		t = t.Insert(v)
+	}
+	return t
+}
+
+// Delete returns a new version of the tree with v removed, copying the path
+// from the root down to v and sharing the rest. A node with two children is
+// replaced by a copy holding the next value in order, which is removed from
+// below it instead. If the value is not in the tree, t itself is returned.
+func (t *PersistentBST[T]) Delete(v T) *PersistentBST[T] {
+	root, ok := t.root.delete(v, t.order.resolve())
+	if !ok {
+		return t
+	}
+	return &PersistentBST[T]{root: root, size: t.size - 1, order: t.order}
+}
+
+// Search reports if the given value is in the tree.
+func (t *PersistentBST[T]) Search(v T) bool {
+	return t.root.search(v, t.order.resolve())
+}
+
+// Traverse traverses the tree in the specified order emitting the values to
+// the channel. Channel is closed once the final value is emitted.
+func (t *PersistentBST[T]) Traverse(tOrder TraverseOrder) <-chan T {
+	return t.root.Traverse(tOrder)
+}
+
+// Walk calls visit with each value of the tree in the given order, stopping
+// as soon as visit returns false. It reports if every value was visited.
+func (t *PersistentBST[T]) Walk(tOrder TraverseOrder, visit func(T) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	return walkBinaryTree[T](t.root, tOrder, visit)
+}
+
+// TraverseSlice returns the values of the tree in the given order as a slice.
+func (t *PersistentBST[T]) TraverseSlice(tOrder TraverseOrder) []T {
+	if t.root == nil {
+		return nil
+	}
+	return appendValues[T](make([]T, 0, t.size), t.root, tOrder)
+}
+
+// Height returns the height of the longest path in the tree from the
+// root node to the farthest leaf.
+func (t *PersistentBST[T]) Height() int {
+	return t.root.Height()
+}
+
+// Size returns the number of values held in this version of the tree.
+func (t *PersistentBST[T]) Size() int {
+	return t.size
+}
+
+// IsEmpty reports if this version of the tree holds no values.
+func (t *PersistentBST[T]) IsEmpty() bool {
+	return t.root == nil
+}
+
+// String returns the values of the tree indented one level per depth, with
+// the root first and each child after its parent, for use when printing the
+// tree. Large trees are cut short after the first few dozen nodes.
+func (t *PersistentBST[T]) String() string {
+	return treeString[T](t.root, t.size)
+}
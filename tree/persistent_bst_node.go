@@ -0,0 +1,200 @@
+package tree
+
+// persistentNode is the node in a PersistentBST. Once a node is linked into a
+// tree it is never changed, as it may be shared by any number of versions of
+// the tree. Changes are made by building copies of the nodes instead.
+type persistentNode[T any] struct {
+	value T
+
+	// The two children nodes.
+	left, right *persistentNode[T]
+}
+
+// HasLeft reports if this node has a Left child.
+func (t *persistentNode[T]) HasLeft() bool {
+	return t.left != nil
+}
+
+// HasRight reports if this node has a Right child.
+func (t *persistentNode[T]) HasRight() bool {
+	return t.right != nil
+}
+
+// Left returns this nodes Left child.
+func (t *persistentNode[T]) Left() BinaryTree[T] {
+	return t.left
+}
+
+// Right returns this nodes Right child.
+func (t *persistentNode[T]) Right() BinaryTree[T] {
+	return t.right
+}
+
+// Parent always returns nil. A shared node has a different parent in each
+// version of the tree, so none is kept.
+func (t *persistentNode[T]) Parent() BinaryTree[T] {
+	return nil
+}
+
+// Value returns this nodes Value.
+func (t *persistentNode[T]) Value() T {
+	return t.value
+}
+
+// Metadata returns a string of metadata about this node.
+// Persistent trees have nothing interesting to show.
+func (t *persistentNode[T]) Metadata() string {
+	return ""
+}
+
+// Insert always returns false, leaving the tree unchanged, as the nodes of a
+// PersistentBST are never changed in place. Use the Insert of PersistentBST,
+// which returns a new version of the tree, instead.
+func (t *persistentNode[T]) Insert(v T) bool {
+	return false
+}
+
+// insert is the worker for PersistentBST.Insert, placing v according to the
+// given ordering of the tree. It returns the root of a new subtree holding v,
+// made of copies of the nodes on the path down to v and the children of those
+// nodes that were left as is, and reports if v was added. If it was not, this
+// node is returned.
+func (t *persistentNode[T]) insert(v T, o ordering[T]) (*persistentNode[T], bool) {
+	if t == nil {
+		return &persistentNode[T]{value: v}, true
+	}
+
+	// Duplicates are only allowed if the tree keeps them, in which case
+	// they go to the right.
+	c := o.compare(v, t.value)
+	if c == 0 && !o.duplicates {
+		return t, false
+	}
+
+	n := *t
+	var ok bool
+	if c < 0 {
+		n.left, ok = t.left.insert(v, o)
+	} else {
+		n.right, ok = t.right.insert(v, o)
+	}
+	if !ok {
+		return t, false
+	}
+	return &n, true
+}
+
+// InsertAll always returns 0, as Insert never adds a value.
+func (t *persistentNode[T]) InsertAll(vals ...T) int {
+	return 0
+}
+
+// Delete always returns false, leaving the tree unchanged, as the nodes of a
+// PersistentBST are never changed in place. Use the Delete of PersistentBST,
+// which returns a new version of the tree, instead.
+func (t *persistentNode[T]) Delete(v T) bool {
+	return false
+}
+
+// delete is the worker for PersistentBST.Delete, removing v according to the
+// given ordering of the tree. Like insert, it returns the root of a new
+// subtree sharing every node off the path down to v, and reports if v was
+// removed. If it was not, this node is returned.
+func (t *persistentNode[T]) delete(v T, o ordering[T]) (*persistentNode[T], bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	n := *t
+	var ok bool
+	switch c := o.compare(v, t.value); {
+	case c < 0:
+		n.left, ok = t.left.delete(v, o)
+	case c > 0:
+		n.right, ok = t.right.delete(v, o)
+	case t.left == nil:
+		return t.right, true
+	case t.right == nil:
+		return t.left, true
+	default:
+		var next T
+		n.right, next = t.right.removeMin()
+		n.value = next
+		ok = true
+	}
+	if !ok {
+		return t, false
+	}
+	return &n, true
+}
+
+// removeMin returns the root of a new subtree without the first value in
+// order of this one, and that value.
+func (t *persistentNode[T]) removeMin() (*persistentNode[T], T) {
+	if t.left == nil {
+		return t.right, t.value
+	}
+
+	n := *t
+	var first T
+	n.left, first = t.left.removeMin()
+	return &n, first
+}
+
+// Search reports if the given value is in the tree.
+func (t *persistentNode[T]) Search(v T) bool {
+	return t.search(v, ordering[T]{}.resolve())
+}
+
+// search is the worker for Search, following the given ordering of the tree.
+func (t *persistentNode[T]) search(v T, o ordering[T]) bool {
+	for n := t; n != nil; {
+		c := o.compare(v, n.value)
+		if c == 0 {
+			return true
+		}
+		if c < 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return false
+}
+
+// Traverse traverses the tree in the specified order emitting the values to
+// the channel. Channel is closed once the final value is emitted.
+func (t *persistentNode[T]) Traverse(tOrder TraverseOrder) <-chan T {
+	ch := make(chan T)
+	go func() {
+		if !t.IsEmpty() {
+			traverseBinaryTree(t, tOrder, ch)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Height returns the height of the longest path in the tree from the
+// root node to the farthest leaf.
+func (t *persistentNode[T]) Height() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + max(t.left.Height(), t.right.Height())
+}
+
+// Size returns the number of values in the tree rooted at this node.
+func (t *persistentNode[T]) Size() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + t.left.Size() + t.right.Size()
+}
+
+// IsEmpty reports if the tree rooted at this node holds no values, which is
+// only the case for a nil node.
+func (t *persistentNode[T]) IsEmpty() bool {
+	return t == nil
+}
@@ -0,0 +1,171 @@
+package tree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// persistentNodes adds every node below n to the set and returns it.
+func persistentNodes(n *persistentNode[int], set map[*persistentNode[int]]bool) map[*persistentNode[int]]bool {
+	if n == nil {
+		return set
+	}
+	set[n] = true
+	persistentNodes(n.left, set)
+	return persistentNodes(n.right, set)
+}
+
+func TestPersistentBSTVersions(t *testing.T) {
+	v0 := NewPersistentBST[int]()
+	v1 := v0.InsertAll(21, 1, 42, -13, 11, 30, 84, 57)
+	v2 := v1.Insert(5)
+	v3 := v2.Delete(42)
+
+	tests := []struct {
+		name string
+		tree *PersistentBST[int]
+		want []int
+	}{
+		{name: "empty", tree: v0},
+		{name: "initial values", tree: v1, want: []int{-13, 1, 11, 21, 30, 42, 57, 84}},
+		{name: "after insert", tree: v2, want: []int{-13, 1, 5, 11, 21, 30, 42, 57, 84}},
+		{name: "after delete", tree: v3, want: []int{-13, 1, 5, 11, 21, 30, 57, 84}},
+	}
+	for _, test := range tests {
+		if got := test.tree.TraverseSlice(TraverseInOrder); !slices.Equal(got, test.want) {
+			t.Errorf("%s: values = %v, want %v", test.name, got, test.want)
+		}
+		if got := test.tree.Size(); got != len(test.want) {
+			t.Errorf("%s: Size() = %d, want %d", test.name, got, len(test.want))
+		}
+	}
+
+	if v1.Search(5) || !v2.Search(5) {
+		t.Errorf("Search(5) = %v, %v on the versions before and after inserting 5, want false, true", v1.Search(5), v2.Search(5))
+	}
+	if !v2.Search(42) || v3.Search(42) {
+		t.Errorf("Search(42) = %v, %v on the versions before and after deleting 42, want true, false", v2.Search(42), v3.Search(42))
+	}
+	if !v0.IsEmpty() || v1.IsEmpty() {
+		t.Errorf("IsEmpty() = %v, %v on the empty and initial versions, want true, false", v0.IsEmpty(), v1.IsEmpty())
+	}
+
+	// Changes that leave the values as they are return the same version.
+	if got := v1.Insert(21); got != v1 {
+		t.Errorf("Insert of a value already in the tree returned a new version")
+	}
+	if got := v1.Delete(1000); got != v1 {
+		t.Errorf("Delete of a value not in the tree returned a new version")
+	}
+	if got := v0.Delete(1); got != v0 {
+		t.Errorf("Delete on an empty tree returned a new version")
+	}
+}
+
+func TestPersistentBSTSharing(t *testing.T) {
+	//          21
+	//        /    \
+	//       1      42
+	//      / \    /  \
+	//   -13  11  30   84
+	//                /
+	//              57
+	old := NewPersistentBST[int]().InsertAll(21, 1, 42, -13, 11, 30, 84, 57)
+
+	// Inserting on the left copies the path 21, 1, 11, and shares the whole
+	// right subtree and the other child of 1.
+	ins := old.Insert(5)
+	if ins.root == old.root || ins.root.left == old.root.left || ins.root.left.right == old.root.left.right {
+		t.Errorf("Insert(5) did not copy the nodes on the path to the new value")
+	}
+	if ins.root.right != old.root.right {
+		t.Errorf("Insert(5) did not share the right subtree of the root")
+	}
+	if ins.root.left.left != old.root.left.left {
+		t.Errorf("Insert(5) did not share the subtree at -13")
+	}
+	if old.root.left.right.left != nil {
+		t.Errorf("Insert(5) changed a node of the old version")
+	}
+
+	// Deleting 42, with two children, copies the path 21, 42, 84 with 57
+	// moving up, and shares the subtree at 30 and all of the left.
+	del := old.Delete(42)
+	if del.root.left != old.root.left {
+		t.Errorf("Delete(42) did not share the left subtree of the root")
+	}
+	if del.root.right.value != 57 || del.root.right.left != old.root.right.left {
+		t.Errorf("Delete(42) did not replace 42 with 57 while sharing the subtree at 30")
+	}
+	if old.root.right.value != 42 || old.root.right.right.left == nil {
+		t.Errorf("Delete(42) changed a node of the old version")
+	}
+
+	// Only the nodes on the path from the root are new.
+	oldNodes := persistentNodes(old.root, map[*persistentNode[int]]bool{})
+	shared := 0
+	for n := range persistentNodes(del.root, map[*persistentNode[int]]bool{}) {
+		if oldNodes[n] {
+			shared++
+		}
+	}
+	if want := del.Size() - 3; shared != want {
+		t.Errorf("Delete(42) shares %d nodes with the old version, want %d", shared, want)
+	}
+}
+
+func TestPersistentBSTRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	// Keep every version along with the values it should hold, and check
+	// them all once the changes are done.
+	versions := []*PersistentBST[int]{NewPersistentBST[int]()}
+	want := [][]int{nil}
+	for i := 0; i < 300; i++ {
+		last := versions[len(versions)-1]
+		vals := slices.Clone(want[len(want)-1])
+		v := r.Intn(100)
+
+		var next *PersistentBST[int]
+		idx, found := slices.BinarySearch(vals, v)
+		if r.Intn(3) == 0 {
+			next = last.Delete(v)
+			if found {
+				vals = slices.Delete(vals, idx, idx+1)
+			}
+		} else {
+			next = last.Insert(v)
+			if !found {
+				vals = slices.Insert(vals, idx, v)
+			}
+		}
+		versions = append(versions, next)
+		want = append(want, vals)
+	}
+
+	for i, version := range versions {
+		if got := version.TraverseSlice(TraverseInOrder); !slices.Equal(got, want[i]) {
+			t.Fatalf("version %d: values = %v, want %v", i, got, want[i])
+		}
+		if got := version.Size(); got != len(want[i]) {
+			t.Errorf("version %d: Size() = %d, want %d", i, got, len(want[i]))
+		}
+	}
+}
+
+func TestPersistentBSTOptions(t *testing.T) {
+	tree := NewPersistentBST[int](Descending(), IgnoreDuplicates(false)).InsertAll(3, 1, 4, 1, 5, 9, 2, 6)
+	if got, want := tree.TraverseSlice(TraverseInOrder), []int{9, 6, 5, 4, 3, 2, 1, 1}; !slices.Equal(got, want) {
+		t.Errorf("Descending multiset values = %v, want %v", got, want)
+	}
+
+	// Deleting one copy of a duplicate leaves the other.
+	del := tree.Delete(1)
+	if got, want := del.TraverseSlice(TraverseInOrder), []int{9, 6, 5, 4, 3, 2, 1}; !slices.Equal(got, want) {
+		t.Errorf("values after Delete(1) = %v, want %v", got, want)
+	}
+	if got := tree.Size(); got != 8 {
+		t.Errorf("Size() of the old version after Delete(1) = %d, want 8", got)
+	}
+}
@@ -1,15 +1,65 @@
 package tree
 
-import "golang.org/x/exp/constraints"
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
 
 // RedBlack Tree.
-type RedBlack[T constraints.Ordered] struct {
+type RedBlack[T any] struct {
 	root *redBlackNode[T]
+
+	// size is the count of values in the tree, kept up to date by the
+	// Insert and Delete methods.
+	size int
+
+	// order defines how the values in the tree are arranged.
+	order ordering[T]
 }
 
 // NewRedBlack returns an empty Red-Black tree ready to use.
-func NewRedBlack[T constraints.Ordered]() Tree[T] {
-	return &RedBlack[T]{}
+//
+// Options can include Descending to order the values largest first, and
+// IgnoreDuplicates(false) to keep every copy of equal values inserted.
+func NewRedBlack[T constraints.Ordered](opts ...treeOptionFunc) Tree[T] {
+	treeOpts := defaultOptions()
+	for _, opt := range opts {
+		opt(treeOpts)
+	}
+
+	return &RedBlack[T]{order: newOrdering[T](treeOpts)}
+}
+
+// NewRedBlackFunc returns an empty Red-Black tree ready to use, with its
+// values ordered by the given comparison function instead of their natural
+// order. See NewBSTFunc for the requirements on cmp.
+func NewRedBlackFunc[T any](cmp func(a, b T) int) Tree[T] {
+	return &RedBlack[T]{order: ordering[T]{cmp: cmp}}
+}
+
+// NewRedBlackFromSorted returns a Red-Black tree holding the given values,
+// which must be in ascending order, built directly in balanced shape in O(n)
+// time without any rotations. Only the nodes on the bottom level are red, so
+// every path down the tree passes through the same number of black nodes.
+//
+// Repeated values are only stored once.
+func NewRedBlackFromSorted[T constraints.Ordered](vals []T) *RedBlack[T] {
+	return newRedBlackFromSorted(slices.Compact(slices.Clone(vals)), ordering[T]{}.resolve())
+}
+
+// newRedBlackFromSorted returns a Red-Black tree with the given ordering
+// holding the given values, which must be in order according to it without
+// repeats.
+func newRedBlackFromSorted[T any](vals []T, o ordering[T]) *RedBlack[T] {
+	root := redBlackNodeFromSorted(vals, bits.Len(uint(len(vals)))-1)
+	if root != nil {
+		root.isRed = false
+	}
+	return &RedBlack[T]{root: root, size: len(vals), order: o}
 }
 
 // Root returns the root node of the tree.
@@ -19,13 +69,37 @@ func (t *RedBlack[T]) Root() BinaryTree[T] {
 
 // Insert inserts the node into the tree, growing as needed.
 func (t *RedBlack[T]) Insert(v T) bool {
+	return t.InsertErr(v) == nil
+}
+
+// InsertErr inserts the node into the tree, growing as needed. If the value
+// is already in the tree, and the tree does not keep duplicates, ErrDuplicate
+// is returned.
+func (t *RedBlack[T]) InsertErr(v T) error {
 	if t.root == nil {
 		t.root = &redBlackNode[T]{
 			value: v,
 		}
-		return true
+		t.size++
+		return nil
+	}
+	if !t.root.insert(v, t.order.resolve()) {
+		return ErrDuplicate
+	}
+	t.size++
+	return nil
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+func (t *RedBlack[T]) InsertAll(vals ...T) int {
+	var n int
+	for _, v := range vals {
+		if t.Insert(v) {
+			n++
+		}
 	}
-	return t.root.Insert(v)
+	return n
 }
 
 // Delete the requested node from the tree and reports if it was successful.
@@ -33,10 +107,65 @@ func (t *RedBlack[T]) Insert(v T) bool {
 //
 // The trees internal structure may be updated.
 func (t *RedBlack[T]) Delete(v T) bool {
+	return t.DeleteErr(v) == nil
+}
+
+// DeleteErr removes the requested node from the tree. If the tree is empty
+// ErrEmptyTree is returned, and if the value is not in the tree ErrNotFound
+// is returned, leaving the tree unchanged.
+//
+// Removing values is not yet supported by the nodes of this tree, so values
+// that are found give an error wrapping errors.ErrUnsupported.
+func (t *RedBlack[T]) DeleteErr(v T) error {
 	if t.root == nil {
-		return false
+		return ErrEmptyTree
+	}
+	if !t.Search(v) {
+		return ErrNotFound
+	}
+	if !t.root.Delete(v) {
+		return fmt.Errorf("tree: deleting from a Red-Black tree: %w", errors.ErrUnsupported)
 	}
-	return t.root.Delete(v)
+	t.size--
+	return nil
+}
+
+// Count returns the number of copies of v in the tree. Unless the tree was
+// created to keep duplicates, this is at most one.
+//
+// There is no DeleteOne or DeleteAll, as on the other trees that keep
+// duplicates, while the nodes of this tree can not yet remove values.
+func (t *RedBlack[T]) Count(v T) int {
+	if t.root == nil {
+		return 0
+	}
+	return countValue[T](t.root, v, t.order.resolve())
+}
+
+// CountFunc returns the number of values in the tree for which pred returns
+// true. Every value is checked, but none are collected along the way.
+func (t *RedBlack[T]) CountFunc(pred func(T) bool) int {
+	if t.root == nil {
+		return 0
+	}
+	return countMatching[T](t.root, pred)
+}
+
+// DeleteFunc removes every value in the tree for which pred returns true,
+// returning the number of values removed. The values that are kept are
+// collected and rebuilt into a balanced tree, so this takes O(n) time however
+// many are removed.
+func (t *RedBlack[T]) DeleteFunc(pred func(T) bool) int {
+	if t.root == nil {
+		return 0
+	}
+	kept, removed := valuesExcept[T](t.root, pred, make([]T, 0, t.size))
+	if removed == 0 {
+		return 0
+	}
+	rebuilt := newRedBlackFromSorted(kept, t.order)
+	t.root, t.size = rebuilt.root, rebuilt.size
+	return removed
 }
 
 // Search reports if the given value is in the tree.
@@ -45,13 +174,135 @@ func (t *RedBlack[T]) Search(v T) bool {
 		return false
 	}
 
-	return t.root.Search(v)
+	return t.root.search(v, t.order.resolve())
 }
 
 // Traverse traverse the tree in the specified order emitting the values to
 // the channel. Channel is closed once the final value is emitted.
-func (t *RedBlack[T]) Traverse(w TraverseOrder) <-chan T {
-	return make(chan T)
+func (t *RedBlack[T]) Traverse(tOrder TraverseOrder) <-chan T {
+	ch := make(chan T)
+	go func() {
+		if !t.IsEmpty() {
+			traverseBinaryTree(t.root, tOrder, ch)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// TraverseBuffered is like Traverse, but the channel holds up to bufSize values,
+// letting the traversal run ahead of the reader instead of handing over each
+// value in turn. This gives better throughput when the reader does little
+// work per value. A bufSize below 1 gives an unbuffered channel, as Traverse.
+func (t *RedBlack[T]) TraverseBuffered(tOrder TraverseOrder, bufSize int) <-chan T {
+	ch := make(chan T, max(bufSize, 0))
+	go func() {
+		if t.root != nil {
+			traverseBinaryTree[T](t.root, tOrder, ch)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Walk calls visit with each value of the tree in the given order, stopping
+// as soon as visit returns false. It reports if every value was visited.
+//
+// Unlike Traverse, no goroutine or channel is involved, so stopping early
+// leaves nothing behind.
+func (t *RedBlack[T]) Walk(tOrder TraverseOrder, visit func(T) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	return walkBinaryTree[T](t.root, tOrder, visit)
+}
+
+// AppendValues appends the values of the tree in the given order onto dst and
+// returns the extended slice, in the manner of append. Reusing dst across
+// calls saves allocating a new slice each time as ToSlice does.
+func (t *RedBlack[T]) AppendValues(dst []T, tOrder TraverseOrder) []T {
+	if t.root == nil {
+		return dst
+	}
+	return appendValues[T](dst, t.root, tOrder)
+}
+
+// TraverseSlice returns the values of the tree in the given order as a slice.
+// Unlike Traverse, no goroutine or channel is used, which for small trees
+// costs more than the walk itself, and nothing is left running if the caller
+// stops reading early. Use Traverse to stream the values of large trees.
+func (t *RedBlack[T]) TraverseSlice(tOrder TraverseOrder) []T {
+	if t.root == nil {
+		return nil
+	}
+	return appendValues[T](make([]T, 0, t.size), t.root, tOrder)
+}
+
+// At returns the value at the given 0-based position in order, i.e., At(0)
+// is the smallest value, or the largest if the tree is Descending. The values
+// are counted off from the start, stopping once the index is reached, so this
+// takes O(index) time. If index is out of range, false is returned.
+func (t *RedBlack[T]) At(index int) (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return nthValue[T](t.root, TraverseInOrder, index)
+}
+
+// SelectDescending returns the k-th largest value (0-indexed), or the k-th
+// smallest if the tree is Descending. The values are counted off in reverse
+// order, taking O(k) time. If k is out of range, false is returned.
+func (t *RedBlack[T]) SelectDescending(k int) (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return nthValue[T](t.root, TraverseReverseOrder, k)
+}
+
+// MorrisInOrder returns the values of the tree in order, found with a Morris
+// traversal that needs no stack, recursion or goroutine, only O(1) space
+// beyond the returned slice. The tree is temporarily rethreaded along the way,
+// but left exactly as it was, so it must not be read by anything else while
+// this runs.
+func (t *RedBlack[T]) MorrisInOrder() []T {
+	if t.root == nil {
+		return nil
+	}
+	return morrisInOrder(t.root, (*redBlackNode[T]).childFields, (*redBlackNode[T]).Value, make([]T, 0, t.size))
+}
+
+// LeafCount returns the number of nodes in the tree with no children.
+func (t *RedBlack[T]) LeafCount() int {
+	if t.root == nil {
+		return 0
+	}
+	leaves, _ := countNodeKinds[T](t.root)
+	return leaves
+}
+
+// InternalCount returns the number of nodes in the tree with at least one
+// child, which is every node that is not a leaf.
+func (t *RedBlack[T]) InternalCount() int {
+	if t.root == nil {
+		return 0
+	}
+	_, internal := countNodeKinds[T](t.root)
+	return internal
+}
+
+// Boundary returns the values around the edge of the tree, going
+// anti-clockwise from the root: the root, the left boundary from the top
+// down, every leaf from left to right, and then the right boundary from the
+// bottom up. Values where the boundaries meet are only included once.
+func (t *RedBlack[T]) Boundary() []T {
+	if t.root == nil {
+		return nil
+	}
+	return boundaryValues[T](t.root)
 }
 
 // Height returns the height of the longest path in the tree from the
@@ -62,3 +313,77 @@ func (t *RedBlack[T]) Height() int {
 	}
 	return t.root.Height()
 }
+
+// MinDepth returns the number of edges from the root to the nearest leaf,
+// found with a breadth first search that stops at the first leaf reached. Set
+// against Height, it shows how lopsided the tree is. An empty tree, like one
+// with only a root, returns 0.
+func (t *RedBlack[T]) MinDepth() int {
+	if t.root == nil {
+		return 0
+	}
+	return minLeafDepth[T](t.root)
+}
+
+// BalanceInfo returns the Height, MinDepth and Size of the tree together with
+// its SkewRatio, to show at a glance how far from balanced the tree is.
+func (t *RedBlack[T]) BalanceInfo() BalanceInfo {
+	return newBalanceInfo(t.Height(), t.MinDepth(), t.size)
+}
+
+// Diameter returns the number of nodes on the longest path between any two
+// leaves of the tree, which may or may not pass through the root.
+func (t *RedBlack[T]) Diameter() int {
+	if t.root == nil {
+		return 0
+	}
+	_, d := binaryTreeDiameter[T](t.root)
+	return d
+}
+
+// Size returns the number of values held in the tree.
+func (t *RedBlack[T]) Size() int {
+	return t.size
+}
+
+// IsEmpty reports if the tree holds no values.
+func (t *RedBlack[T]) IsEmpty() bool {
+	return t.root == nil
+}
+
+// String returns the values of the tree indented one level per depth, with
+// the root first and each child after its parent, for use when printing the
+// tree. Large trees are cut short after the first few dozen nodes.
+func (t *RedBlack[T]) String() string {
+	return treeString[T](t.root, t.size)
+}
+
+// Min returns the smallest value in the tree, or the largest if the tree is
+// Descending. If the tree is empty, false is returned.
+func (t *RedBlack[T]) Min() (T, bool) {
+	var zero T
+	if t.root == nil {
+		return zero, false
+	}
+
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+	return n.value, true
+}
+
+// Max returns the largest value in the tree, or the smallest if the tree is
+// Descending. If the tree is empty, false is returned.
+func (t *RedBlack[T]) Max() (T, bool) {
+	var zero T
+	if t.root == nil {
+		return zero, false
+	}
+
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.value, true
+}
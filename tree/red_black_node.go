@@ -1,9 +1,7 @@
 package tree
 
-import "golang.org/x/exp/constraints"
-
 // redBlackNode is the basic node in a Red-Black binary search tree.
-type redBlackNode[T constraints.Ordered] struct {
+type redBlackNode[T any] struct {
 	value T
 
 	isRed bool
@@ -11,6 +9,27 @@ type redBlackNode[T constraints.Ordered] struct {
 	left, right *redBlackNode[T]
 }
 
+// redBlackNodeFromSorted builds a balanced subtree from the given sorted values
+// by recursively using the middle value as the root of each subtree. The
+// nodes redDepth levels down are colored red and all others black.
+//
+// Splitting at the middle leaves every path from the root to a missing child
+// within one node of the same length, so when redDepth is the deepest level
+// every path passes through the same number of black nodes.
+func redBlackNodeFromSorted[T any](vals []T, redDepth int) *redBlackNode[T] {
+	if len(vals) == 0 {
+		return nil
+	}
+
+	mid := len(vals) / 2
+	return &redBlackNode[T]{
+		value: vals[mid],
+		isRed: redDepth == 0,
+		left:  redBlackNodeFromSorted(vals[:mid], redDepth-1),
+		right: redBlackNodeFromSorted(vals[mid+1:], redDepth-1),
+	}
+}
+
 // HasLeft reports if this node has a Left child.
 func (t *redBlackNode[T]) HasLeft() bool {
 	return t.left != nil
@@ -31,6 +50,18 @@ func (t *redBlackNode[T]) Right() BinaryTree[T] {
 	return t.right
 }
 
+// Parent always returns nil, as these nodes do not keep track of their
+// parent.
+func (t *redBlackNode[T]) Parent() BinaryTree[T] {
+	return nil
+}
+
+// childFields returns the addresses of this nodes child pointers, so that
+// generic helpers such as flattenNodes can rearrange them.
+func (t *redBlackNode[T]) childFields() (left, right **redBlackNode[T]) {
+	return &t.left, &t.right
+}
+
 // Value returns this nodes Value.
 func (t *redBlackNode[T]) Value() T {
 	return t.value
@@ -48,27 +79,48 @@ func (t *redBlackNode[T]) Metadata() string {
 // Insert inserts the node into the tree, growing as needed, and reports
 // if the operation was successful.
 func (t *redBlackNode[T]) Insert(v T) bool {
+	return t.insert(v, ordering[T]{}.resolve())
+}
+
+// insert is the worker for Insert, placing v according to the given ordering
+// of the tree.
+func (t *redBlackNode[T]) insert(v T, o ordering[T]) bool {
 	if t == nil {
 		return false
 	}
 
-	if v == t.value {
+	// Duplicates are only allowed if the tree keeps them, in which case
+	// they go to the right.
+	c := o.compare(v, t.value)
+	if c == 0 && !o.duplicates {
 		return false
 	}
 
-	if v < t.value {
+	if c < 0 {
 		if t.left == nil {
 			t.left = &redBlackNode[T]{value: v}
 			return true
 		}
-		return t.left.Insert(v)
+		return t.left.insert(v, o)
 	}
 
 	if t.right == nil {
 		t.right = &redBlackNode[T]{value: v}
 		return true
 	}
-	return t.right.Insert(v)
+	return t.right.insert(v, o)
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+func (t *redBlackNode[T]) InsertAll(vals ...T) int {
+	var n int
+	for _, v := range vals {
+		if t.Insert(v) {
+			n++
+		}
+	}
+	return n
 }
 
 // Delete the requested node from the tree and reports if it was successful.
@@ -83,17 +135,23 @@ func (t *redBlackNode[T]) Delete(v T) bool {
 
 // Search reports if the given value is in the tree.
 func (t *redBlackNode[T]) Search(v T) bool {
+	return t.search(v, ordering[T]{}.resolve())
+}
+
+// search is the worker for Search, following the given ordering of the tree.
+func (t *redBlackNode[T]) search(v T, o ordering[T]) bool {
 	if t == nil {
 		return false
 	}
-	if v == t.value {
+	c := o.compare(v, t.value)
+	if c == 0 {
 		return true
 	}
 
-	if v < t.value {
-		return t.left.Search(v)
+	if c < 0 {
+		return t.left.search(v, o)
 	}
-	return t.right.Search(v)
+	return t.right.search(v, o)
 }
 
 // Walk traverse the tree in the specified order emitting the values to
@@ -101,7 +159,9 @@ func (t *redBlackNode[T]) Search(v T) bool {
 func (t *redBlackNode[T]) Traverse(tOrder TraverseOrder) <-chan T {
 	ch := make(chan T)
 	go func() {
-		traverseBinaryTree(t, tOrder, ch)
+		if !t.IsEmpty() {
+			traverseBinaryTree(t, tOrder, ch)
+		}
 		close(ch)
 	}()
 
@@ -121,3 +181,17 @@ func (t *redBlackNode[T]) Height() int {
 	}
 	return rh + 1
 }
+
+// Size returns the number of values in the tree rooted at this node.
+func (t *redBlackNode[T]) Size() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + t.left.Size() + t.right.Size()
+}
+
+// IsEmpty reports if the tree rooted at this node holds no values, which is
+// only the case for a nil node.
+func (t *redBlackNode[T]) IsEmpty() bool {
+	return t == nil
+}
@@ -0,0 +1,94 @@
+package tree
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// checkRedBlack returns the number of black nodes on every path from n down
+// to a missing child, and reports if that is the same for all of them and no
+// red node has a red child.
+func checkRedBlack(n *redBlackNode[int]) (int, bool) {
+	if n == nil {
+		return 1, true
+	}
+	if n.isRed && ((n.left != nil && n.left.isRed) || (n.right != nil && n.right.isRed)) {
+		return 0, false
+	}
+
+	lh, lok := checkRedBlack(n.left)
+	rh, rok := checkRedBlack(n.right)
+	if !lok || !rok || lh != rh {
+		return 0, false
+	}
+	if !n.isRed {
+		lh++
+	}
+	return lh, true
+}
+
+func TestNewRedBlackFromSorted(t *testing.T) {
+	tests := []struct {
+		vals []int
+		want []int
+	}{
+		{
+			vals: nil,
+			want: nil,
+		},
+		{
+			vals: []int{7},
+			want: []int{7},
+		},
+		{
+			// A complete tree, with a full bottom level.
+			vals: []int{1, 2, 3, 4, 5, 6, 7},
+			want: []int{1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			vals: []int{1, 2, 3, 4, 5, 6, 7, 8},
+			want: []int{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		{
+			// Duplicates are collapsed.
+			vals: []int{-3, -3, 0, 2, 2, 2, 5, 8, 8, 13},
+			want: []int{-3, 0, 2, 5, 8, 13},
+		},
+	}
+
+	// Every size up to a few levels deep.
+	for n := 1; n <= 70; n++ {
+		vals := make([]int, n)
+		for i := range vals {
+			vals[i] = i * 10
+		}
+		tests = append(tests, struct {
+			vals []int
+			want []int
+		}{vals: vals, want: slices.Clone(vals)})
+	}
+
+	for _, test := range tests {
+		tree := NewRedBlackFromSorted(test.vals)
+
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("NewRedBlackFromSorted(%v) in order = %v, want %v", test.vals, got, test.want)
+		}
+		if got := tree.Size(); got != len(test.want) {
+			t.Errorf("NewRedBlackFromSorted(%v).Size() = %d, want %d", test.vals, got, len(test.want))
+		}
+		if got, want := tree.Height(), minHeight(len(test.want)); got != want {
+			t.Errorf("NewRedBlackFromSorted(%v).Height() = %d, want %d", test.vals, got, want)
+		}
+		if tree.root != nil && tree.root.isRed {
+			t.Errorf("NewRedBlackFromSorted(%v) has a red root", test.vals)
+		}
+		if _, ok := checkRedBlack(tree.root); !ok {
+			t.Errorf("NewRedBlackFromSorted(%v) does not hold the Red-Black invariants", test.vals)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package tree
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// SegmentTree holds a fixed length sequence of values and answers queries
+// combining the values over any range of indexes in O(log n) time, while
+// still allowing individual values to be changed in O(log n) time. The
+// values are combined by summing them, or with any other associative
+// operation, such as taking the minimum, given to NewSegmentTreeFunc.
+//
+// Unlike the other trees in this package, a segment tree is arranged by the
+// index of each value rather than by the values themselves, so it is kept as
+// a flat array instead of linked nodes. The values are the leaves at
+// positions n through 2n-1, and each internal node i holds the combination
+// of its children at 2i and 2i+1, with the root at 1.
+type SegmentTree[T any] struct {
+	n    int
+	tree []T
+
+	// combine merges the results for two adjacent ranges, with a covering
+	// the lower indexes.
+	combine func(a, b T) T
+
+	// identity is the result for an empty range, which leaves any value
+	// unchanged when combined with it.
+	identity T
+}
+
+// NewSegmentTree returns a SegmentTree holding a copy of the given values,
+// answering queries with the sum over the range.
+func NewSegmentTree[T constraints.Integer | constraints.Float](vals []T) *SegmentTree[T] {
+	return NewSegmentTreeFunc(vals, func(a, b T) T { return a + b }, 0)
+}
+
+// NewSegmentTreeFunc returns a SegmentTree holding a copy of the given
+// values, answering queries by merging the values over the range with
+// combine.
+//
+// combine must be associative, and identity must leave any value unchanged
+// when combined with it, such as 0 for addition or the largest possible
+// value for taking the minimum.
+func NewSegmentTreeFunc[T any](vals []T, combine func(a, b T) T, identity T) *SegmentTree[T] {
+	n := len(vals)
+	t := &SegmentTree[T]{
+		n:        n,
+		tree:     make([]T, 2*n),
+		combine:  combine,
+		identity: identity,
+	}
+	copy(t.tree[n:], vals)
+	for i := n - 1; i > 0; i-- {
+		t.tree[i] = combine(t.tree[2*i], t.tree[2*i+1])
+	}
+	return t
+}
+
+// Len returns the number of values in the tree.
+func (t *SegmentTree[T]) Len() int {
+	return t.n
+}
+
+// Query returns the combination of the values at indexes lo through hi,
+// inclusive. It panics unless 0 <= lo <= hi < Len().
+func (t *SegmentTree[T]) Query(lo, hi int) T {
+	if lo < 0 || hi < lo || hi >= t.n {
+		panic(fmt.Sprintf("tree: segment tree query [%d, %d] out of range with length %d", lo, hi, t.n))
+	}
+
+	// Work up from the leaves, taking in the nodes at the edges of the range
+	// whose parents also cover values outside of it. The two ends are kept
+	// apart so the values are combined in index order.
+	left, right := t.identity, t.identity
+	for l, r := lo+t.n, hi+t.n+1; l < r; l, r = l/2, r/2 {
+		if l%2 == 1 {
+			left = t.combine(left, t.tree[l])
+			l++
+		}
+		if r%2 == 1 {
+			r--
+			right = t.combine(t.tree[r], right)
+		}
+	}
+	return t.combine(left, right)
+}
+
+// Update sets the value at index i to v, recomputing every range above it up
+// to the root. It panics unless 0 <= i < Len().
+func (t *SegmentTree[T]) Update(i int, v T) {
+	if i < 0 || i >= t.n {
+		panic(fmt.Sprintf("tree: segment tree index %d out of range with length %d", i, t.n))
+	}
+
+	i += t.n
+	t.tree[i] = v
+	for i /= 2; i > 0; i /= 2 {
+		t.tree[i] = t.combine(t.tree[2*i], t.tree[2*i+1])
+	}
+}
@@ -0,0 +1,157 @@
+package tree
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSegmentTreeQuery(t *testing.T) {
+	vals := []int{5, -2, 7, 0, 3, 3, -8, 10}
+	tree := NewSegmentTree(vals)
+
+	tests := []struct {
+		lo, hi int
+		want   int
+	}{
+		{lo: 0, hi: 7, want: 18},
+		{lo: 0, hi: 0, want: 5},
+		{lo: 7, hi: 7, want: 10},
+		{lo: 1, hi: 3, want: 5},
+		{lo: 2, hi: 6, want: 5},
+		{lo: 3, hi: 4, want: 3},
+	}
+	for _, test := range tests {
+		if got := tree.Query(test.lo, test.hi); got != test.want {
+			t.Errorf("Query(%d, %d) = %d, want %d", test.lo, test.hi, got, test.want)
+		}
+	}
+
+	// The tree holds its own copy of the values.
+	vals[0] = 100
+	if got := tree.Query(0, 0); got != 5 {
+		t.Errorf("Query(0, 0) after changing the input slice = %d, want 5", got)
+	}
+}
+
+func TestSegmentTreeRandomUpdates(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	// Odd and even lengths fill the leaves differently.
+	for _, n := range []int{1, 2, 7, 64, 100} {
+		vals := make([]float64, n)
+		for i := range vals {
+			vals[i] = float64(r.Intn(200) - 100)
+		}
+		tree := NewSegmentTree(vals)
+
+		for step := 0; step < 200; step++ {
+			i, v := r.Intn(n), float64(r.Intn(200)-100)
+			vals[i] = v
+			tree.Update(i, v)
+
+			// Compare against sums from a freshly computed prefix sum.
+			prefix := make([]float64, n+1)
+			for j, v := range vals {
+				prefix[j+1] = prefix[j] + v
+			}
+			lo := r.Intn(n)
+			hi := lo + r.Intn(n-lo)
+			if got, want := tree.Query(lo, hi), prefix[hi+1]-prefix[lo]; got != want {
+				t.Fatalf("n = %d, step %d: Query(%d, %d) = %v, want %v", n, step, lo, hi, got, want)
+			}
+		}
+		if got := tree.Len(); got != n {
+			t.Errorf("Len() = %d, want %d", got, n)
+		}
+	}
+}
+
+func TestSegmentTreeFunc(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	vals := make([]int, 37)
+	for i := range vals {
+		vals[i] = r.Intn(1000) - 500
+	}
+
+	tests := []struct {
+		name     string
+		combine  func(a, b int) int
+		identity int
+	}{
+		{
+			name:     "min",
+			combine:  func(a, b int) int { return min(a, b) },
+			identity: math.MaxInt,
+		},
+		{
+			name:     "max",
+			combine:  func(a, b int) int { return max(a, b) },
+			identity: math.MinInt,
+		},
+	}
+
+	for _, test := range tests {
+		current := slices.Clone(vals)
+		tree := NewSegmentTreeFunc(current, test.combine, test.identity)
+
+		for step := 0; step < 300; step++ {
+			if step%3 == 0 {
+				i, v := r.Intn(len(current)), r.Intn(1000)-500
+				current[i] = v
+				tree.Update(i, v)
+			}
+
+			lo := r.Intn(len(current))
+			hi := lo + r.Intn(len(current)-lo)
+			want := test.identity
+			for _, v := range current[lo : hi+1] {
+				want = test.combine(want, v)
+			}
+			if got := tree.Query(lo, hi); got != want {
+				t.Fatalf("%s, step %d: Query(%d, %d) = %d, want %d", test.name, step, lo, hi, got, want)
+			}
+		}
+	}
+
+	// Values are combined in index order, so operations need not be
+	// commutative.
+	words := []string{"a", "b", "c", "d", "e", "f", "g"}
+	concat := NewSegmentTreeFunc(words, func(a, b string) string { return a + b }, "")
+	concat.Update(3, "D")
+	words[3] = "D"
+	for lo := range words {
+		for hi := lo; hi < len(words); hi++ {
+			if got, want := concat.Query(lo, hi), strings.Join(words[lo:hi+1], ""); got != want {
+				t.Errorf("concatenating Query(%d, %d) = %q, want %q", lo, hi, got, want)
+			}
+		}
+	}
+}
+
+func TestSegmentTreeOutOfRange(t *testing.T) {
+	tree := NewSegmentTree([]int{1, 2, 3})
+
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{name: "Query(-1, 1)", f: func() { tree.Query(-1, 1) }},
+		{name: "Query(2, 1)", f: func() { tree.Query(2, 1) }},
+		{name: "Query(0, 3)", f: func() { tree.Query(0, 3) }},
+		{name: "Update(3, 0)", f: func() { tree.Update(3, 0) }},
+	}
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", test.name)
+				}
+			}()
+			test.f()
+		}()
+	}
+}
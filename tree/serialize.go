@@ -0,0 +1,111 @@
+package tree
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+// nilMarker stands in for a missing child in the output of Serialize.
+const nilMarker = "#"
+
+// Serialize returns the tree as a string listing its nodes in pre order, with
+// a # in place of each missing child, separated by commas. Each value is
+// written in its JSON form, so strings are quoted and may hold any character.
+// For example, a root of 2 with a lone left child of 1 is:
+//
+//	2,1,#,#,#
+//
+// The whole shape of the tree is kept, not just its values, so Deserialize
+// gives back the same tree even if it is not a valid binary search tree. An
+// empty tree is a single #. As each node is written once, the copies of a
+// value a Treap keeping duplicates counts within one node come back as one.
+func Serialize[T any](t BinaryTree[T]) (string, error) {
+	var buf strings.Builder
+	var emit func(n BinaryTree[T], present bool) error
+	emit = func(n BinaryTree[T], present bool) error {
+		if buf.Len() > 0 {
+			buf.WriteByte(',')
+		}
+		if !present {
+			buf.WriteString(nilMarker)
+			return nil
+		}
+
+		b, err := json.Marshal(n.Value())
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+
+		if err := emit(n.Left(), n.HasLeft()); err != nil {
+			return err
+		}
+		return emit(n.Right(), n.HasRight())
+	}
+	if err := emit(t, !isTreeNil(t)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Deserialize returns a BST with the shape and values given in the form
+// produced by Serialize. As with FromLevelArray the shape is taken as given,
+// so the values must be in binary search tree order for the tree to be
+// searched. See IsValidBST.
+//
+// If s is not in the form of Serialize, an error is returned.
+func Deserialize[T constraints.Ordered](s string) (*BST[T], error) {
+	var pos, size int
+
+	// next reads the entry at pos, reporting false for a missing child.
+	next := func() (T, bool, error) {
+		var v T
+		if pos > 0 {
+			if pos >= len(s) || s[pos] != ',' {
+				return v, false, fmt.Errorf("tree: deserializing: want ',' at offset %d", pos)
+			}
+			pos++
+		}
+		if strings.HasPrefix(s[pos:], nilMarker) {
+			pos += len(nilMarker)
+			return v, false, nil
+		}
+
+		dec := json.NewDecoder(strings.NewReader(s[pos:]))
+		if err := dec.Decode(&v); err != nil {
+			return v, false, fmt.Errorf("tree: deserializing value at offset %d: %w", pos, err)
+		}
+		pos += int(dec.InputOffset())
+		return v, true, nil
+	}
+
+	var build func() (*bstNode[T], error)
+	build = func() (*bstNode[T], error) {
+		v, ok, err := next()
+		if err != nil || !ok {
+			return nil, err
+		}
+		size++
+
+		node := &bstNode[T]{value: v}
+		if node.left, err = build(); err != nil {
+			return nil, err
+		}
+		if node.right, err = build(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+
+	root, err := build()
+	if err != nil {
+		return nil, err
+	}
+	if pos != len(s) {
+		return nil, fmt.Errorf("tree: deserializing: unexpected %q after the end of the tree", s[pos:])
+	}
+	return &BST[T]{root: root, size: size, order: ordering[T]{}.resolve()}, nil
+}
@@ -0,0 +1,116 @@
+package tree
+
+import "testing"
+
+func TestSerializeDeserialize(t *testing.T) {
+	tests := []struct {
+		name string
+		tree BinaryTree[int]
+		want string
+	}{
+		{
+			name: "empty",
+			tree: (&BST[int]{}).Root(),
+			want: "#",
+		},
+		{
+			name: "single node",
+			tree: newBSTWith(42).Root(),
+			want: "42,#,#",
+		},
+		{
+			name: "balanced",
+			tree: newBSTWith(4, 2, 6, 1, 3, 5, 7).Root(),
+			want: "4,2,1,#,#,3,#,#,6,5,#,#,7,#,#",
+		},
+		{
+			name: "left skewed",
+			tree: newBSTWith(3, 2, 1).Root(),
+			want: "3,2,1,#,#,#,#",
+		},
+		{
+			name: "right skewed",
+			tree: newBSTWith(-1, 2, 30).Root(),
+			want: "-1,#,2,#,30,#,#",
+		},
+		{
+			// The shape is kept even when it is not in search order.
+			name: "not a BST",
+			tree: &bstNode[int]{
+				value: 1,
+				left:  &bstNode[int]{value: 5},
+				right: &bstNode[int]{value: 0, right: &bstNode[int]{value: 9}},
+			},
+			want: "1,5,#,#,0,#,9,#,#",
+		},
+		{
+			name: "AVL",
+			tree: avlTestTree.Root(),
+		},
+	}
+
+	for _, test := range tests {
+		got, err := Serialize(test.tree)
+		if err != nil {
+			t.Errorf("%s: Serialize() error = %v", test.name, err)
+			continue
+		}
+		if test.want != "" && got != test.want {
+			t.Errorf("%s: Serialize() = %q, want %q", test.name, got, test.want)
+		}
+
+		tree, err := Deserialize[int](got)
+		if err != nil {
+			t.Errorf("%s: Deserialize(%q) error = %v", test.name, got, err)
+			continue
+		}
+		if !binaryTreesEqual(tree.Root(), test.tree) {
+			t.Errorf("%s: Deserialize(Serialize()) = %v, want %v", test.name, tree, test.tree)
+		}
+		if tree.Size() != test.tree.Size() {
+			t.Errorf("%s: Deserialize(Serialize()).Size() = %d, want %d", test.name, tree.Size(), test.tree.Size())
+		}
+	}
+}
+
+func TestSerializeStrings(t *testing.T) {
+	// Values holding the separator or the nil marker are quoted.
+	tree := &BST[string]{}
+	tree.InsertAll("m", "a,b", "#", "z \"q\"")
+
+	s, err := Serialize(tree.Root())
+	if err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+	want := `"m","a,b","#",#,#,#,"z \"q\"",#,#`
+	if s != want {
+		t.Errorf("Serialize() = %q, want %q", s, want)
+	}
+
+	got, err := Deserialize[string](s)
+	if err != nil {
+		t.Fatalf("Deserialize(%q) error = %v", s, err)
+	}
+	if !binaryTreesEqual(got.Root(), tree.Root()) {
+		t.Errorf("Deserialize(Serialize()) = %v, want %v", got, tree)
+	}
+}
+
+func TestDeserializeInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		",",
+		"1",
+		"1,#",
+		"1,#,#,#",
+		"1,#,#,",
+		"1;#;#",
+		"x,#,#",
+		`"a",#,#`,
+		"##",
+	} {
+		if tree, err := Deserialize[int](s); err == nil {
+			t.Errorf("Deserialize(%q) = %v, nil, want an error", s, tree)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package tree
+
+import "sync"
+
+// SyncTree wraps a Tree to make it safe for concurrent use by multiple
+// goroutines. Methods that only read the tree share a read lock, while those
+// that change it take the write lock.
+//
+// All access to the wrapped tree must go through the SyncTree once it has
+// been wrapped.
+type SyncTree[T any] struct {
+	mu   sync.RWMutex
+	tree Tree[T]
+}
+
+// NewSyncTree returns a SyncTree guarding the given tree.
+func NewSyncTree[T any](t Tree[T]) *SyncTree[T] {
+	return &SyncTree[T]{tree: t}
+}
+
+// Insert adds the given value into the tree.
+// If the value could not be added, false is returned.
+func (t *SyncTree[T]) Insert(v T) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.Insert(v)
+}
+
+// InsertAll adds the given values into the tree, returning how many of them
+// were added.
+func (t *SyncTree[T]) InsertAll(vals ...T) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.InsertAll(vals...)
+}
+
+// Delete the requested node from the tree and reports if it was successful.
+func (t *SyncTree[T]) Delete(v T) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.Delete(v)
+}
+
+// Search reports if the given value is in the tree.
+func (t *SyncTree[T]) Search(v T) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Search(v)
+}
+
+// Height returns the height of the longest path in the tree from the
+// root node to the farthest leaf.
+func (t *SyncTree[T]) Height() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Height()
+}
+
+// Size returns the number of values held in the tree.
+func (t *SyncTree[T]) Size() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Size()
+}
+
+// IsEmpty reports if the tree holds no values.
+func (t *SyncTree[T]) IsEmpty() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.IsEmpty()
+}
+
+// Traverse traverses the tree in the specified order emitting the values to
+// the channel. Channel is closed once the final value is emitted.
+//
+// The values are copied out under the read lock before Traverse returns, so
+// the lock is not held while the caller reads from the channel, and changes
+// made to the tree after Traverse returns are not seen.
+func (t *SyncTree[T]) Traverse(tOrder TraverseOrder) <-chan T {
+	vals := t.snapshot(tOrder)
+
+	ch := make(chan T)
+	go func() {
+		for _, v := range vals {
+			ch <- v
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// read calls fn with the wrapped tree under the read lock, for the functions
+// of this package that work on the tree as a whole, such as Filter and Map.
+// fn must not change the tree, or keep it past its return.
+func (t *SyncTree[T]) read(fn func(Tree[T])) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	fn(t.tree)
+}
+
+// replace swaps the wrapped tree for the one fn returns given it, under the
+// write lock, for the functions of this package that rebuild a tree, such as
+// Rebalance.
+func (t *SyncTree[T]) replace(fn func(Tree[T]) Tree[T]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tree = fn(t.tree)
+}
+
+// snapshot returns the values of the tree in the given order, read under the
+// read lock.
+func (t *SyncTree[T]) snapshot(tOrder TraverseOrder) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	vals := make([]T, 0, t.tree.Size())
+
+	// Walk avoids the goroutine behind Traverse where the tree has it.
+	if w, ok := t.tree.(interface {
+		Walk(TraverseOrder, func(T) bool) bool
+	}); ok {
+		w.Walk(tOrder, func(v T) bool {
+			vals = append(vals, v)
+			return true
+		})
+		return vals
+	}
+
+	for v := range t.tree.Traverse(tOrder) {
+		vals = append(vals, v)
+	}
+	return vals
+}
@@ -0,0 +1,303 @@
+package tree
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Run with -race to check the locking.
+func TestSyncTreeConcurrent(t *testing.T) {
+	const (
+		writers   = 8
+		readers   = 8
+		perWriter = 500
+	)
+
+	tests := []struct {
+		name string
+		tree Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: &BST[int]{},
+		},
+		{
+			name: "AVL",
+			tree: &AVL[int]{},
+		},
+	}
+
+	for _, test := range tests {
+		tree := NewSyncTree(test.tree)
+
+		var writing sync.WaitGroup
+		for w := 0; w < writers; w++ {
+			writing.Add(1)
+			go func(w int) {
+				defer writing.Done()
+				// Each writer inserts its own interleaved share of values.
+				for i := 0; i < perWriter; i++ {
+					tree.Insert(i*writers + w)
+				}
+				tree.InsertAll(w, w+writers)
+			}(w)
+		}
+
+		done := make(chan struct{})
+		var reading sync.WaitGroup
+		for r := 0; r < readers; r++ {
+			reading.Add(1)
+			go func(r int) {
+				defer reading.Done()
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					tree.Search(r)
+					tree.Height()
+					tree.Size()
+
+					// Snapshots must always be in order.
+					var got []int
+					for v := range tree.Traverse(TraverseInOrder) {
+						got = append(got, v)
+					}
+					if !sort.IntsAreSorted(got) {
+						t.Errorf("%s: Traverse() = %v, want sorted values", test.name, got)
+					}
+				}
+			}(r)
+		}
+
+		writing.Wait()
+		close(done)
+		reading.Wait()
+
+		if got, want := tree.Size(), writers*perWriter; got != want {
+			t.Errorf("%s: Size() = %d, want %d", test.name, got, want)
+		}
+		var got []int
+		for v := range tree.Traverse(TraverseInOrder) {
+			got = append(got, v)
+		}
+		if len(got) != writers*perWriter || !sort.IntsAreSorted(got) {
+			t.Errorf("%s: Traverse() emitted %d values, want %d sorted values",
+				test.name, len(got), writers*perWriter)
+		}
+	}
+}
+
+func TestSyncTreeTraverseSnapshot(t *testing.T) {
+	tree := NewSyncTree[int](&BST[int]{})
+	tree.InsertAll(2, 1, 3)
+
+	// Holding the channel open must not block writers.
+	ch := tree.Traverse(TraversePreOrder)
+	if !tree.Insert(4) {
+		t.Fatalf("Insert(4) = false, want true")
+	}
+
+	var got []int
+	for v := range ch {
+		got = append(got, v)
+	}
+	if want := []int{2, 1, 3}; !slices.Equal(got, want) {
+		t.Errorf("Traverse() = %v, want the snapshot %v", got, want)
+	}
+
+	// Red-Black trees are read through Walk.
+	rb := NewSyncTree[int](&RedBlack[int]{})
+	rb.InsertAll(5, 3, 8)
+	got = nil
+	for v := range rb.Traverse(TraverseInOrder) {
+		got = append(got, v)
+	}
+	if want := []int{3, 5, 8}; !slices.Equal(got, want) {
+		t.Errorf("RedBlack Traverse() = %v, want %v", got, want)
+	}
+}
+
+func TestSyncTreeTreeFunctions(t *testing.T) {
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+	tree := NewSyncTree[int](newBSTWith(vals...))
+
+	filtered := Filter[int](tree, func(v int) bool { return v > 0 })
+	if _, ok := filtered.(*SyncTree[int]); !ok {
+		t.Errorf("Filter(SyncTree) = %T, want *SyncTree[int]", filtered)
+	}
+	if got, want := ToSlice(filtered), []int{1, 11, 21, 30, 42, 57, 84}; !slices.Equal(got, want) {
+		t.Errorf("Filter(SyncTree) = %v, want %v", got, want)
+	}
+
+	mapped := Map[int, int](tree, func(v int) int { return v * 2 })
+	if _, ok := mapped.(*SyncTree[int]); !ok {
+		t.Errorf("Map(SyncTree) = %T, want *SyncTree[int]", mapped)
+	}
+	if got, want := ToSlice(mapped), []int{-26, 2, 22, 42, 60, 84, 114, 168}; !slices.Equal(got, want) {
+		t.Errorf("Map(SyncTree) = %v, want %v", got, want)
+	}
+
+	trimmed := Trim[int](tree, 10, 50)
+	if _, ok := trimmed.(*SyncTree[int]); !ok {
+		t.Errorf("Trim(SyncTree) = %T, want *SyncTree[int]", trimmed)
+	}
+	if got, want := ToSlice(trimmed), []int{11, 21, 30, 42}; !slices.Equal(got, want) {
+		t.Errorf("Trim(SyncTree) = %v, want %v", got, want)
+	}
+
+	var mirrored []int
+	InOrderInto(Mirror[int](tree), &mirrored)
+	if want := []int{84, 57, 42, 30, 21, 11, 1, -13}; !slices.Equal(mirrored, want) {
+		t.Errorf("Mirror(SyncTree) in order = %v, want %v", mirrored, want)
+	}
+
+	// The wrapped tree is left as it was.
+	if got := ToSlice[int](tree); len(got) != len(vals) {
+		t.Errorf("SyncTree after the functions holds %v, want %d values", got, len(vals))
+	}
+
+	// Trees without nodes, like a SyncTree, are read through Traverse.
+	words := NewTernary()
+	words.InsertAll("cat", "car", "dog")
+	if got, want := ToSlice(Filter[string](words, func(w string) bool { return w != "car" })), []string{"cat", "dog"}; !slices.Equal(got, want) {
+		t.Errorf("Filter(Ternary) = %v, want %v", got, want)
+	}
+}
+
+// concurrentWritePercents are the percentages of writes run by
+// BenchmarkConcurrentSearch when no ratio is given on the command line.
+var concurrentWritePercents = []int{0, 1, 10, 50}
+
+// concurrentTree is a tree safe for concurrent use, as compared by the
+// concurrent benchmarks.
+type concurrentTree struct {
+	name string
+	tree func() Tree[int]
+}
+
+// concurrentTrees returns the trees run by the concurrent benchmarks that
+// match the tree_type_filter flag. Each of the benchmark tree types is
+// wrapped in a SyncTree, where readers share a lock that writers hold alone.
+// Against these is a COWTree, where readers take no lock at all and writers
+// copy the path they change. Its PersistentBST does no balancing, so the
+// fair comparison is with the SyncTree of a BST.
+func concurrentTrees() []concurrentTree {
+	var trees []concurrentTree
+	for _, example := range benchmarkTrees {
+		if treeTypeMatches(example.name, *treeTypeFilter) {
+			trees = append(trees, concurrentTree{
+				name: "Sync" + example.name,
+				tree: func() Tree[int] { return NewSyncTree(example.tree()) },
+			})
+		}
+	}
+	if treeTypeMatches("BST", *treeTypeFilter) {
+		trees = append(trees, concurrentTree{
+			name: "COWBST",
+			tree: func() Tree[int] { return NewCOWTree[int]() },
+		})
+	}
+	return trees
+}
+
+// freshVals returns a function giving values that are not in any tree filled
+// from testIntVals, which are never negative. Each goroutine of a benchmark
+// should take its own, as the source is not safe for concurrent use.
+func freshVals(seed int64) func() int {
+	rng := rand.New(rand.NewSource(seed))
+	return func() int {
+		return -1 - rng.Int()
+	}
+}
+
+// To run the concurrent benchmarks use this command, adding -cpu to vary
+// the number of goroutines:
+//
+// go test . --test.bench="BenchmarkConcurrent" --concurrent_write_percent=n
+//
+// The results of SyncBST and COWBST under the same write percentage compare
+// the two ways of sharing one tree between goroutines.
+
+// BenchmarkConcurrentInsert measures the contention of goroutines that all
+// insert into the same tree. Each tree is filled with n values before the
+// timer starts, and the goroutines then insert fresh values, so that every
+// insert adds a value rather than finding it already there.
+func BenchmarkConcurrentInsert(b *testing.B) {
+	for _, example := range concurrentTrees() {
+		for _, n := range insertSteps {
+			// Skip any tests that are outside the limit.
+			if n > *treeSizeUpperLimit {
+				break
+			}
+
+			b.Run(fmt.Sprintf("%s-%06d", example.name, n),
+				func(b *testing.B) {
+					tree := example.tree()
+					for _, v := range testIntVals[:n] {
+						tree.Insert(v)
+					}
+					var seed atomic.Int64
+
+					b.ResetTimer()
+					b.RunParallel(func(pb *testing.PB) {
+						fresh := freshVals(seed.Add(1))
+						for pb.Next() {
+							tree.Insert(fresh())
+						}
+					})
+				})
+		}
+	}
+}
+
+// BenchmarkConcurrentSearch measures the contention of goroutines sharing
+// a tree of n values, where the given percentage of the operations are
+// inserts of fresh values and the rest are searches. Readers of a SyncTree
+// share the lock with each other, so the throughput falls as the share of
+// writers grows, while readers of a COWTree are never held up by writers.
+func BenchmarkConcurrentSearch(b *testing.B) {
+	writePercents := concurrentWritePercents
+	if *concurrentWritePercent >= 0 {
+		writePercents = []int{*concurrentWritePercent}
+	}
+
+	for _, example := range concurrentTrees() {
+		for _, n := range insertSteps {
+			// Skip any tests that are outside the limit.
+			if n > *treeSizeUpperLimit {
+				break
+			}
+
+			for _, writes := range writePercents {
+				b.Run(fmt.Sprintf("%s-%06d-%dpct-writes", example.name, n, writes),
+					func(b *testing.B) {
+						tree := example.tree()
+						for _, v := range testIntVals[:n] {
+							tree.Insert(v)
+						}
+						var next, seed atomic.Int64
+
+						b.ResetTimer()
+						b.RunParallel(func(pb *testing.PB) {
+							fresh := freshVals(seed.Add(1))
+							for pb.Next() {
+								i := int(next.Add(1))
+								if i%100 < writes {
+									tree.Insert(fresh())
+								} else {
+									_ = tree.Search(testIntVals[i%n])
+								}
+							}
+						})
+					})
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package tree
+
+// Ternary is a ternary search tree holding strings.
+//
+// Each node holds a single byte, with up to three children: words with a
+// smaller byte in that position go to the lo child, larger to the hi child,
+// and the remaining bytes of words sharing it continue down the eq child.
+// Words sharing a prefix share the nodes for it, which makes finding all the
+// words starting with a given prefix cheap.
+//
+// The empty string can not be stored in the tree.
+type Ternary struct {
+	root *ternaryNode
+
+	// size is the count of words in the tree, kept up to date by the
+	// Insert and Delete methods.
+	size int
+}
+
+// NewTernary returns an empty Ternary search tree ready to use.
+func NewTernary() *Ternary {
+	return &Ternary{}
+}
+
+// Insert adds the given word into the tree. If the word is already in the
+// tree, or is empty, false is returned.
+func (t *Ternary) Insert(word string) bool {
+	if word == "" {
+		return false
+	}
+
+	var added bool
+	t.root, added = t.root.insert(word)
+	if added {
+		t.size++
+	}
+	return added
+}
+
+// InsertAll inserts each of the words into the tree, returning the count of
+// words that were added.
+func (t *Ternary) InsertAll(words ...string) int {
+	var n int
+	for _, w := range words {
+		if t.Insert(w) {
+			n++
+		}
+	}
+	return n
+}
+
+// Delete removes the given word from the tree and reports if it was
+// successful. If the word is not in the tree, the tree is unchanged and
+// false is returned.
+//
+// Any nodes left leading to no other words are removed along with it.
+func (t *Ternary) Delete(word string) bool {
+	if word == "" {
+		return false
+	}
+
+	var removed bool
+	t.root, removed = t.root.delete(word)
+	if removed {
+		t.size--
+	}
+	return removed
+}
+
+// Search reports if the given word is in the tree. Prefixes of words in the
+// tree are not found unless they were inserted as words themselves.
+func (t *Ternary) Search(word string) bool {
+	n := t.root.find(word)
+	return n != nil && n.terminal
+}
+
+// HasPrefix reports if any word in the tree starts with the given prefix. A
+// word is a prefix of itself. Every tree with a word in it has the empty
+// prefix.
+func (t *Ternary) HasPrefix(prefix string) bool {
+	if prefix == "" {
+		return t.root != nil
+	}
+	// The node for the last byte may remain only to hold other words below
+	// its lo or hi children.
+	n := t.root.find(prefix)
+	return n != nil && (n.terminal || n.eq != nil)
+}
+
+// PrefixSearch returns the words in the tree starting with the given prefix
+// in sorted order, such as for offering completions of partly typed words.
+// The empty prefix returns every word in the tree. If no words start with
+// the prefix, the result is empty.
+func (t *Ternary) PrefixSearch(prefix string) []string {
+	var words []string
+	collect := func(w string) bool {
+		words = append(words, w)
+		return true
+	}
+
+	if prefix == "" {
+		t.root.walk(nil, false, collect)
+		return words
+	}
+
+	n := t.root.find(prefix)
+	if n == nil {
+		return nil
+	}
+	if n.terminal {
+		words = append(words, prefix)
+	}
+	n.eq.walk([]byte(prefix), false, collect)
+	return words
+}
+
+// Traverse traverses the tree emitting the words to the channel. Channel is
+// closed once the final word is emitted.
+//
+// Words are emitted in reverse sorted order for TraverseReverseOrder, and in
+// sorted order for all the other orders, which have no meaning for words.
+func (t *Ternary) Traverse(tOrder TraverseOrder) <-chan string {
+	ch := make(chan string)
+	go func() {
+		t.Walk(tOrder, func(w string) bool {
+			ch <- w
+			return true
+		})
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Walk calls visit with each word in the tree in the order described by
+// Traverse, stopping as soon as visit returns false. It reports if every
+// word was visited.
+func (t *Ternary) Walk(tOrder TraverseOrder, visit func(string) bool) bool {
+	return t.root.walk(nil, tOrder == TraverseReverseOrder, visit)
+}
+
+// Height returns the number of nodes on the longest path in the tree from
+// the root node to the farthest leaf.
+func (t *Ternary) Height() int {
+	return t.root.height()
+}
+
+// Size returns the number of words held in the tree.
+func (t *Ternary) Size() int {
+	return t.size
+}
+
+// IsEmpty reports if the tree holds no words.
+func (t *Ternary) IsEmpty() bool {
+	return t.root == nil
+}
@@ -0,0 +1,137 @@
+package tree
+
+// ternaryNode is a node in a Ternary search tree, holding a single byte of
+// the words stored in the tree.
+type ternaryNode struct {
+	char byte
+
+	// terminal marks the node as the last byte of a word in the tree.
+	terminal bool
+
+	// lo and hi hold the words with a smaller or larger byte at this
+	// position, and eq holds the rest of the words that share this byte.
+	lo, eq, hi *ternaryNode
+}
+
+// insert adds the remainder of the word starting at this node, creating
+// nodes as needed, and returns the root of the subtree along with whether
+// the word was added. The word must not be empty.
+func (t *ternaryNode) insert(word string) (*ternaryNode, bool) {
+	if t == nil {
+		t = &ternaryNode{char: word[0]}
+	}
+
+	var added bool
+	switch {
+	case word[0] < t.char:
+		t.lo, added = t.lo.insert(word)
+	case word[0] > t.char:
+		t.hi, added = t.hi.insert(word)
+	case len(word) > 1:
+		t.eq, added = t.eq.insert(word[1:])
+	default:
+		added = !t.terminal
+		t.terminal = true
+	}
+	return t, added
+}
+
+// find returns the node holding the last byte of the given word, which need
+// not be a complete word in the tree, or nil if there is none.
+func (t *ternaryNode) find(word string) *ternaryNode {
+	if word == "" {
+		return nil
+	}
+
+	n := t
+	for n != nil {
+		switch {
+		case word[0] < n.char:
+			n = n.lo
+		case word[0] > n.char:
+			n = n.hi
+		case len(word) > 1:
+			word = word[1:]
+			n = n.eq
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// delete removes the remainder of the word starting at this node, returning
+// the root of the subtree, which is nil once nothing is left in it, along
+// with whether the word was removed.
+func (t *ternaryNode) delete(word string) (*ternaryNode, bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	var removed bool
+	switch {
+	case word[0] < t.char:
+		t.lo, removed = t.lo.delete(word)
+	case word[0] > t.char:
+		t.hi, removed = t.hi.delete(word)
+	case len(word) > 1:
+		t.eq, removed = t.eq.delete(word[1:])
+	default:
+		removed = t.terminal
+		t.terminal = false
+	}
+
+	// Drop nodes that no longer lead to any words.
+	if !t.terminal && t.lo == nil && t.eq == nil && t.hi == nil {
+		return nil, removed
+	}
+	return t, removed
+}
+
+// walk calls visit with each word in the subtree in sorted order, or in
+// reverse if reverse is set, each prefixed with the given bytes. It stops as
+// soon as visit returns false, reporting if every word was visited.
+func (t *ternaryNode) walk(prefix []byte, reverse bool, visit func(string) bool) bool {
+	if t == nil {
+		return true
+	}
+
+	first, last := t.lo, t.hi
+	if reverse {
+		first, last = last, first
+	}
+
+	if !first.walk(prefix, reverse, visit) {
+		return false
+	}
+
+	// A word comes before any longer words it is a prefix of.
+	prefix = append(prefix, t.char)
+	if reverse {
+		if !t.eq.walk(prefix, reverse, visit) {
+			return false
+		}
+		if t.terminal && !visit(string(prefix)) {
+			return false
+		}
+	} else {
+		if t.terminal && !visit(string(prefix)) {
+			return false
+		}
+		if !t.eq.walk(prefix, reverse, visit) {
+			return false
+		}
+	}
+	prefix = prefix[:len(prefix)-1]
+
+	return last.walk(prefix, reverse, visit)
+}
+
+// height returns the number of nodes on the longest path from this node
+// down to the farthest leaf.
+func (t *ternaryNode) height() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + max(t.lo.height(), t.eq.height(), t.hi.height())
+}
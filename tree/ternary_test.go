@@ -0,0 +1,223 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestTernaryInsertSearch(t *testing.T) {
+	// Ternary trees can be used anywhere a Tree of strings is.
+	var tree Tree[string] = NewTernary()
+
+	// Words sharing prefixes, including words that are prefixes of others.
+	words := []string{"cat", "cats", "car", "cart", "care", "dog", "do", "a", "catalog"}
+	for _, w := range words {
+		if !tree.Insert(w) {
+			t.Errorf("Insert(%q) = false, want true", w)
+		}
+	}
+	if tree.Insert("car") {
+		t.Errorf("Insert(\"car\") again = true, want false")
+	}
+	if tree.Insert("") {
+		t.Errorf("Insert(\"\") = true, want false")
+	}
+	if got, want := tree.Size(), len(words); got != want {
+		t.Errorf("Size() = %d, want %d", got, want)
+	}
+
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{word: "cat", want: true},
+		{word: "cats", want: true},
+		{word: "catalog", want: true},
+		{word: "do", want: true},
+		{word: "a", want: true},
+		// Partial prefixes of words are not words themselves.
+		{word: "ca", want: false},
+		{word: "cata", want: false},
+		{word: "d", want: false},
+		// Absent words.
+		{word: "cow", want: false},
+		{word: "dogs", want: false},
+		{word: "b", want: false},
+		{word: "", want: false},
+	}
+	for _, test := range tests {
+		if got := tree.Search(test.word); got != test.want {
+			t.Errorf("Search(%q) = %v, want %v", test.word, got, test.want)
+		}
+	}
+
+	// Words come out sorted, with each word ahead of the longer words it is
+	// a prefix of.
+	var got []string
+	for w := range tree.Traverse(TraverseInOrder) {
+		got = append(got, w)
+	}
+	want := []string{"a", "car", "care", "cart", "cat", "catalog", "cats", "do", "dog"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Traverse(%v) differs (-want +got):\n%s", TraverseInOrder, diff)
+	}
+
+	got = nil
+	for w := range tree.Traverse(TraverseReverseOrder) {
+		got = append(got, w)
+	}
+	want = []string{"dog", "do", "cats", "catalog", "cat", "cart", "care", "car", "a"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Traverse(%v) differs (-want +got):\n%s", TraverseReverseOrder, diff)
+	}
+}
+
+func TestTernaryPrefix(t *testing.T) {
+	tree := NewTernary()
+	tree.InsertAll("cat", "cats", "car", "cart", "dog")
+
+	tests := []struct {
+		prefix string
+		has    bool
+		want   []string
+	}{
+		{
+			prefix: "",
+			has:    true,
+			want:   []string{"car", "cart", "cat", "cats", "dog"},
+		},
+		{
+			prefix: "ca",
+			has:    true,
+			want:   []string{"car", "cart", "cat", "cats"},
+		},
+		{
+			// Complete words are prefixes of themselves.
+			prefix: "cat",
+			has:    true,
+			want:   []string{"cat", "cats"},
+		},
+		{
+			prefix: "dog",
+			has:    true,
+			want:   []string{"dog"},
+		},
+		{
+			prefix: "cow",
+			has:    false,
+		},
+		{
+			prefix: "dogs",
+			has:    false,
+		},
+	}
+	for _, test := range tests {
+		if got := tree.HasPrefix(test.prefix); got != test.has {
+			t.Errorf("HasPrefix(%q) = %v, want %v", test.prefix, got, test.has)
+		}
+		if diff := cmp.Diff(test.want, tree.PrefixSearch(test.prefix)); diff != "" {
+			t.Errorf("PrefixSearch(%q) differs (-want +got):\n%s", test.prefix, diff)
+		}
+	}
+
+	if NewTernary().HasPrefix("") {
+		t.Errorf("HasPrefix(\"\") on an empty tree = true, want false")
+	}
+}
+
+func TestTernaryPrefixSearch(t *testing.T) {
+	tree := NewTernary()
+	tree.InsertAll(
+		"tea", "team", "teams", "tear", "tease", "ten", "tend", "tender",
+		"to", "toast", "today", "toe", "tone", "top", "topic",
+		"tree", "trek", "trend",
+		"apple", "apply", "ape",
+	)
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{
+			prefix: "te",
+			want:   []string{"tea", "team", "teams", "tear", "tease", "ten", "tend", "tender"},
+		},
+		{
+			prefix: "tea",
+			want:   []string{"tea", "team", "teams", "tear", "tease"},
+		},
+		{
+			prefix: "tend",
+			want:   []string{"tend", "tender"},
+		},
+		{
+			prefix: "to",
+			want:   []string{"to", "toast", "today", "toe", "tone", "top", "topic"},
+		},
+		{
+			prefix: "tr",
+			want:   []string{"tree", "trek", "trend"},
+		},
+		{
+			prefix: "ap",
+			want:   []string{"ape", "apple", "apply"},
+		},
+		{
+			prefix: "apple",
+			want:   []string{"apple"},
+		},
+		{
+			prefix: "topics",
+		},
+		{
+			prefix: "tx",
+		},
+		{
+			prefix: "b",
+		},
+	}
+	for _, test := range tests {
+		got := tree.PrefixSearch(test.prefix)
+		if diff := cmp.Diff(test.want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("PrefixSearch(%q) differs (-want +got):\n%s", test.prefix, diff)
+		}
+	}
+
+	// The empty prefix completes to everything.
+	if got := tree.PrefixSearch(""); len(got) != tree.Size() {
+		t.Errorf("PrefixSearch(\"\") returned %d words, want all %d", len(got), tree.Size())
+	}
+	if got := NewTernary().PrefixSearch(""); len(got) != 0 {
+		t.Errorf("PrefixSearch(\"\") on an empty tree = %q, want none", got)
+	}
+}
+
+func TestTernaryDelete(t *testing.T) {
+	tree := NewTernary()
+	tree.InsertAll("cat", "cats", "car")
+
+	if tree.Delete("ca") {
+		t.Errorf("Delete(\"ca\") of a partial prefix = true, want false")
+	}
+	if !tree.Delete("cat") {
+		t.Errorf("Delete(\"cat\") = false, want true")
+	}
+	if tree.Search("cat") || !tree.Search("cats") {
+		t.Errorf("after Delete(\"cat\"), Search(\"cat\"), Search(\"cats\") = %v, %v, want false, true",
+			tree.Search("cat"), tree.Search("cats"))
+	}
+
+	// Removing the last word through a branch drops its nodes.
+	if !tree.Delete("cats") {
+		t.Errorf("Delete(\"cats\") = false, want true")
+	}
+	if tree.HasPrefix("cat") {
+		t.Errorf("HasPrefix(\"cat\") after deleting every word starting with it = true, want false")
+	}
+
+	if !tree.Delete("car") || tree.Size() != 0 || tree.Height() != 0 {
+		t.Errorf("after deleting every word, Size(), Height() = %d, %d, want 0, 0", tree.Size(), tree.Height())
+	}
+}
@@ -0,0 +1,382 @@
+package tree
+
+import (
+	"math/rand"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Treap is a binary search tree that keeps itself balanced by giving every
+// value a random priority and keeping the nodes in heap order by priority,
+// with each node having a higher priority than any node below it. The shape
+// is that of a binary search tree built by inserting the values in a random
+// order, giving expected O(log n) operations without any explicit balancing.
+//
+// A Treap keeping duplicates holds every copy of a value in a single node
+// along with a count of the copies, so the shape depends only on the distinct
+// values.
+type Treap[T any] struct {
+	root *treapNode[T]
+
+	// size is the count of values in the tree, kept up to date by the
+	// Insert and Delete methods.
+	size int
+
+	// order defines how the values in the tree are arranged.
+	order ordering[T]
+
+	// rng is the source of the priorities. If nil, the shared default
+	// source in math/rand is used.
+	rng *rand.Rand
+}
+
+// NewTreap returns an empty Treap ready to use.
+//
+// Options can include Descending to order the values largest first,
+// IgnoreDuplicates(false) to keep every copy of equal values inserted, and
+// WithRandSource to draw the priorities from a given source.
+func NewTreap[T constraints.Ordered](opts ...treeOptionFunc) Tree[T] {
+	treeOpts := defaultOptions()
+	for _, opt := range opts {
+		opt(treeOpts)
+	}
+
+	t := &Treap[T]{order: newOrdering[T](treeOpts)}
+	if treeOpts.randSource != nil {
+		t.rng = rand.New(treeOpts.randSource)
+	}
+	return t
+}
+
+// NewTreapFunc returns an empty Treap ready to use, with its values ordered
+// by the given comparison function instead of their natural order. See
+// NewBSTFunc for the requirements on cmp.
+//
+// As each copy of a value is counted in the node holding the first, values
+// that cmp finds equal are all given back as the one inserted first.
+func NewTreapFunc[T any](cmp func(a, b T) int) Tree[T] {
+	return &Treap[T]{order: ordering[T]{cmp: cmp}}
+}
+
+// newTreapFromSorted returns a Treap with the given ordering holding the
+// given values, which must be in sorted order, with the nodes arranged into a
+// balanced shape. The priorities are drawn from a new source seeded from that
+// of like, so that a Treap made WithRandSource gives the same new tree each
+// time, or from the shared default source if like has none.
+//
+// Runs of equal values, which o must keep duplicates to allow, are counted
+// in a single node.
+func newTreapFromSorted[T, U any](like *Treap[T], vals []U, o ordering[U]) *Treap[U] {
+	t := &Treap[U]{size: len(vals), order: o}
+	if like.rng != nil {
+		t.rng = rand.New(rand.NewSource(like.rng.Int63()))
+	}
+
+	var dups []int
+	if o.duplicates {
+		cmp := o.resolve().cmp
+		distinct := make([]U, 0, len(vals))
+		for _, v := range vals {
+			if len(distinct) > 0 && cmp(distinct[len(distinct)-1], v) == 0 {
+				dups[len(dups)-1]++
+				continue
+			}
+			distinct = append(distinct, v)
+			dups = append(dups, 0)
+		}
+		vals = distinct
+	}
+	t.root = treapNodeFromSorted(vals, dups, t.priority)
+	return t
+}
+
+// Root returns the root node of the tree.
+func (t *Treap[T]) Root() BinaryTree[T] {
+	return t.root
+}
+
+// priority returns a new random priority.
+func (t *Treap[T]) priority() int64 {
+	if t.rng == nil {
+		return rand.Int63()
+	}
+	return t.rng.Int63()
+}
+
+// Insert inserts the value into the tree, rotating it up past any nodes with
+// a lower priority than the one it is given. If the value is already in the
+// tree, false is returned.
+func (t *Treap[T]) Insert(v T) bool {
+	return t.InsertErr(v) == nil
+}
+
+// InsertErr inserts the value into the tree like Insert. If the value is
+// already in the tree, and the tree does not keep duplicates, ErrDuplicate is
+// returned.
+func (t *Treap[T]) InsertErr(v T) error {
+	root, ok := t.root.insert(v, t.priority(), t.order.resolve())
+	t.root = root
+	if !ok {
+		return ErrDuplicate
+	}
+	t.size++
+	return nil
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+func (t *Treap[T]) InsertAll(vals ...T) int {
+	var n int
+	for _, v := range vals {
+		if t.Insert(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// Delete the requested node from the tree and reports if it was successful.
+// If the value is not in the tree, the tree is unchanged and false is returned.
+//
+// The node is rotated down until it is a leaf and then removed.
+func (t *Treap[T]) Delete(v T) bool {
+	return t.DeleteErr(v) == nil
+}
+
+// DeleteErr removes the requested node from the tree like Delete. If the tree
+// is empty ErrEmptyTree is returned, and if the value is not in the tree
+// ErrNotFound is returned, leaving the tree unchanged.
+func (t *Treap[T]) DeleteErr(v T) error {
+	if t.root == nil {
+		return ErrEmptyTree
+	}
+	root, n := t.root.delete(v, t.order.resolve(), false)
+	t.root = root
+	if n == 0 {
+		return ErrNotFound
+	}
+	t.size -= n
+	return nil
+}
+
+// DeleteOne removes a single copy of v from the tree, leaving any other copies
+// in place, and reports if one was removed. It is the same as Delete, named
+// for clarity alongside DeleteAll in trees that keep duplicates.
+//
+// While other copies remain, only the count in the node holding v is lowered.
+func (t *Treap[T]) DeleteOne(v T) bool {
+	return t.Delete(v)
+}
+
+// DeleteAll removes every copy of v from the tree, returning the number of
+// copies removed. Use Count to find how many there are without removing them.
+//
+// The copies are all held in one node, which is removed in a single
+// O(log n) step however many copies there are.
+func (t *Treap[T]) DeleteAll(v T) int {
+	root, n := t.root.delete(v, t.order.resolve(), true)
+	t.root = root
+	t.size -= n
+	return n
+}
+
+// Count returns the number of copies of v in the tree. Unless the tree was
+// created to keep duplicates, this is at most one.
+func (t *Treap[T]) Count(v T) int {
+	if t.root == nil {
+		return 0
+	}
+	return countValue[T](t.root, v, t.order.resolve())
+}
+
+// CountFunc returns the number of values in the tree for which pred returns
+// true. Every value is checked, but none are collected along the way.
+func (t *Treap[T]) CountFunc(pred func(T) bool) int {
+	if t.root == nil {
+		return 0
+	}
+	return countMatching[T](t.root, pred)
+}
+
+// DeleteFunc removes every value in the tree for which pred returns true,
+// returning the number of values removed. The matching values are
+// collected first and then each is deleted in turn, keeping the priorities of
+// the nodes that remain.
+func (t *Treap[T]) DeleteFunc(pred func(T) bool) int {
+	if t.root == nil {
+		return 0
+	}
+	var doomed []T
+	walkBinaryTree[T](t.root, TraverseInOrder, func(v T) bool {
+		if pred(v) {
+			doomed = append(doomed, v)
+		}
+		return true
+	})
+	for _, v := range doomed {
+		t.Delete(v)
+	}
+	return len(doomed)
+}
+
+// Search reports if the given value is in the tree.
+func (t *Treap[T]) Search(v T) bool {
+	return t.root.search(v, t.order.resolve())
+}
+
+// Traverse traverses the tree in the specified order emitting the values to
+// the channel. Channel is closed once the final value is emitted.
+func (t *Treap[T]) Traverse(tOrder TraverseOrder) <-chan T {
+	return t.root.Traverse(tOrder)
+}
+
+// TraverseBuffered is like Traverse, but the channel holds up to bufSize values,
+// letting the traversal run ahead of the reader instead of handing over each
+// value in turn. This gives better throughput when the reader does little
+// work per value. A bufSize below 1 gives an unbuffered channel, as Traverse.
+func (t *Treap[T]) TraverseBuffered(tOrder TraverseOrder, bufSize int) <-chan T {
+	ch := make(chan T, max(bufSize, 0))
+	go func() {
+		if t.root != nil {
+			traverseBinaryTree[T](t.root, tOrder, ch)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Walk calls visit with each value of the tree in the given order, stopping
+// as soon as visit returns false. It reports if every value was visited.
+func (t *Treap[T]) Walk(tOrder TraverseOrder, visit func(T) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	return walkBinaryTree[T](t.root, tOrder, visit)
+}
+
+// AppendValues appends the values of the tree in the given order onto dst and
+// returns the extended slice, in the manner of append. Reusing dst across
+// calls saves allocating a new slice each time as ToSlice does.
+func (t *Treap[T]) AppendValues(dst []T, tOrder TraverseOrder) []T {
+	if t.root == nil {
+		return dst
+	}
+	return appendValues[T](dst, t.root, tOrder)
+}
+
+// TraverseSlice returns the values of the tree in the given order as a slice.
+// Unlike Traverse, no goroutine or channel is used, which for small trees
+// costs more than the walk itself, and nothing is left running if the caller
+// stops reading early. Use Traverse to stream the values of large trees.
+func (t *Treap[T]) TraverseSlice(tOrder TraverseOrder) []T {
+	if t.root == nil {
+		return nil
+	}
+	return appendValues[T](make([]T, 0, t.size), t.root, tOrder)
+}
+
+// At returns the value at the given 0-based position in order, i.e., At(0)
+// is the smallest value, or the largest if the tree is Descending. The values
+// are counted off from the start, stopping once the index is reached, so this
+// takes O(index) time. If index is out of range, false is returned.
+func (t *Treap[T]) At(index int) (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return nthValue[T](t.root, TraverseInOrder, index)
+}
+
+// SelectDescending returns the k-th largest value (0-indexed), or the k-th
+// smallest if the tree is Descending. The values are counted off in reverse
+// order, taking O(k) time. If k is out of range, false is returned.
+func (t *Treap[T]) SelectDescending(k int) (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return nthValue[T](t.root, TraverseReverseOrder, k)
+}
+
+// MorrisInOrder returns the values of the tree in order, found with a Morris
+// traversal that needs no stack, recursion or goroutine, only O(1) space
+// beyond the returned slice. The tree is temporarily rethreaded along the way,
+// but left exactly as it was, so it must not be read by anything else while
+// this runs.
+func (t *Treap[T]) MorrisInOrder() []T {
+	if t.root == nil {
+		return nil
+	}
+	return morrisInOrder(t.root, (*treapNode[T]).childFields, (*treapNode[T]).Value, make([]T, 0, t.size))
+}
+
+// LeafCount returns the number of nodes in the tree with no children.
+func (t *Treap[T]) LeafCount() int {
+	if t.root == nil {
+		return 0
+	}
+	leaves, _ := countNodeKinds[T](t.root)
+	return leaves
+}
+
+// InternalCount returns the number of nodes in the tree with at least one
+// child, which is every node that is not a leaf.
+func (t *Treap[T]) InternalCount() int {
+	if t.root == nil {
+		return 0
+	}
+	_, internal := countNodeKinds[T](t.root)
+	return internal
+}
+
+// Boundary returns the values around the edge of the tree, going
+// anti-clockwise from the root: the root, the left boundary from the top
+// down, every leaf from left to right, and then the right boundary from the
+// bottom up. Values where the boundaries meet are only included once.
+func (t *Treap[T]) Boundary() []T {
+	if t.root == nil {
+		return nil
+	}
+	return boundaryValues[T](t.root)
+}
+
+// Height returns the height of the longest path in the tree from the
+// root node to the farthest leaf.
+func (t *Treap[T]) Height() int {
+	return t.root.Height()
+}
+
+// MinDepth returns the number of edges from the root to the nearest leaf,
+// found with a breadth first search that stops at the first leaf reached. Set
+// against Height, it shows how lopsided the tree is. An empty tree, like one
+// with only a root, returns 0.
+func (t *Treap[T]) MinDepth() int {
+	if t.root == nil {
+		return 0
+	}
+	return minLeafDepth[T](t.root)
+}
+
+// BalanceInfo returns the Height, MinDepth and Size of the tree together with
+// its SkewRatio, to show at a glance how far from balanced the tree is.
+func (t *Treap[T]) BalanceInfo() BalanceInfo {
+	return newBalanceInfo(t.Height(), t.MinDepth(), t.size)
+}
+
+// Size returns the number of values held in the tree.
+func (t *Treap[T]) Size() int {
+	return t.size
+}
+
+// IsEmpty reports if the tree holds no values.
+func (t *Treap[T]) IsEmpty() bool {
+	return t.root == nil
+}
+
+// String returns the values of the tree indented one level per depth, with
+// the root first and each child after its parent, for use when printing the
+// tree. Large trees are cut short after the first few dozen nodes.
+func (t *Treap[T]) String() string {
+	return treeString[T](t.root, t.size)
+}
@@ -0,0 +1,330 @@
+package tree
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// treapNode is the node in a Treap. Along with the value, each node holds a
+// random priority, with every node having a higher priority than any node
+// below it.
+type treapNode[T any] struct {
+	value    T
+	priority int64
+
+	// dups is the number of copies of value held beyond the first, for a
+	// Treap keeping duplicates.
+	dups int
+
+	left  *treapNode[T]
+	right *treapNode[T]
+}
+
+// treapNodeFromSorted builds a balanced subtree from the given sorted values,
+// giving the nodes new priorities drawn from priority. If dups is not nil,
+// it holds the number of extra copies of each value. The priorities are
+// handed out largest first in level order, so every node still has a higher
+// priority than any node below it.
+func treapNodeFromSorted[T any](vals []T, dups []int, priority func() int64) *treapNode[T] {
+	nodes := make([]treapNode[T], len(vals))
+	var build func(lo, hi int) *treapNode[T]
+	build = func(lo, hi int) *treapNode[T] {
+		if lo == hi {
+			return nil
+		}
+		mid := lo + (hi-lo)/2
+		n := &nodes[mid]
+		n.value = vals[mid]
+		if dups != nil {
+			n.dups = dups[mid]
+		}
+		n.left = build(lo, mid)
+		n.right = build(mid+1, hi)
+		return n
+	}
+	root := build(0, len(vals))
+
+	prios := make([]int64, len(vals))
+	for i := range prios {
+		prios[i] = priority()
+	}
+	slices.Sort(prios)
+	level := []*treapNode[T]{root}
+	for i := 0; root != nil && i < len(level); i++ {
+		n := level[i]
+		n.priority = prios[len(prios)-1-i]
+		if n.left != nil {
+			level = append(level, n.left)
+		}
+		if n.right != nil {
+			level = append(level, n.right)
+		}
+	}
+	return root
+}
+
+// HasLeft reports if this node has a Left child.
+func (t *treapNode[T]) HasLeft() bool {
+	return t.left != nil
+}
+
+// HasRight reports if this node has a Right child.
+func (t *treapNode[T]) HasRight() bool {
+	return t.right != nil
+}
+
+// Left returns this nodes Left child.
+func (t *treapNode[T]) Left() BinaryTree[T] {
+	return t.left
+}
+
+// Right returns this nodes Right child.
+func (t *treapNode[T]) Right() BinaryTree[T] {
+	return t.right
+}
+
+// Parent always returns nil, as these nodes do not keep track of their
+// parent.
+func (t *treapNode[T]) Parent() BinaryTree[T] {
+	return nil
+}
+
+// childFields returns the addresses of this nodes child pointers, so that
+// generic helpers such as flattenNodes can rearrange them.
+func (t *treapNode[T]) childFields() (left, right **treapNode[T]) {
+	return &t.left, &t.right
+}
+
+// Value returns this nodes Value.
+func (t *treapNode[T]) Value() T {
+	return t.value
+}
+
+// copies returns the number of copies of the value held by this node.
+func (t *treapNode[T]) copies() int {
+	return 1 + t.dups
+}
+
+// Metadata returns a string of metadata about this node. For a Treap, this
+// is the priority of the node, followed by the number of copies of its value
+// if there is more than one.
+func (t *treapNode[T]) Metadata() string {
+	if t.dups > 0 {
+		return fmt.Sprintf("P:%d x%d", t.priority, t.copies())
+	}
+	return fmt.Sprintf("P:%d", t.priority)
+}
+
+// Insert inserts the value below this node with a random priority and
+// reports if the operation was successful.
+//
+// This node is held by the caller, so it is never rotated out of its place,
+// even if the new value draws a higher priority. Callers should prefer the
+// Insert of the Treap which keeps track of the root.
+func (t *treapNode[T]) Insert(v T) bool {
+	o := ordering[T]{}.resolve()
+	var ok bool
+	switch c := o.compare(v, t.value); {
+	case c < 0:
+		t.left, ok = t.left.insert(v, rand.Int63(), o)
+	case c > 0:
+		t.right, ok = t.right.insert(v, rand.Int63(), o)
+	}
+	return ok
+}
+
+// insert is the worker for Insert, placing v with the given priority
+// according to the given ordering of the tree. It returns the new root of
+// this subtree, which changes if v rotates up past this node, and reports if
+// v was added.
+//
+// A copy of a value already in the tree is counted in the node holding it,
+// which keeps the value first inserted.
+func (t *treapNode[T]) insert(v T, priority int64, o ordering[T]) (*treapNode[T], bool) {
+	if t == nil {
+		return &treapNode[T]{value: v, priority: priority}, true
+	}
+
+	var ok bool
+	switch c := o.compare(v, t.value); {
+	case c == 0:
+		if !o.duplicates {
+			return t, false
+		}
+		t.dups++
+		return t, true
+	case c < 0:
+		t.left, ok = t.left.insert(v, priority, o)
+		if t.left.priority > t.priority {
+			t = t.rotateRight()
+		}
+	default:
+		t.right, ok = t.right.insert(v, priority, o)
+		if t.right.priority > t.priority {
+			t = t.rotateLeft()
+		}
+	}
+	return t, ok
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+func (t *treapNode[T]) InsertAll(vals ...T) int {
+	var n int
+	for _, v := range vals {
+		if t.Insert(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// Delete removes the value from below this node and reports if it was
+// successful. If the value is not in the tree, the tree is unchanged and
+// false is returned.
+//
+// This node is held by the caller, so it can not remove its own value.
+// Callers should prefer the Delete of the Treap which keeps track of the
+// root.
+func (t *treapNode[T]) Delete(v T) bool {
+	o := ordering[T]{}.resolve()
+	var n int
+	switch c := o.compare(v, t.value); {
+	case c < 0:
+		t.left, n = t.left.delete(v, o, false)
+	case c > 0:
+		t.right, n = t.right.delete(v, o, false)
+	}
+	return n > 0
+}
+
+// delete is the worker for Delete, removing v according to the given
+// ordering of the tree. If the node holding v has more than one copy and all
+// is not set, only its count is lowered. Otherwise the node is rotated down,
+// past whichever of its children has the higher priority, until it is a leaf
+// and can be dropped along with every copy. It returns the new root of this
+// subtree and the number of copies of v removed.
+func (t *treapNode[T]) delete(v T, o ordering[T], all bool) (*treapNode[T], int) {
+	if t == nil {
+		return nil, 0
+	}
+
+	var n int
+	switch c := o.compare(v, t.value); {
+	case c < 0:
+		t.left, n = t.left.delete(v, o, all)
+		return t, n
+	case c > 0:
+		t.right, n = t.right.delete(v, o, all)
+		return t, n
+	}
+
+	if t.dups > 0 && !all {
+		t.dups--
+		return t, 1
+	}
+
+	n = t.copies()
+	switch {
+	case t.left == nil:
+		return t.right, n
+	case t.right == nil:
+		return t.left, n
+	case t.left.priority > t.right.priority:
+		t = t.rotateRight()
+		t.right, _ = t.right.delete(v, o, true)
+	default:
+		t = t.rotateLeft()
+		t.left, _ = t.left.delete(v, o, true)
+	}
+	return t, n
+}
+
+// rotateLeft rotates the right child of this node up into its place,
+// returning the new root of the subtree.
+//
+//	  [t]             [r]
+//	  / \             / \
+//	 a  [r]    =>   [t]  c
+//	    / \         / \
+//	   b   c       a   b
+func (t *treapNode[T]) rotateLeft() *treapNode[T] {
+	r := t.right
+	t.right = r.left
+	r.left = t
+	return r
+}
+
+// rotateRight rotates the left child of this node up into its place,
+// returning the new root of the subtree.
+//
+//	    [t]         [l]
+//	    / \         / \
+//	  [l]  c   =>  a  [t]
+//	  / \             / \
+//	 a   b           b   c
+func (t *treapNode[T]) rotateRight() *treapNode[T] {
+	l := t.left
+	t.left = l.right
+	l.right = t
+	return l
+}
+
+// Search reports if the given value is in the tree.
+func (t *treapNode[T]) Search(v T) bool {
+	return t.search(v, ordering[T]{}.resolve())
+}
+
+// search is the worker for Search, following the given ordering of the tree.
+func (t *treapNode[T]) search(v T, o ordering[T]) bool {
+	for n := t; n != nil; {
+		c := o.compare(v, n.value)
+		if c == 0 {
+			return true
+		}
+		if c < 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return false
+}
+
+// Traverse traverse the tree in the specified order emitting the values to
+// the channel. Channel is closed once the final value is emitted.
+func (t *treapNode[T]) Traverse(tOrder TraverseOrder) <-chan T {
+	ch := make(chan T)
+	go func() {
+		if !t.IsEmpty() {
+			traverseBinaryTree(t, tOrder, ch)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Height returns the height of the longest path in the tree from the
+// root node to the farthest leaf.
+func (t *treapNode[T]) Height() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + max(t.left.Height(), t.right.Height())
+}
+
+// Size returns the number of values in the tree rooted at this node.
+func (t *treapNode[T]) Size() int {
+	if t == nil {
+		return 0
+	}
+	return t.copies() + t.left.Size() + t.right.Size()
+}
+
+// IsEmpty reports if the tree rooted at this node holds no values, which is
+// only the case for a nil node.
+func (t *treapNode[T]) IsEmpty() bool {
+	return t == nil
+}
@@ -0,0 +1,230 @@
+package tree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// countNodes returns the number of nodes in the subtree, which is less than
+// its Size when some hold more than one copy of their value.
+func countNodes(n *treapNode[int]) int {
+	if n == nil {
+		return 0
+	}
+	return 1 + countNodes(n.left) + countNodes(n.right)
+}
+
+// checkTreap reports if every node in the subtree has a higher priority than
+// its children.
+func checkTreap(n *treapNode[int]) bool {
+	if n == nil {
+		return true
+	}
+	if (n.left != nil && n.left.priority > n.priority) ||
+		(n.right != nil && n.right.priority > n.priority) {
+		return false
+	}
+	return checkTreap(n.left) && checkTreap(n.right)
+}
+
+func TestTreapFixedSeed(t *testing.T) {
+	tree := NewTreap[int](WithRandSource(rand.NewSource(1))).(*Treap[int])
+	if got := tree.InsertAll(4, 2, 6, 1, 3, 5, 7); got != 7 {
+		t.Fatalf("InsertAll() = %d, want 7", got)
+	}
+
+	// With this seed the tree always takes the same shape:
+	//
+	//	  2
+	//	 / \
+	//	1   5
+	//	   / \
+	//	  4   6
+	//	 /     \
+	//	3       7
+	var got []int
+	tree.Walk(TraversePreOrder, func(v int) bool {
+		got = append(got, v)
+		return true
+	})
+	if diff := cmp.Diff([]int{2, 1, 5, 4, 3, 6, 7}, got); diff != "" {
+		t.Errorf("pre order values differ (-want +got):\n%s", diff)
+	}
+	if !checkTreap(tree.root) || !IsValidBST(tree.Root()) {
+		t.Errorf("tree does not hold the Treap invariants")
+	}
+}
+
+func TestTreapInsertDeleteSearch(t *testing.T) {
+	tree := NewTreap[int](WithRandSource(rand.NewSource(7))).(*Treap[int])
+	r := rand.New(rand.NewSource(42))
+
+	present := map[int]bool{}
+	for i := 0; i < 2000; i++ {
+		v := r.Intn(500)
+		if i%3 == 2 {
+			if got := tree.Delete(v); got != present[v] {
+				t.Fatalf("Delete(%d) = %v, want %v", v, got, present[v])
+			}
+			delete(present, v)
+		} else {
+			if got := tree.Insert(v); got == present[v] {
+				t.Fatalf("Insert(%d) = %v, want %v", v, got, !present[v])
+			}
+			present[v] = true
+		}
+	}
+
+	if !checkTreap(tree.root) || !IsValidBST(tree.Root()) {
+		t.Errorf("tree does not hold the Treap invariants")
+	}
+	if got, want := tree.Size(), len(present); got != want {
+		t.Errorf("Size() = %d, want %d", got, want)
+	}
+	for v := 0; v < 500; v++ {
+		if got := tree.Search(v); got != present[v] {
+			t.Errorf("Search(%d) = %v, want %v", v, got, present[v])
+		}
+	}
+
+	var got []int
+	for v := range tree.Traverse(TraverseInOrder) {
+		got = append(got, v)
+	}
+	if len(got) != len(present) || !sort.IntsAreSorted(got) {
+		t.Errorf("Traverse() emitted %d values, sorted %v, want %d sorted values",
+			len(got), sort.IntsAreSorted(got), len(present))
+	}
+}
+
+func TestTreapHeight(t *testing.T) {
+	const n = 1000
+
+	// Sorted inserts would leave a plain BST n tall, but the random
+	// priorities keep the tree near its expected height of a few log n.
+	limit := 4 * int(math.Log2(n))
+	for seed := int64(1); seed <= 5; seed++ {
+		tree := NewTreap[int](WithRandSource(rand.NewSource(seed)))
+		for v := 0; v < n; v++ {
+			tree.Insert(v)
+		}
+		if h := tree.Height(); h > limit {
+			t.Errorf("seed %d: Height() = %d, want <= %d", seed, h, limit)
+		}
+	}
+
+	// The default source works too.
+	tree := &Treap[int]{}
+	for v := 0; v < n; v++ {
+		tree.Insert(v)
+	}
+	if h := tree.Height(); h > limit {
+		t.Errorf("Height() with the default source = %d, want <= %d", h, limit)
+	}
+}
+
+func TestTreapDeleteOneAll(t *testing.T) {
+	tree := NewTreap[int](IgnoreDuplicates(false), WithRandSource(rand.NewSource(7))).(*Treap[int])
+	tree.InsertAll(5, 3, 8, 5, 1, 5, 9)
+
+	if got := tree.Count(5); got != 3 {
+		t.Fatalf("Count(5) = %d, want 3", got)
+	}
+	// The copies are counted in a single node.
+	if got := countNodes(tree.root); got != 5 {
+		t.Errorf("node count = %d, want 5", got)
+	}
+
+	if !tree.DeleteOne(5) {
+		t.Errorf("DeleteOne(5) = false, want true")
+	}
+	if got := tree.Count(5); got != 2 {
+		t.Errorf("after DeleteOne(5), Count(5) = %d, want 2", got)
+	}
+	if got := tree.Size(); got != 6 {
+		t.Errorf("after DeleteOne(5), Size() = %d, want 6", got)
+	}
+	if got := countNodes(tree.root); got != 5 {
+		t.Errorf("after DeleteOne(5), node count = %d, want 5", got)
+	}
+
+	if got := tree.DeleteAll(5); got != 2 {
+		t.Errorf("DeleteAll(5) = %d, want 2", got)
+	}
+	if got := tree.Count(5); got != 0 {
+		t.Errorf("after DeleteAll(5), Count(5) = %d, want 0", got)
+	}
+	if tree.Search(5) {
+		t.Errorf("after DeleteAll(5), Search(5) = true, want false")
+	}
+	if got, want := ToSlice[int](tree), []int{1, 3, 8, 9}; !cmp.Equal(got, want) {
+		t.Errorf("after DeleteAll(5), values = %v, want %v", got, want)
+	}
+	if !checkTreap(tree.root) {
+		t.Errorf("after DeleteAll(5), tree is not in heap order")
+	}
+	if got := countNodes(tree.root); got != 4 {
+		t.Errorf("after DeleteAll(5), node count = %d, want 4", got)
+	}
+
+	// Missing values.
+	if tree.DeleteOne(5) {
+		t.Errorf("DeleteOne(5) of a missing value = true, want false")
+	}
+	if got := tree.DeleteAll(5); got != 0 {
+		t.Errorf("DeleteAll(5) of a missing value = %d, want 0", got)
+	}
+	if got := tree.Size(); got != 4 {
+		t.Errorf("Size() = %d, want 4", got)
+	}
+}
+
+func TestTreapCountedCopies(t *testing.T) {
+	tree := NewTreap[int](IgnoreDuplicates(false), WithRandSource(rand.NewSource(3))).(*Treap[int])
+	tree.InsertAll(4, 2, 4, 6, 2, 4)
+	want := []int{2, 2, 4, 4, 4, 6}
+
+	if got := tree.TraverseSlice(TraverseInOrder); !cmp.Equal(got, want) {
+		t.Errorf("TraverseSlice(InOrder) = %v, want %v", got, want)
+	}
+	if got := tree.MorrisInOrder(); !cmp.Equal(got, want) {
+		t.Errorf("MorrisInOrder() = %v, want %v", got, want)
+	}
+	for _, order := range []TraverseOrder{TraversePreOrder, TraversePostOrder, TraverseLevelOrder} {
+		got := tree.TraverseSlice(order)
+		sort.Ints(got)
+		if !cmp.Equal(got, want) {
+			t.Errorf("TraverseSlice(%v) sorted = %v, want %v", order, got, want)
+		}
+	}
+	if got, ok := tree.At(4); !ok || got != 4 {
+		t.Errorf("At(4) = %d, %v, want 4, true", got, ok)
+	}
+	if got := tree.CountFunc(func(v int) bool { return v < 5 }); got != 5 {
+		t.Errorf("CountFunc(< 5) = %d, want 5", got)
+	}
+	if got := ParallelReduce[int](tree, 0, func(v int) int { return v }, func(a, b int) int { return a + b }); got != 22 {
+		t.Errorf("ParallelReduce() sum = %d, want 22", got)
+	}
+
+	var mirrored []int
+	InOrderInto(Mirror[int](tree), &mirrored)
+	if want := []int{6, 4, 4, 4, 2, 2}; !cmp.Equal(mirrored, want) {
+		t.Errorf("Mirror() values = %v, want %v", mirrored, want)
+	}
+
+	filtered := Filter[int](tree, func(v int) bool { return v > 2 }).(*Treap[int])
+	if got, want := ToSlice[int](filtered), []int{4, 4, 4, 6}; !cmp.Equal(got, want) {
+		t.Errorf("Filter() values = %v, want %v", got, want)
+	}
+	if got := countNodes(filtered.root); got != 2 {
+		t.Errorf("Filter() node count = %d, want 2", got)
+	}
+	if got := filtered.DeleteAll(4); got != 3 || filtered.Size() != 1 {
+		t.Errorf("Filter() DeleteAll(4) = %d with Size() %d, want 3 with 1", got, filtered.Size())
+	}
+}
@@ -1,7 +1,21 @@
 package tree
 
 import (
-	"golang.org/x/exp/constraints"
+	"errors"
+	"math"
+)
+
+// Errors reported by the InsertErr and DeleteErr methods of the trees.
+var (
+	// ErrDuplicate is returned when inserting a value that is already in a
+	// tree which does not keep duplicates.
+	ErrDuplicate = errors.New("tree: value is already in the tree")
+
+	// ErrNotFound is returned when deleting a value that is not in the tree.
+	ErrNotFound = errors.New("tree: value is not in the tree")
+
+	// ErrEmptyTree is returned when deleting from a tree with no values.
+	ErrEmptyTree = errors.New("tree: tree is empty")
 )
 
 // TraverseOrder represents the common orders that tree nodes may be traversed in.
@@ -44,18 +58,23 @@ func (t TraverseOrder) String() string {
 }
 
 // Traverser is an interface for trees that implement a way to traverse themselves.
-type Traverser[T constraints.Ordered] interface {
+type Traverser[T any] interface {
 	// Traverse traverse the tree in the specified order emitting the values to
 	// the channel. Channel is closed once the final value is emitted.
 	Traverse(TraverseOrder) <-chan T
 }
 
 // Tree defines the basic interface common to all trees.
-type Tree[T constraints.Ordered] interface {
+type Tree[T any] interface {
 	// Insert adds the given value into the true.
 	// If the value could not be added, false is returned.
 	Insert(v T) bool
 
+	// InsertAll adds the given values into the tree, returning how many of
+	// them were added. Values already in the tree, or repeated in vals, are
+	// not counted.
+	InsertAll(vals ...T) int
+
 	// Delete the requested node from the tree and reports if it was successful.
 	// If the value is not in the tree, the tree is unchanged and false is returned.
 	//
@@ -69,5 +88,50 @@ type Tree[T constraints.Ordered] interface {
 	// root node to the farthest leaf.
 	Height() int
 
+	// Size returns the number of values held in the tree.
+	Size() int
+
+	// IsEmpty reports if the tree holds no values.
+	IsEmpty() bool
+
 	Traverser[T]
 }
+
+// RootedTree is a Tree built from BinaryTree nodes, giving access to its root
+// node so that functions working on the nodes can be used on the whole tree.
+type RootedTree[T any] interface {
+	Tree[T]
+
+	// Root returns the root node of the tree.
+	Root() BinaryTree[T]
+}
+
+// BalanceInfo holds diagnostic measures of how well balanced a tree is.
+type BalanceInfo struct {
+	// Height is the number of nodes on the longest path from the root to
+	// a leaf, as returned by Height.
+	Height int
+
+	// MinDepth is the number of edges from the root to the nearest leaf, as
+	// returned by MinDepth.
+	MinDepth int
+
+	// Size is the number of values in the tree.
+	Size int
+
+	// SkewRatio is Height / log2(Size+1), the height of the tree relative
+	// to that of a perfectly balanced tree of the same size. It is 1 for a
+	// perfectly balanced tree and grows towards Size / log2(Size+1) as the
+	// tree degenerates into a chain. An empty tree has a ratio of 0.
+	SkewRatio float64
+}
+
+// newBalanceInfo returns the BalanceInfo for a tree with the given measures,
+// working out the SkewRatio from them.
+func newBalanceInfo(height, minDepth, size int) BalanceInfo {
+	info := BalanceInfo{Height: height, MinDepth: minDepth, Size: size}
+	if size > 0 {
+		info.SkewRatio = float64(height) / math.Log2(float64(size+1))
+	}
+	return info
+}
@@ -1,6 +1,13 @@
 package tree
 
-import "golang.org/x/exp/constraints"
+import (
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
 
 // Options contains the various settings used in these tree functions.
 type Options struct {
@@ -10,6 +17,26 @@ type Options struct {
 	// FPTolerance is used to set floating point tolerance for equality
 	// comparisons.
 	fpTolerance float64
+
+	// descending indicates the tree orders its values from largest to
+	// smallest instead of the usual smallest to largest.
+	descending bool
+
+	// adaptiveSpacing indicates rendered trees should size each subtree by
+	// its breadth rather than reserving the full width of a complete tree.
+	adaptiveSpacing bool
+
+	// showSizes indicates rendered trees should note the number of nodes in
+	// the subtree under each node along with its other metadata.
+	showSizes bool
+
+	// randSource, if not nil, is the source of the random numbers used by
+	// randomized trees such as the Treap.
+	randSource rand.Source
+
+	// rebalanceFactor, if not zero, is how many times taller than balanced
+	// a BST may grow before it is rebuilt.
+	rebalanceFactor float64
 }
 
 func defaultOptions() *Options {
@@ -24,6 +51,9 @@ type treeOptionFunc func(c *Options)
 
 // IgnoreDuplicates tells the tree function that a duplicate value in an
 // operation should be ignored. (Such as when joining two Trees)
+//
+// Duplicates are ignored by default. Giving IgnoreDuplicates(false) to a new
+// tree makes it a multiset, keeping every copy of equal values inserted.
 func IgnoreDuplicates(ignore bool) treeOptionFunc {
 	return func(o *Options) {
 		o.ignoreDuplicates = ignore
@@ -38,21 +68,620 @@ func FloatingPointTolerance(tol float64) treeOptionFunc {
 	}
 }
 
+// Descending tells a new tree to order its values from largest to smallest,
+// so that in order traversals emit the values in descending order and Min
+// and Max return the first and last values in that order.
+func Descending() treeOptionFunc {
+	return func(o *Options) {
+		o.descending = true
+	}
+}
+
+// WithAdaptiveSpacing tells the tree renderer to give each subtree only as
+// much width as it needs for the nodes it has, instead of always reserving
+// the full 2^n width of a complete tree. Unbalanced and sparse trees render
+// far more compactly with this set.
+func WithAdaptiveSpacing(adaptive bool) treeOptionFunc {
+	return func(o *Options) {
+		o.adaptiveSpacing = adaptive
+	}
+}
+
+// ShowSizes tells the tree renderer to annotate each node with the number of
+// nodes in the subtree rooted at it, as n:<count> after any other metadata
+// the node has. This gives plain BSTs, which have no metadata of their own,
+// something useful to show.
+func ShowSizes(show bool) treeOptionFunc {
+	return func(o *Options) {
+		o.showSizes = show
+	}
+}
+
+// WithRandSource tells a new randomized tree, such as a Treap, to draw its
+// random numbers from the given source instead of the shared default source.
+// Giving a source with a fixed seed makes the shape of the tree repeatable.
+func WithRandSource(src rand.Source) treeOptionFunc {
+	return func(o *Options) {
+		o.randSource = src
+	}
+}
+
+// AutoRebalanceThreshold tells a new BST to rebuild itself in balanced shape
+// whenever an insert leaves it taller than factor times the height of a
+// balanced tree of the same size, roughly factor * log2(size). This bounds
+// the height of a BST fed sorted or nearly sorted values, at the cost of an
+// occasional O(n) rebuild, giving amortized balance without the per insert
+// work of an AVL tree.
+//
+// Factors below 1 can never be met and are treated as 1. A factor of 0 or
+// less, the default, never rebuilds.
+func AutoRebalanceThreshold(factor float64) treeOptionFunc {
+	return func(o *Options) {
+		switch {
+		case factor <= 0:
+			factor = 0
+		case factor < 1:
+			factor = 1
+		}
+		o.rebalanceFactor = factor
+	}
+}
+
 // Clone returns a complete new copy of the given tree.
 func Clone[T constraints.Ordered](t Tree[T]) Tree[T] {
 	return t
 }
 
+// Mirror returns a copy of the given tree with the left and right children of
+// every node swapped, so that the values appear in reverse order. The given
+// tree is left unchanged.
+//
+// A mirrored binary search tree is no longer a valid binary search tree, as
+// its ordering is reversed, but it is still a valid binary tree. It is
+// returned as a BinaryTree backed by plain nodes, regardless of the type of
+// the original tree, and any metadata such as balance factors is dropped. A
+// SyncTree is mirrored under its read lock.
+func Mirror[T any](t Tree[T]) BinaryTree[T] {
+	if st, ok := t.(*SyncTree[T]); ok {
+		var mirror BinaryTree[T]
+		st.read(func(inner Tree[T]) { mirror = Mirror(inner) })
+		return mirror
+	}
+
+	root := rootOf(t)
+	if isTreeNil(root) {
+		return (*bstNode[T])(nil)
+	}
+	return mirrorNode(root)
+}
+
+// rootOf returns the root node of the given tree, which is either a tree
+// type with a Root method or a node itself. Other trees, such as SyncTree and
+// Ternary, give nil.
+func rootOf[T any](t Tree[T]) BinaryTree[T] {
+	switch tt := t.(type) {
+	case RootedTree[T]:
+		return tt.Root()
+	case BinaryTree[T]:
+		return tt
+	}
+	return nil
+}
+
+// inOrderValues returns the values of the given tree in order, read straight
+// from the nodes where it has them, and otherwise through Traverse.
+func inOrderValues[T any](t Tree[T]) []T {
+	var vals []T
+	if root := rootOf(t); root != nil {
+		InOrderInto(root, &vals)
+		return vals
+	}
+	return ToSlice(t)
+}
+
+// mirrorNode is the recursive worker for Mirror.
+//
+// Copies of a value held within one node, as a Treap keeping duplicates
+// holds them, are given a node each, chained down the left of the first.
+func mirrorNode[T any](t BinaryTree[T]) *bstNode[T] {
+	node := &bstNode[T]{value: t.Value()}
+	if t.HasLeft() {
+		node.right = mirrorNode(t.Left())
+	}
+	last := node
+	for i := copiesOf(t); i > 1; i-- {
+		last.left = &bstNode[T]{value: node.value}
+		last = last.left
+	}
+	if t.HasRight() {
+		last.left = mirrorNode(t.Right())
+	}
+	return node
+}
+
+// Map returns a new tree of the same type as the given tree holding the result
+// of applying f to each of its values. Values that f maps to the same result
+// are only stored once.
+//
+// Since f need not preserve the order of the values, the results are sorted
+// and the new tree is built from them in balanced shape, rather than copying
+// the structure of the given tree. The new tree keeps the direction of the
+// given one, but is ordered by the natural order of U even if the given tree
+// was created with a comparison function.
+//
+// A SyncTree is read under its read lock, and the new tree is wrapped in a
+// SyncTree of its own.
+func Map[T, U constraints.Ordered](t Tree[T], f func(T) U) Tree[U] {
+	if st, ok := t.(*SyncTree[T]); ok {
+		var mapped Tree[U]
+		st.read(func(inner Tree[T]) { mapped = Map(inner, f) })
+		return NewSyncTree(mapped)
+	}
+
+	vals := inOrderValues(t)
+
+	mapped := make([]U, len(vals))
+	for i, v := range vals {
+		mapped[i] = f(v)
+	}
+
+	order := ordering[U]{descending: orderingOf(t).descending}
+	o := order.resolve()
+	slices.SortFunc(mapped, o.compare)
+	mapped = slices.CompactFunc(mapped, func(a, b U) bool {
+		return o.compare(a, b) == 0
+	})
+
+	return buildLike(t, mapped, order)
+}
+
+// Filter returns a new tree of the same type as the given tree holding only
+// the values for which pred returns true. The given tree is left unchanged.
+//
+// The kept values are already in order, so the new tree is built from them
+// directly in balanced shape, keeping the ordering of the given tree. As with
+// Map, a SyncTree is read under its read lock and gives a new SyncTree.
+func Filter[T any](t Tree[T], pred func(T) bool) Tree[T] {
+	if st, ok := t.(*SyncTree[T]); ok {
+		var filtered Tree[T]
+		st.read(func(inner Tree[T]) { filtered = Filter(inner, pred) })
+		return NewSyncTree(filtered)
+	}
+
+	vals := inOrderValues(t)
+
+	kept := vals[:0]
+	for _, v := range vals {
+		if pred(v) {
+			kept = append(kept, v)
+		}
+	}
+
+	return buildLike(t, kept, orderingOf(t))
+}
+
+// Trim returns a new tree holding only the values v of the given tree with
+// lo <= v <= hi, discarding everything outside the range. The given tree is
+// left unchanged.
+//
+// A BST is trimmed node by node, so the values that are kept keep their
+// relative structure. Other trees are rebuilt in balanced shape from the kept
+// values, as with Filter, which also describes how a SyncTree is trimmed.
+func Trim[T any](t Tree[T], lo, hi T) Tree[T] {
+	if st, ok := t.(*SyncTree[T]); ok {
+		var trimmed Tree[T]
+		st.read(func(inner Tree[T]) { trimmed = Trim(inner, lo, hi) })
+		return NewSyncTree(trimmed)
+	}
+
+	order := orderingOf(t)
+	o := order.resolve()
+	root := rootOf(t)
+	if isTreeNil(t) || t.IsEmpty() || o.cmp(hi, lo) < 0 {
+		return buildLike[T, T](t, nil, order)
+	}
+	if root == nil {
+		var vals []T
+		for _, v := range inOrderValues(t) {
+			if o.cmp(lo, v) <= 0 && o.cmp(v, hi) <= 0 {
+				vals = append(vals, v)
+			}
+		}
+		return buildLike(t, vals, order)
+	}
+
+	if bst, ok := t.(*BST[T]); ok {
+		trimmed := &BST[T]{order: bst.order, rebalanceFactor: bst.rebalanceFactor}
+		trimmed.root, trimmed.size = trimNode(root, lo, hi, o)
+		return trimmed
+	}
+
+	var vals []T
+	rangeAppend(root, lo, hi, o, nil, &vals)
+	return buildLike(t, vals, order)
+}
+
+// trimNode is the recursive worker for Trim, returning a copy of the given
+// tree with the values outside [lo, hi] removed, along with its size. A node
+// before the range is replaced by its trimmed subtree after it, and a node
+// past the range by its trimmed subtree before it.
+func trimNode[T any](t BinaryTree[T], lo, hi T, o ordering[T]) (*bstNode[T], int) {
+	// The bound reached first depends on which way the tree is ordered.
+	first, last := lo, hi
+	if o.descending {
+		first, last = hi, lo
+	}
+
+	var left, right *bstNode[T]
+	var ln, rn int
+	v := t.Value()
+	if t.HasLeft() && o.compare(first, v) <= 0 {
+		left, ln = trimNode(t.Left(), lo, hi, o)
+	}
+	if t.HasRight() && o.compare(v, last) <= 0 {
+		right, rn = trimNode(t.Right(), lo, hi, o)
+	}
+
+	switch {
+	case o.compare(v, first) < 0:
+		return right, rn
+	case o.compare(last, v) < 0:
+		return left, ln
+	}
+	return &bstNode[T]{value: v, left: left, right: right}, 1 + ln + rn
+}
+
+// orderingOf returns the ordering of the given tree, read under the read lock
+// for a SyncTree. Nodes on their own, and trees without an ordering of their
+// own such as Ternary, have the default ordering.
+func orderingOf[T any](t Tree[T]) ordering[T] {
+	switch tt := t.(type) {
+	case *SyncTree[T]:
+		var o ordering[T]
+		tt.read(func(inner Tree[T]) { o = orderingOf(inner) })
+		return o
+	case *COWTree[T]:
+		return tt.Snapshot().order
+	case *BST[T]:
+		return tt.order
+	case *AVL[T]:
+		return tt.order
+	case *RedBlack[T]:
+		return tt.order
+	case *Treap[T]:
+		return tt.order
+	case *WeightBalanced[T]:
+		return tt.order
+	}
+	return ordering[T]{}
+}
+
+// buildLike returns a tree of the same type as the given tree holding the
+// given values, which must be in order according to o without repeats. The
+// tree is built directly in balanced shape. Anything other than the AVL,
+// Red-Black, Treap and WeightBalanced trees, such as a node on its own, gets a BST, which keeps the
+// rebalance threshold of the given tree if that was a BST too.
+func buildLike[T, U any](t Tree[T], vals []U, o ordering[U]) Tree[U] {
+	o = o.resolve()
+	switch tt := t.(type) {
+	case *AVL[T]:
+		root, _ := avlNodeFromSorted(vals, nil)
+		return &AVL[U]{root: root, size: len(vals), order: o}
+	case *RedBlack[T]:
+		return newRedBlackFromSorted(vals, o)
+	case *Treap[T]:
+		return newTreapFromSorted(tt, vals, o)
+	case *WeightBalanced[T]:
+		return &WeightBalanced[U]{root: wbNodeFromSorted(vals), size: len(vals), order: o, delta: tt.delta}
+	}
+	bst := &BST[U]{root: bstNodeFromSorted(vals), size: len(vals), order: o}
+	if tt, ok := t.(*BST[T]); ok {
+		bst.rebalanceFactor = tt.rebalanceFactor
+	}
+	return bst
+}
+
 // Join combines the given trees using the options (if any).
 //
 // Options can include things like what strategy to use when encountering
 // duplicate values, hints or reqeuirements on type of output tree, etc.
-func Join[T constraints.Ordered](a, b Tree[T], opts ...treeOptionFunc) Tree[T] {
+//
+// The values of both trees are merged in order and the result is built as a
+// balanced BST in the direction of a. Each value is only kept once, however
+// many copies of it either tree holds, unless IgnoreDuplicates(false) is
+// given, in which case every copy is kept and the result keeps duplicates.
+// As with Union, the values are compared by the comparison function of a.
+func Join[T any](a, b Tree[T], opts ...treeOptionFunc) Tree[T] {
 	treeOpts := defaultOptions()
 	for _, opt := range opts {
 		opt(treeOpts)
 	}
-	return a
+
+	var vals []T
+	if treeOpts.ignoreDuplicates {
+		mergeWalk(a, b, func(v T, _, _ bool) bool {
+			vals = append(vals, v)
+			return true
+		})
+	} else {
+		o, ca, cb := mergeCursors(a, b)
+		av, aok := ca.next()
+		bv, bok := cb.next()
+		for aok || bok {
+			if !bok || (aok && o.cmp(av, bv) <= 0) {
+				vals = append(vals, av)
+				av, aok = ca.next()
+			} else {
+				vals = append(vals, bv)
+				bv, bok = cb.next()
+			}
+		}
+	}
+
+	o := orderingOf(a).resolve()
+	o.duplicates = !treeOpts.ignoreDuplicates
+	return newMergedBST(vals, o)
+}
+
+// newMergedBST returns a balanced BST with the ordering o holding the given
+// values, which are in ascending order by the comparison function of o.
+func newMergedBST[T any](vals []T, o ordering[T]) *BST[T] {
+	if o.descending {
+		slices.Reverse(vals)
+	}
+	return &BST[T]{root: bstNodeFromSorted(vals), size: len(vals), order: o}
+}
+
+// Union returns a new tree holding every value that is in either of the given
+// trees. Values in both are only stored once, as are values repeated within
+// a tree that keeps duplicates.
+//
+// The values of both trees are walked in order side by side and the result is
+// built directly as a balanced BST, taking O(m+n) time for trees of m and n
+// values. The values are compared by the comparison function of a, and the
+// result is ordered by it in the direction of a. The same goes for the other
+// set operations.
+//
+// The trees are usually ordered by the same function, but need not be. If
+// the values of b are out of order by the function of a, they are first
+// collected and sorted by it, taking O(n log n) time instead.
+func Union[T any](a, b Tree[T]) Tree[T] {
+	return setOperation(a, b, true, true, true)
+}
+
+// Intersection returns a new tree holding the values that are in both of the
+// given trees. Like Union, it takes O(m+n) time and the result is a balanced
+// BST with no repeated values.
+func Intersection[T any](a, b Tree[T]) Tree[T] {
+	return setOperation(a, b, false, true, false)
+}
+
+// Difference returns a new tree holding the values of a that are not in b.
+// Like Union, it takes O(m+n) time and the result is a balanced BST with no
+// repeated values.
+func Difference[T any](a, b Tree[T]) Tree[T] {
+	return setOperation(a, b, true, false, false)
+}
+
+// SymmetricDifference returns a new tree holding the values that are in
+// exactly one of the given trees. Like Union, it takes O(m+n) time and the
+// result is a balanced BST with no repeated values.
+func SymmetricDifference[T any](a, b Tree[T]) Tree[T] {
+	return setOperation(a, b, true, false, true)
+}
+
+// setOperation returns a balanced BST in the direction of a holding each
+// distinct value of the two trees once, keeping the values found only in a if onlyA is true, those
+// found in both if both is true, and those found only in b if onlyB is true.
+func setOperation[T any](a, b Tree[T], onlyA, both, onlyB bool) Tree[T] {
+	var vals []T
+	o := mergeWalk(a, b, func(v T, inA, inB bool) bool {
+		if (inA && inB && both) || (inA && !inB && onlyA) || (!inA && inB && onlyB) {
+			vals = append(vals, v)
+		}
+		return true
+	})
+	return newMergedBST(vals, ordering[T]{cmp: o.cmp, descending: o.descending})
+}
+
+// IsSubset reports if every value in a is also in b. Repeated values are
+// treated as one, so a tree that keeps duplicates is a subset of one holding
+// a single copy of each of its values. An empty tree is a subset of any tree.
+//
+// The values of both trees are walked in order side by side, stopping at the
+// first value of a missing from b, taking O(m+n) time rather than the
+// O(m log n) of searching b for each value of a. As with Union, the values
+// are compared by the comparison function of a.
+func IsSubset[T any](a, b Tree[T]) bool {
+	subset := true
+	mergeWalk(a, b, func(_ T, inA, inB bool) bool {
+		subset = !inA || inB
+		return subset
+	})
+	return subset
+}
+
+// IsSuperset reports if every value in b is also in a. See IsSubset.
+func IsSuperset[T any](a, b Tree[T]) bool {
+	return IsSubset(b, a)
+}
+
+// Diff compares two snapshots of a tree, returning the values added, which
+// are in b but not a, and the values removed, which are in a but not b. Both
+// are in ascending order, and values in both trees are in neither. Repeated
+// values are treated as one, as with the other set operations.
+//
+// The values of both trees are walked in order side by side once, taking
+// O(m+n) time for trees of m and n values. As with Union, the values are
+// compared by the comparison function of a.
+func Diff[T any](a, b Tree[T]) (added, removed []T) {
+	mergeWalk(a, b, func(v T, inA, inB bool) bool {
+		switch {
+		case inA && !inB:
+			removed = append(removed, v)
+		case inB && !inA:
+			added = append(added, v)
+		}
+		return true
+	})
+	return added, removed
+}
+
+// mergeWalk walks the values of the two trees in ascending order side by side,
+// calling visit once with each distinct value, and if it is in a, b or both,
+// until visit returns false. The values are compared by the comparison
+// function of a, and the resolved ordering of a is returned. See
+// mergeCursors.
+func mergeWalk[T any](a, b Tree[T], visit func(v T, inA, inB bool) bool) ordering[T] {
+	o, ca, cb := mergeCursors(a, b)
+	av, aok := ca.next()
+	bv, bok := cb.next()
+	for aok || bok {
+		var v T
+		var inA, inB bool
+		switch {
+		case !bok || (aok && o.cmp(av, bv) < 0):
+			v, inA = av, true
+		case !aok || o.cmp(bv, av) < 0:
+			v, inB = bv, true
+		default:
+			v, inA, inB = av, true, true
+		}
+
+		// Step past every copy of v in either tree.
+		for inA && aok && o.cmp(av, v) == 0 {
+			av, aok = ca.next()
+		}
+		for inB && bok && o.cmp(bv, v) == 0 {
+			bv, bok = cb.next()
+		}
+
+		if !visit(v, inA, inB) {
+			break
+		}
+	}
+	return o
+}
+
+// mergeCursors returns the resolved ordering of a, and cursors over the
+// values of a and b that are both ascending by its comparison function.
+func mergeCursors[T any](a, b Tree[T]) (ordering[T], *ascendingCursor[T], *ascendingCursor[T]) {
+	o := orderingOf(a).resolve()
+	return o, newAscendingCursor(a, o), newCursorBy(b, o)
+}
+
+// newCursorBy returns a cursor over the values of t ascending by the
+// comparison function of o, which t need not be ordered by.
+//
+// Comparison functions can not be told apart by looking at them, as two
+// closures of the same function literal may capture different state, and
+// two different functions may order values the same way. Instead, t is
+// walked once first to check that its values are in order by o. If they
+// are, as is usual, they are handed out straight from the tree; otherwise
+// they are collected and sorted by o.
+func newCursorBy[T any](t Tree[T], o ordering[T]) *ascendingCursor[T] {
+	to := orderingOf(t)
+	c := newAscendingCursor(t, to)
+	if c.vals == nil && inOrderBy(newAscendingCursor(t, to), o) {
+		return c
+	}
+
+	var vals []T
+	for v, ok := c.next(); ok; v, ok = c.next() {
+		vals = append(vals, v)
+	}
+	slices.SortStableFunc(vals, o.cmp)
+	return &ascendingCursor[T]{vals: vals}
+}
+
+// inOrderBy reports if the values remaining in c are ascending by the
+// comparison function of o.
+func inOrderBy[T any](c *ascendingCursor[T], o ordering[T]) bool {
+	prev, ok := c.next()
+	for v, more := c.next(); ok && more; v, more = c.next() {
+		if o.cmp(prev, v) > 0 {
+			return false
+		}
+		prev = v
+	}
+	return true
+}
+
+// ascendingCursor hands out the values of a tree one at a time, ascending by
+// its comparison function, so that two trees can be walked side by side. The
+// nodes are walked with an explicit stack, taking O(height) space. Trees
+// without nodes of their own, such as SyncTree, are read into a slice first.
+type ascendingCursor[T any] struct {
+	stack []BinaryTree[T]
+
+	// reverse is set for Descending trees, walking right to left.
+	reverse bool
+
+	// vals holds the remaining values of a tree without nodes.
+	vals []T
+
+	// repeats is the number of copies of the value of the node on top of
+	// the stack already handed out.
+	repeats int
+}
+
+// newAscendingCursor returns a cursor over the values of t, which has the
+// ordering o.
+func newAscendingCursor[T any](t Tree[T], o ordering[T]) *ascendingCursor[T] {
+	c := &ascendingCursor[T]{reverse: o.descending}
+	root := rootOf(t)
+	if root == nil {
+		c.vals = ToSlice(t)
+		if c.reverse {
+			slices.Reverse(c.vals)
+		}
+		return c
+	}
+	if !isTreeNil(root) {
+		c.push(root)
+	}
+	return c
+}
+
+// push stacks n and the run of nodes leading down from it to the first value
+// in ascending order below it.
+func (c *ascendingCursor[T]) push(n BinaryTree[T]) {
+	for {
+		c.stack = append(c.stack, n)
+		if c.reverse && n.HasRight() {
+			n = n.Right()
+		} else if !c.reverse && n.HasLeft() {
+			n = n.Left()
+		} else {
+			return
+		}
+	}
+}
+
+// next returns the next value, or false once every value has been returned.
+func (c *ascendingCursor[T]) next() (T, bool) {
+	if c.vals != nil || len(c.stack) == 0 {
+		var zero T
+		if len(c.vals) == 0 {
+			return zero, false
+		}
+		v := c.vals[0]
+		c.vals = c.vals[1:]
+		return v, true
+	}
+
+	n := c.stack[len(c.stack)-1]
+	if c.repeats++; c.repeats < copiesOf(n) {
+		return n.Value(), true
+	}
+	c.repeats = 0
+	c.stack = c.stack[:len(c.stack)-1]
+	if c.reverse && n.HasLeft() {
+		c.push(n.Left())
+	} else if !c.reverse && n.HasRight() {
+		c.push(n.Right())
+	}
+	return n.Value(), true
 }
 
 // Split splits the Tree into two trees such that first tree returned constains
@@ -70,11 +699,25 @@ func Prune[T constraints.Ordered](t Tree[T], val T) Tree[T] {
 	return t
 }
 
-// Rebalance attempts to perform some rebalancing on a tree.
+// Rebalance returns a height balanced tree holding the values of the given
+// tree, of the same type and with the same ordering. The values are collected
+// in order and the tree is rebuilt from them, which takes O(n) time.
 //
-// Not all types need it so those types may short-circuit this.
-func Rebalance[T constraints.Ordered](t Tree[T], val T) Tree[T] {
-	return t
+// Not all types need it so those types short-circuit this: AVL, Red-Black,
+// Treap and WeightBalanced trees keep themselves balanced and are returned as
+// they are. A
+// node on its own is rebuilt as a BST. A SyncTree has the tree it wraps
+// rebalanced in place under its write lock, and is itself returned.
+func Rebalance[T any](t Tree[T]) Tree[T] {
+	switch tt := t.(type) {
+	case *AVL[T], *RedBlack[T], *Treap[T], *WeightBalanced[T]:
+		return t
+	case *SyncTree[T]:
+		tt.replace(Rebalance[T])
+		return tt
+	}
+
+	return buildLike(t, inOrderValues(t), orderingOf(t))
 }
 
 // Convert attempts to convert the given tree into a tree of a type specified
@@ -94,8 +737,11 @@ func Convert[T constraints.Ordered](t Tree[T], opts ...treeOptionFunc) Tree[T] {
 }
 
 // ToSlice converts the tree to a slice in natural order.
-func ToSlice[T constraints.Ordered](t Tree[T]) []T {
+func ToSlice[T any](t Tree[T]) []T {
 	var x []T
+	for v := range t.Traverse(TraverseInOrder) {
+		x = append(x, v)
+	}
 	return x
 }
 
@@ -130,15 +776,13 @@ func ToSlice[T constraints.Ordered](t Tree[T]) []T {
 // values in an In Order traversal, but the structure is different.
 //
 // This function supports changing the tolerance for floating point comparisons.
-func Equal[T constraints.Ordered](a, b Tree[T], opts ...treeOptionFunc) bool {
+func Equal[T constraints.Ordered](a, b RootedTree[T], opts ...treeOptionFunc) bool {
 	treeOpts := defaultOptions()
 	for _, opt := range opts {
 		opt(treeOpts)
 	}
 
-	// TODO(rsned): Once other types of Trees exist besides BinaryTree,
-	// enhance this to choose the appropriate equality.
-	return binaryTreesEqual(a.(BinaryTree[T]), b.(BinaryTree[T]))
+	return binaryTreesEqual(a.Root(), b.Root())
 }
 
 // Equivalent reports if the two trees have the same node values in the same order.
@@ -148,15 +792,138 @@ func Equal[T constraints.Ordered](a, b Tree[T], opts ...treeOptionFunc) bool {
 // See the description for Equal for examples of this.
 //
 // This function supports changing the tolerance for floating point comparisons.
-func Equivalent[T constraints.Ordered](a, b Tree[T], opts ...treeOptionFunc) bool {
+func Equivalent[T constraints.Ordered](a, b RootedTree[T], opts ...treeOptionFunc) bool {
 	treeOpts := defaultOptions()
 	for _, opt := range opts {
 		opt(treeOpts)
 	}
 
-	// TODO(rsned): Once other types of Trees exist besides BinaryTree,
-	// enhance this to choose the appropriate equality.
-	return binaryTreesEquivalent(a.(BinaryTree[T]), b.(BinaryTree[T]))
+	return binaryTreesEquivalent(a.Root(), b.Root())
+}
+
+// EqualStrict reports if the two trees are Equal, and also have the same
+// Metadata at every node. Equal ignores metadata, so two AVL trees of the same
+// shape and values are Equal even if one holds stale balance factors, while
+// EqualStrict tells them apart. Trees of different types are rarely strictly
+// equal, as their nodes carry different kinds of metadata.
+//
+// As with SameShape, trees not built from binary tree nodes, such as Ternary,
+// are never strictly equal to any tree.
+func EqualStrict[T constraints.Ordered](a, b Tree[T]) bool {
+	ra, rb := rootOf(a), rootOf(b)
+	if (a != nil && ra == nil) || (b != nil && rb == nil) {
+		return false
+	}
+	return binaryTreesEqual(ra, rb) && binaryTreesMetadataEqual(ra, rb)
+}
+
+// SameValues reports if the two trees hold the same values in the same order,
+// regardless of their shapes or types. A BST and an AVL tree built from the
+// same values have the same values, as do a BST and a SyncTree wrapping one.
+//
+// Unlike Equivalent, the trees need not be built from binary tree nodes.
+func SameValues[T constraints.Ordered](a, b Tree[T]) bool {
+	return slices.Equal(valuesOf(a), valuesOf(b))
+}
+
+// valuesOf returns the values of the given tree in order, or nil for a nil
+// tree.
+func valuesOf[T any](t Tree[T]) []T {
+	if isTreeNil(t) {
+		return nil
+	}
+	return ToSlice(t)
+}
+
+// SameShape reports if the two trees have the same structure, with a node in
+// one wherever there is a node in the other, regardless of the values held or
+// the types of the trees. Empty trees have the same shape as each other.
+//
+// Trees not built from binary tree nodes, such as Ternary, have no shape to
+// compare and are never the same shape as any tree.
+func SameShape[T constraints.Ordered](a, b Tree[T]) bool {
+	ra, rb := rootOf(a), rootOf(b)
+	if (a != nil && ra == nil) || (b != nil && rb == nil) {
+		return false
+	}
+	return binaryTreeStructureEqual(ra, rb)
+}
+
+// ParallelReduce maps every value of the tree with mapFn and combines the
+// results in order with combine, starting from identity. The subtrees of each
+// node are reduced in separate goroutines while there are spare processors,
+// up to GOMAXPROCS in all, which pays off for large trees with expensive
+// mapFn work.
+//
+// combine must be associative, and identity must leave any result unchanged
+// when combined with it. The results are combined in the order of the values
+// in the tree, so combine need not be commutative. mapFn and combine may be
+// called from several goroutines at once.
+//
+// Trees not built from binary tree nodes, such as Ternary, are reduced
+// serially.
+func ParallelReduce[T, A any](t Tree[T], identity A, mapFn func(T) A, combine func(A, A) A) A {
+	if isTreeNil(t) {
+		return identity
+	}
+
+	root := rootOf(t)
+	if root == nil {
+		acc := identity
+		for v := range t.Traverse(TraverseInOrder) {
+			acc = combine(acc, mapFn(v))
+		}
+		return acc
+	}
+	if isTreeNil(root) {
+		return identity
+	}
+
+	// Each token is a goroutine that may be started beyond the current one.
+	tokens := make(chan struct{}, runtime.GOMAXPROCS(0)-1)
+
+	// spawn reports if a token was taken for a new goroutine.
+	spawn := func() bool {
+		if cap(tokens) == 0 {
+			return false
+		}
+		select {
+		case tokens <- struct{}{}:
+			return true
+		default:
+			return false
+		}
+	}
+
+	var reduce func(n BinaryTree[T]) A
+	reduce = func(n BinaryTree[T]) A {
+		left, right := identity, identity
+
+		var wg sync.WaitGroup
+		if n.HasLeft() {
+			if spawn() {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					left = reduce(n.Left())
+					<-tokens
+				}()
+			} else {
+				left = reduce(n.Left())
+			}
+		}
+		mid := mapFn(n.Value())
+		for i := copiesOf(n); i > 1; i-- {
+			mid = combine(mid, mapFn(n.Value()))
+		}
+		if n.HasRight() {
+			right = reduce(n.Right())
+		}
+		wg.Wait()
+
+		return combine(combine(left, mid), right)
+	}
+	return reduce(root)
 }
 
 // Summarize takes a tree and reports a set of basic facts about the tree.
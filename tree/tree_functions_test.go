@@ -1 +1,1455 @@
 package tree
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// newBSTWith returns a BST with the given values inserted in order.
+func newBSTWith(vals ...int) *BST[int] {
+	tree := &BST[int]{}
+	for _, v := range vals {
+		tree.Insert(v)
+	}
+	return tree
+}
+
+// byAbs orders ints by their absolute values.
+func byAbs(a, b int) int {
+	return max(a, -a) - max(b, -b)
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		a, b Tree[int]
+		opts []treeOptionFunc
+		want []int
+	}{
+		{
+			// Two empty trees.
+			a:    &BST[int]{},
+			b:    &BST[int]{},
+			want: []int{},
+		},
+		{
+			// Empty tree with a populated one.
+			a:    &BST[int]{},
+			b:    newBSTWith(21, 1, 42),
+			want: []int{1, 21, 42},
+		},
+		{
+			a:    newBSTWith(21, 1, 42),
+			b:    &BST[int]{},
+			want: []int{1, 21, 42},
+		},
+		{
+			// Disjoint trees.
+			a:    newBSTWith(5, 3, 7),
+			b:    newBSTWith(50, 30, 70),
+			want: []int{3, 5, 7, 30, 50, 70},
+		},
+		{
+			// Interleaved trees.
+			a:    newBSTWith(5, 1, 9),
+			b:    newBSTWith(4, 2, 8),
+			want: []int{1, 2, 4, 5, 8, 9},
+		},
+		{
+			// Overlapping trees.
+			a:    newBSTWith(5, 3, 7, 1),
+			b:    newBSTWith(7, 3, 9),
+			opts: []treeOptionFunc{IgnoreDuplicates(true)},
+			want: []int{1, 3, 5, 7, 9},
+		},
+		{
+			// Joining different tree types.
+			a:    newBSTWith(5, 3, 7),
+			b:    avlTestTree,
+			want: []int{-13, 1, 3, 5, 7, 11, 21, 30, 42, 57, 84, 90},
+		},
+		{
+			// Joining with a descending tree.
+			a: newBSTWith(5, 3, 7),
+			b: &BST[int]{
+				root: &bstNode[int]{
+					value: 4,
+					left:  &bstNode[int]{value: 8},
+					right: &bstNode[int]{value: 2},
+				},
+				size:  3,
+				order: ordering[int]{descending: true},
+			},
+			want: []int{2, 3, 4, 5, 7, 8},
+		},
+		{
+			// Values in both trees are kept from each of them.
+			a:    newBSTWith(3, 1, 5),
+			b:    newBSTWith(4, 3, 5, 6),
+			opts: []treeOptionFunc{IgnoreDuplicates(false)},
+			want: []int{1, 3, 3, 4, 5, 5, 6},
+		},
+		{
+			// Trees with a comparison function are merged by it, so
+			// -2 and 2 count as the same value.
+			a: func() Tree[int] {
+				t := NewBSTFunc(byAbs)
+				t.InsertAll(3, -1, 2)
+				return t
+			}(),
+			b: func() Tree[int] {
+				t := NewBSTFunc(byAbs)
+				t.InsertAll(-2, 4)
+				return t
+			}(),
+			want: []int{-1, 2, 3, 4},
+		},
+		{
+			// Every copy of a value is dropped, not only one from
+			// each tree.
+			a: func() Tree[int] {
+				t := NewBST[int](IgnoreDuplicates(false))
+				t.InsertAll(5, 5, 3)
+				return t
+			}(),
+			b:    newBSTWith(5),
+			want: []int{3, 5},
+		},
+		{
+			a: func() Tree[int] {
+				t := NewBST[int](IgnoreDuplicates(false))
+				t.InsertAll(5, 5, 3)
+				return t
+			}(),
+			b:    newBSTWith(5),
+			opts: []treeOptionFunc{IgnoreDuplicates(false)},
+			want: []int{3, 5, 5, 5},
+		},
+		{
+			// The result is in the direction of the first tree.
+			a: func() Tree[int] {
+				t := NewAVL[int](Descending())
+				t.InsertAll(5, 3, 7)
+				return t
+			}(),
+			b:    newBSTWith(4, 8),
+			want: []int{8, 7, 5, 4, 3},
+		},
+	}
+
+	for _, test := range tests {
+		tree := Join(test.a, test.b, test.opts...)
+		got := ToSlice(tree)
+		if !cmp.Equal(got, test.want, cmpopts.EquateEmpty()) {
+			t.Errorf("Join(%v, %v) = %v, want %v\ndiff: %v",
+				ToSlice(test.a), ToSlice(test.b), got, test.want,
+				cmp.Diff(test.want, got))
+		}
+
+		// The output tree should be balanced.
+		if h, maxH := tree.Height(), minHeight(len(test.want)); h > maxH {
+			t.Errorf("Join(%v, %v).Height() = %d, want <= %d",
+				ToSlice(test.a), ToSlice(test.b), h, maxH)
+		}
+
+		// The output tree should be a valid BST under its own ordering.
+		if !IsValidBST(tree) {
+			t.Errorf("Join(%v, %v) is not a valid BST", ToSlice(test.a), ToSlice(test.b))
+		}
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      Tree[int]
+		wantUnion []int
+		wantInter []int
+		wantDiff  []int
+	}{
+		{
+			name:      "empty trees",
+			a:         &BST[int]{},
+			b:         &BST[int]{},
+			wantUnion: []int{},
+			wantInter: []int{},
+			wantDiff:  []int{},
+		},
+		{
+			name:      "empty second tree",
+			a:         newBSTWith(21, 1, 42),
+			b:         &BST[int]{},
+			wantUnion: []int{1, 21, 42},
+			wantInter: []int{},
+			wantDiff:  []int{1, 21, 42},
+		},
+		{
+			name:      "disjoint",
+			a:         newBSTWith(5, 3, 7),
+			b:         newBSTWith(50, 30, 70),
+			wantUnion: []int{3, 5, 7, 30, 50, 70},
+			wantInter: []int{},
+			wantDiff:  []int{3, 5, 7},
+		},
+		{
+			name:      "overlapping",
+			a:         newBSTWith(5, 3, 7, 1),
+			b:         newBSTWith(7, 3, 9),
+			wantUnion: []int{1, 3, 5, 7, 9},
+			wantInter: []int{3, 7},
+			wantDiff:  []int{1, 5},
+		},
+		{
+			name:      "subset",
+			a:         newBSTWith(5, 3),
+			b:         newBSTWith(5, 3, 7),
+			wantUnion: []int{3, 5, 7},
+			wantInter: []int{3, 5},
+			wantDiff:  []int{},
+		},
+		{
+			name:      "different tree types",
+			a:         newBSTWith(5, 21, 7, 84),
+			b:         avlTestTree,
+			wantUnion: []int{-13, 1, 5, 7, 11, 21, 30, 42, 57, 84, 90},
+			wantInter: []int{21, 84},
+			wantDiff:  []int{5, 7},
+		},
+		{
+			// Repeated values are only counted once.
+			name: "multiset",
+			a: func() Tree[int] {
+				t := NewBST[int](IgnoreDuplicates(false))
+				t.InsertAll(3, 3, 5, 5, 5, 8)
+				return t
+			}(),
+			b:         newBSTWith(5, 1),
+			wantUnion: []int{1, 3, 5, 8},
+			wantInter: []int{5},
+			wantDiff:  []int{3, 8},
+		},
+		{
+			name: "descending tree",
+			a:    newBSTWith(5, 3, 7),
+			b: func() Tree[int] {
+				t := NewBST[int](Descending())
+				t.InsertAll(4, 7, 3)
+				return t
+			}(),
+			wantUnion: []int{3, 4, 5, 7},
+			wantInter: []int{3, 7},
+			wantDiff:  []int{5},
+		},
+		{
+			// The result is in the direction of the first tree.
+			name: "descending first tree",
+			a: func() Tree[int] {
+				t := NewAVL[int](Descending())
+				t.InsertAll(5, 3, 7)
+				return t
+			}(),
+			b:         newBSTWith(5, 1),
+			wantUnion: []int{7, 5, 3, 1},
+			wantInter: []int{5},
+			wantDiff:  []int{7, 3},
+		},
+		{
+			name: "descending SyncTree",
+			a:    newBSTWith(5, 3, 7),
+			b: func() Tree[int] {
+				t := NewBST[int](Descending())
+				t.InsertAll(4, 7, 3)
+				return NewSyncTree(t)
+			}(),
+			wantUnion: []int{3, 4, 5, 7},
+			wantInter: []int{3, 7},
+			wantDiff:  []int{5},
+		},
+	}
+
+	for _, test := range tests {
+		for _, op := range []struct {
+			name string
+			fn   func(a, b Tree[int]) Tree[int]
+			want []int
+		}{
+			{"Union", Union[int], test.wantUnion},
+			{"Intersection", Intersection[int], test.wantInter},
+			{"Difference", Difference[int], test.wantDiff},
+		} {
+			tree := op.fn(test.a, test.b)
+			if got := ToSlice(tree); !cmp.Equal(got, op.want, cmpopts.EquateEmpty()) {
+				t.Errorf("%s: %s() = %v, want %v", test.name, op.name, got, op.want)
+			}
+			if got := tree.Size(); got != len(op.want) {
+				t.Errorf("%s: %s().Size() = %d, want %d", test.name, op.name, got, len(op.want))
+			}
+			// The output tree should be balanced.
+			if h, maxH := tree.Height(), minHeight(len(op.want)); h > maxH {
+				t.Errorf("%s: %s().Height() = %d, want <= %d", test.name, op.name, h, maxH)
+			}
+			if !IsValidBST(tree) {
+				t.Errorf("%s: %s() is not a valid BST", test.name, op.name)
+			}
+		}
+	}
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Tree[int]
+		want []int
+	}{
+		{
+			name: "empty trees",
+			a:    &BST[int]{},
+			b:    &BST[int]{},
+			want: []int{},
+		},
+		{
+			// Full overlap leaves nothing.
+			name: "same values",
+			a:    newBSTWith(5, 3, 7),
+			b:    newBSTWith(3, 7, 5),
+			want: []int{},
+		},
+		{
+			// No overlap is the same as the union.
+			name: "disjoint",
+			a:    newBSTWith(5, 3, 7),
+			b:    newBSTWith(50, 30, 70),
+			want: []int{3, 5, 7, 30, 50, 70},
+		},
+		{
+			name: "partial overlap",
+			a:    newBSTWith(5, 3, 7, 1),
+			b:    newBSTWith(7, 3, 9),
+			want: []int{1, 5, 9},
+		},
+		{
+			name: "different tree types",
+			a:    newBSTWith(5, 21, 7, 84),
+			b:    avlTestTree,
+			want: []int{-13, 1, 5, 7, 11, 30, 42, 57, 90},
+		},
+	}
+
+	for _, test := range tests {
+		tree := SymmetricDifference(test.a, test.b)
+		if got := ToSlice(tree); !cmp.Equal(got, test.want, cmpopts.EquateEmpty()) {
+			t.Errorf("%s: SymmetricDifference() = %v, want %v", test.name, got, test.want)
+		}
+		// It is the same either way around.
+		if got := ToSlice(SymmetricDifference(test.b, test.a)); !cmp.Equal(got, test.want, cmpopts.EquateEmpty()) {
+			t.Errorf("%s: SymmetricDifference(b, a) = %v, want %v", test.name, got, test.want)
+		}
+		if got := tree.Size(); got != len(test.want) {
+			t.Errorf("%s: SymmetricDifference().Size() = %d, want %d", test.name, got, len(test.want))
+		}
+	}
+}
+
+func TestIsSubsetSuperset(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b         Tree[int]
+		wantSubset   bool
+		wantSuperset bool
+	}{
+		{
+			name:         "empty trees",
+			a:            &BST[int]{},
+			b:            &BST[int]{},
+			wantSubset:   true,
+			wantSuperset: true,
+		},
+		{
+			name:         "empty subset",
+			a:            &BST[int]{},
+			b:            newBSTWith(5, 3, 7),
+			wantSubset:   true,
+			wantSuperset: false,
+		},
+		{
+			name:         "proper subset",
+			a:            newBSTWith(5, 7),
+			b:            newBSTWith(5, 3, 7, 9),
+			wantSubset:   true,
+			wantSuperset: false,
+		},
+		{
+			name:         "proper superset",
+			a:            newBSTWith(5, 3, 7, 9),
+			b:            newBSTWith(3, 9),
+			wantSubset:   false,
+			wantSuperset: true,
+		},
+		{
+			name:         "equal sets",
+			a:            newBSTWith(5, 3, 7),
+			b:            newBSTWith(3, 7, 5),
+			wantSubset:   true,
+			wantSuperset: true,
+		},
+		{
+			name:         "disjoint",
+			a:            newBSTWith(5, 3, 7),
+			b:            newBSTWith(50, 30, 70),
+			wantSubset:   false,
+			wantSuperset: false,
+		},
+		{
+			// The last value of a is past the end of b.
+			name:         "overlapping",
+			a:            newBSTWith(5, 3, 8),
+			b:            newBSTWith(5, 3, 7),
+			wantSubset:   false,
+			wantSuperset: false,
+		},
+		{
+			name:         "different tree types",
+			a:            newBSTWith(21, -13, 90),
+			b:            avlTestTree,
+			wantSubset:   true,
+			wantSuperset: false,
+		},
+		{
+			// Repeated values are treated as one.
+			name: "multiset",
+			a: func() Tree[int] {
+				t := NewBST[int](IgnoreDuplicates(false))
+				t.InsertAll(3, 3, 5, 5, 5)
+				return t
+			}(),
+			b:            newBSTWith(5, 3),
+			wantSubset:   true,
+			wantSuperset: true,
+		},
+		{
+			name: "descending tree",
+			a:    newBSTWith(7, 3),
+			b: func() Tree[int] {
+				t := NewBST[int](Descending())
+				t.InsertAll(4, 7, 3)
+				return t
+			}(),
+			wantSubset:   true,
+			wantSuperset: false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := IsSubset(test.a, test.b); got != test.wantSubset {
+			t.Errorf("%s: IsSubset() = %v, want %v", test.name, got, test.wantSubset)
+		}
+		if got := IsSuperset(test.a, test.b); got != test.wantSuperset {
+			t.Errorf("%s: IsSuperset() = %v, want %v", test.name, got, test.wantSuperset)
+		}
+	}
+}
+
+func TestSetOperationsFunc(t *testing.T) {
+	a := NewBSTFunc(byAbs)
+	a.InsertAll(-3, 1, 2)
+	b := NewBSTFunc(byAbs)
+	b.Insert(1)
+
+	// The trees are merged by their comparison function, in which -3 comes
+	// after 2.
+	if got, want := ToSlice(Union[int](a, b)), []int{1, 2, -3}; !slices.Equal(got, want) {
+		t.Errorf("Union() = %v, want %v", got, want)
+	}
+	if got, want := ToSlice(Intersection[int](a, b)), []int{1}; !slices.Equal(got, want) {
+		t.Errorf("Intersection() = %v, want %v", got, want)
+	}
+	added, removed := Diff[int](a, b)
+	if len(added) != 0 || !slices.Equal(removed, []int{2, -3}) {
+		t.Errorf("Diff() = %v, %v, want [], [2 -3]", added, removed)
+	}
+	if !IsSubset[int](b, a) || IsSubset[int](a, b) {
+		t.Errorf("IsSubset() = %v, %v, want true, false", IsSubset[int](b, a), IsSubset[int](a, b))
+	}
+
+	// The result keeps the comparison function.
+	union := Union[int](a, b)
+	if !union.Insert(-4) || union.Insert(4) {
+		t.Errorf("Union() of trees with a comparison function did not keep it")
+	}
+
+	// A tree ordered some other way is merged by the comparison function
+	// of the first tree, in which -2 and 2 are the same value.
+	if got, want := ToSlice(Union[int](a, newBSTWith(-2, 1, 4))), []int{1, 2, -3, 4}; !slices.Equal(got, want) {
+		t.Errorf("Union() with a naturally ordered tree = %v, want %v", got, want)
+	}
+	if !IsSubset[int](a, newBSTWith(-3, -2, -1, 5)) {
+		t.Errorf("IsSubset() of a naturally ordered tree = false, want true")
+	}
+
+	// Closures of the same function literal are told apart by the order
+	// of the values, not their code, so b is merged by the remainders
+	// modulo 10 of a, in which 3 and 13 are the same value.
+	byMod := func(m int) func(a, b int) int {
+		return func(a, b int) int { return a%m - b%m }
+	}
+	mod10 := NewBSTFunc(byMod(10))
+	mod10.InsertAll(3, 14)
+	mod7 := NewBSTFunc(byMod(7))
+	mod7.InsertAll(3, 8, 13)
+	if got, want := ToSlice(Union(mod10, mod7)), []int{3, 14, 8}; !slices.Equal(got, want) {
+		t.Errorf("Union() of modulo 10 and 7 trees = %v, want %v", got, want)
+	}
+	if got, want := ToSlice(Intersection(mod10, mod7)), []int{3}; !slices.Equal(got, want) {
+		t.Errorf("Intersection() of modulo 10 and 7 trees = %v, want %v", got, want)
+	}
+
+	// The same order from a different function merges in place.
+	words := NewBST[string]()
+	words.InsertAll("dog", "cat")
+	funcWords := NewBSTFunc(strings.Compare)
+	funcWords.InsertAll("cow", "cat", "ant")
+	if got, want := ToSlice(Join(words, funcWords)), []string{"ant", "cat", "cow", "dog"}; !slices.Equal(got, want) {
+		t.Errorf("Join() of natural and strings.Compare trees = %v, want %v", got, want)
+	}
+	if got, want := ToSlice(Union(funcWords, words)), []string{"ant", "cat", "cow", "dog"}; !slices.Equal(got, want) {
+		t.Errorf("Union() of strings.Compare and natural trees = %v, want %v", got, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name        string
+		a, b        Tree[int]
+		wantAdded   []int
+		wantRemoved []int
+	}{
+		{
+			name: "both empty",
+			a:    &BST[int]{},
+			b:    &BST[int]{},
+		},
+		{
+			name: "identical",
+			a:    newBSTWith(5, 3, 7),
+			b:    newBSTWith(3, 7, 5),
+		},
+		{
+			name:      "from empty",
+			a:         &BST[int]{},
+			b:         newBSTWith(5, 3, 7),
+			wantAdded: []int{3, 5, 7},
+		},
+		{
+			name:        "to empty",
+			a:           newBSTWith(5, 3, 7),
+			b:           &BST[int]{},
+			wantRemoved: []int{3, 5, 7},
+		},
+		{
+			name:        "disjoint",
+			a:           newBSTWith(5, 3, 7),
+			b:           newBSTWith(50, 30, 70),
+			wantAdded:   []int{30, 50, 70},
+			wantRemoved: []int{3, 5, 7},
+		},
+		{
+			name:        "overlapping",
+			a:           newBSTWith(21, 1, 42, -13, 11),
+			b:           newBSTWith(21, 42, 11, 30, 84),
+			wantAdded:   []int{30, 84},
+			wantRemoved: []int{-13, 1},
+		},
+		{
+			// Repeated values are treated as one.
+			name: "multiset",
+			a: func() Tree[int] {
+				t := NewBST[int](IgnoreDuplicates(false))
+				t.InsertAll(3, 3, 5, 5, 5)
+				return t
+			}(),
+			b:           newBSTWith(5, 9),
+			wantAdded:   []int{9},
+			wantRemoved: []int{3},
+		},
+		{
+			name: "different tree types",
+			a:    newBSTWith(21, -13, 90, 100),
+			b: func() Tree[int] {
+				t := NewAVL[int](Descending())
+				t.InsertAll(21, -13, 90, 4)
+				return t
+			}(),
+			wantAdded:   []int{4},
+			wantRemoved: []int{100},
+		},
+	}
+
+	for _, test := range tests {
+		added, removed := Diff(test.a, test.b)
+		if diff := cmp.Diff(test.wantAdded, added, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("%s: Diff() added differs (-want +got):\n%s", test.name, diff)
+		}
+		if diff := cmp.Diff(test.wantRemoved, removed, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("%s: Diff() removed differs (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestMirror(t *testing.T) {
+	avl := &AVL[int]{}
+	for _, v := range []int{42, 21, 84, 1, 30, 99, -13, 11} {
+		avl.Insert(v)
+	}
+
+	tests := []struct {
+		name string
+		tree Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTWith(42, 21, 84, 1, 30, 57, 99, 25),
+		},
+		{
+			name: "AVL",
+			tree: avl,
+		},
+		{
+			name: "node",
+			tree: newBSTWith(3, 2, 1).Root().(Tree[int]),
+		},
+	}
+
+	for _, test := range tests {
+		var reversed []int
+		for v := range test.tree.Traverse(TraverseReverseOrder) {
+			reversed = append(reversed, v)
+		}
+
+		mirror := Mirror(test.tree)
+		var got []int
+		InOrderInto(mirror, &got)
+		if diff := cmp.Diff(reversed, got); diff != "" {
+			t.Errorf("%s: in order values of Mirror() differ from the reverse order of the tree (-want +got):\n%s", test.name, diff)
+		}
+
+		// Mirroring twice gives back the original structure.
+		if twice := Mirror(mirror.(Tree[int])); !binaryTreeStructureEqual(twice, rootOf(test.tree)) {
+			t.Errorf("%s: Mirror(Mirror()) does not have the structure of the original", test.name)
+		}
+	}
+
+	if got := Mirror[int](&BST[int]{}); !isTreeNil(got) {
+		t.Errorf("Mirror(empty tree) = %v, want an empty tree", got)
+	}
+}
+
+func TestMap(t *testing.T) {
+	vals := []int{42, 21, 84, 1, 30, 57, 99, -13, 11}
+
+	avl := &AVL[int]{}
+	rb := &RedBlack[int]{}
+	for _, v := range vals {
+		avl.Insert(v)
+		rb.Insert(v)
+	}
+
+	trees := []struct {
+		name string
+		tree Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTWith(vals...),
+		},
+		{
+			name: "AVL",
+			tree: avl,
+		},
+		{
+			name: "RedBlack",
+			tree: rb,
+		},
+		{
+			name: "Treap",
+			tree: func() Tree[int] {
+				tree := NewTreap[int](WithRandSource(rand.NewSource(7)))
+				tree.InsertAll(vals...)
+				return tree
+			}(),
+		},
+	}
+
+	funcs := []struct {
+		name string
+		f    func(int) int
+		want []int
+	}{
+		{
+			name: "negate",
+			f:    func(v int) int { return -v },
+			want: []int{-99, -84, -57, -42, -30, -21, -11, -1, 13},
+		},
+		{
+			name: "double",
+			f:    func(v int) int { return 2 * v },
+			want: []int{-26, 2, 22, 42, 60, 84, 114, 168, 198},
+		},
+		{
+			// Values mapping to the same result are only kept once.
+			name: "sign",
+			f: func(v int) int {
+				if v < 0 {
+					return -1
+				}
+				return 1
+			},
+			want: []int{-1, 1},
+		},
+	}
+
+	for _, tt := range trees {
+		for _, fn := range funcs {
+			got := Map(tt.tree, fn.f)
+
+			if gotType, wantType := fmt.Sprintf("%T", got), fmt.Sprintf("%T", tt.tree); gotType != wantType {
+				t.Errorf("%s: Map(%s) returned a %s, want %s", tt.name, fn.name, gotType, wantType)
+			}
+
+			var gotVals []int
+			InOrderInto(rootOf(got), &gotVals)
+			if diff := cmp.Diff(fn.want, gotVals); diff != "" {
+				t.Errorf("%s: Map(%s) values differ (-want +got):\n%s", tt.name, fn.name, diff)
+			}
+			if got.Size() != len(fn.want) {
+				t.Errorf("%s: Map(%s).Size() = %d, want %d", tt.name, fn.name, got.Size(), len(fn.want))
+			}
+			if !IsValidBST(got) || !IsBalanced(rootOf(got)) {
+				t.Errorf("%s: Map(%s) is not a valid balanced tree", tt.name, fn.name)
+			}
+			switch g := got.(type) {
+			case *AVL[int]:
+				if !IsValidAVL(got) {
+					t.Errorf("%s: Map(%s) is not a valid AVL tree", tt.name, fn.name)
+				}
+			case *RedBlack[int]:
+				if g.root.isRed || blackHeight(g.root) < 0 {
+					t.Errorf("%s: Map(%s) is not a valid Red-Black tree", tt.name, fn.name)
+				}
+			case *Treap[int]:
+				if !checkTreap(g.root) {
+					t.Errorf("%s: Map(%s) is not in heap order", tt.name, fn.name)
+				}
+			}
+		}
+	}
+
+	// The values can change type, and the new tree keeps the direction of
+	// the original.
+	desc := NewBST[int](Descending())
+	desc.InsertAll(5, 10, 9)
+	strs := Map(desc, strconv.Itoa)
+	var got []string
+	InOrderInto(rootOf(strs), &got)
+	if want := []string{"9", "5", "10"}; !slices.Equal(got, want) {
+		t.Errorf("Map(descending, strconv.Itoa) = %q, want %q", got, want)
+	}
+	if !strs.Insert("7") || !strs.Search("7") {
+		t.Errorf("Insert(\"7\") into mapped descending tree failed")
+	}
+}
+
+// blackHeight returns the number of black nodes on every path from the given
+// node down to a missing child, or -1 if the paths differ or a red node has a
+// red child.
+func blackHeight[T any](n *redBlackNode[T]) int {
+	if n == nil {
+		return 0
+	}
+	if n.isRed && ((n.left != nil && n.left.isRed) || (n.right != nil && n.right.isRed)) {
+		return -1
+	}
+	lh, rh := blackHeight(n.left), blackHeight(n.right)
+	if lh < 0 || lh != rh {
+		return -1
+	}
+	if n.isRed {
+		return lh
+	}
+	return lh + 1
+}
+
+func TestFilter(t *testing.T) {
+	vals := []int{42, 21, 84, 1, 30, 57, 99, -13, 11, 8, 64}
+	odd := func(v int) bool { return v%2 != 0 }
+
+	avl := &AVL[int]{}
+	rb := &RedBlack[int]{}
+	for _, v := range vals {
+		avl.Insert(v)
+		rb.Insert(v)
+	}
+
+	tests := []struct {
+		name string
+		tree Tree[int]
+		pred func(int) bool
+		want []int
+	}{
+		{
+			name: "BST",
+			tree: newBSTWith(vals...),
+			pred: odd,
+			want: []int{-13, 1, 11, 21, 57, 99},
+		},
+		{
+			name: "AVL",
+			tree: avl,
+			pred: odd,
+			want: []int{-13, 1, 11, 21, 57, 99},
+		},
+		{
+			name: "RedBlack",
+			tree: rb,
+			pred: odd,
+			want: []int{-13, 1, 11, 21, 57, 99},
+		},
+		{
+			name: "descending",
+			tree: func() Tree[int] {
+				tree := NewAVL[int](Descending())
+				tree.InsertAll(vals...)
+				return tree
+			}(),
+			pred: odd,
+			want: []int{99, 57, 21, 11, 1, -13},
+		},
+		{
+			name: "descending Treap",
+			tree: func() Tree[int] {
+				tree := NewTreap[int](Descending())
+				tree.InsertAll(vals...)
+				return tree
+			}(),
+			pred: func(int) bool { return true },
+			want: []int{99, 84, 64, 57, 42, 30, 21, 11, 8, 1, -13},
+		},
+		{
+			name: "keep all",
+			tree: avl,
+			pred: func(int) bool { return true },
+			want: []int{-13, 1, 8, 11, 21, 30, 42, 57, 64, 84, 99},
+		},
+		{
+			name: "keep none",
+			tree: avl,
+			pred: func(int) bool { return false },
+			want: nil,
+		},
+	}
+
+	for _, test := range tests {
+		var before []int
+		InOrderInto(rootOf(test.tree), &before)
+
+		got := Filter(test.tree, test.pred)
+		if gotType, wantType := fmt.Sprintf("%T", got), fmt.Sprintf("%T", test.tree); gotType != wantType {
+			t.Errorf("%s: Filter() returned a %s, want %s", test.name, gotType, wantType)
+		}
+
+		var gotVals []int
+		InOrderInto(rootOf(got), &gotVals)
+		if diff := cmp.Diff(test.want, gotVals); diff != "" {
+			t.Errorf("%s: Filter() values differ (-want +got):\n%s", test.name, diff)
+		}
+		if got.Size() != len(test.want) {
+			t.Errorf("%s: Filter().Size() = %d, want %d", test.name, got.Size(), len(test.want))
+		}
+		if !IsBalanced(rootOf(got)) {
+			t.Errorf("%s: Filter() is not balanced", test.name)
+		}
+		if _, ok := got.(*AVL[int]); ok && !IsValidAVL(got) {
+			t.Errorf("%s: Filter() is not a valid AVL tree", test.name)
+		}
+		if tr, ok := got.(*Treap[int]); ok && !checkTreap(tr.root) {
+			t.Errorf("%s: Filter() is not in heap order", test.name)
+		}
+		for _, v := range test.want {
+			if !got.Search(v) {
+				t.Errorf("%s: Filter().Search(%d) = false, want true", test.name, v)
+			}
+		}
+
+		// The original is unchanged.
+		var after []int
+		InOrderInto(rootOf(test.tree), &after)
+		if !slices.Equal(before, after) {
+			t.Errorf("%s: Filter() changed the tree from %v to %v", test.name, before, after)
+		}
+	}
+
+	// Trees ordered by a comparison function keep it.
+	lengths := NewBSTFunc(func(a, b string) int { return len(a) - len(b) })
+	lengths.InsertAll("ccc", "a", "dddd", "bb")
+	short := Filter(lengths, func(s string) bool { return len(s) < 4 })
+	if !short.Insert("eeeee") || short.Insert("zz") {
+		t.Errorf("Filter() of a tree with a comparison function did not keep it")
+	}
+}
+
+func TestTrim(t *testing.T) {
+	vals := []int{42, 21, 84, 1, 30, 57, 99, -13, 11, 8, 64}
+
+	avl := &AVL[int]{}
+	rb := &RedBlack[int]{}
+	for _, v := range vals {
+		avl.Insert(v)
+		rb.Insert(v)
+	}
+	descending := NewAVL[int](Descending())
+	descending.InsertAll(vals...)
+	descTreap := NewTreap[int](Descending())
+	descTreap.InsertAll(vals...)
+	descWB := NewWeightBalanced[int](DefaultWeightBalanceDelta, Descending())
+	descWB.InsertAll(vals...)
+
+	tests := []struct {
+		name   string
+		tree   Tree[int]
+		lo, hi int
+		want   []int
+	}{
+		{
+			name: "BST interior",
+			tree: newBSTWith(vals...),
+			lo:   10,
+			hi:   60,
+			want: []int{11, 21, 30, 42, 57},
+		},
+		{
+			name: "AVL interior",
+			tree: avl,
+			lo:   10,
+			hi:   60,
+			want: []int{11, 21, 30, 42, 57},
+		},
+		{
+			name: "RedBlack interior",
+			tree: rb,
+			lo:   10,
+			hi:   60,
+			want: []int{11, 21, 30, 42, 57},
+		},
+		{
+			name: "descending interior",
+			tree: descending,
+			lo:   10,
+			hi:   60,
+			want: []int{57, 42, 30, 21, 11},
+		},
+		{
+			name: "descending Treap interior",
+			tree: descTreap,
+			lo:   10,
+			hi:   60,
+			want: []int{57, 42, 30, 21, 11},
+		},
+		{
+			name: "descending WeightBalanced interior",
+			tree: descWB,
+			lo:   10,
+			hi:   60,
+			want: []int{57, 42, 30, 21, 11},
+		},
+		{
+			name: "bounds are inclusive",
+			tree: newBSTWith(vals...),
+			lo:   1,
+			hi:   42,
+			want: []int{1, 8, 11, 21, 30, 42},
+		},
+		{
+			name: "BST empty range",
+			tree: newBSTWith(vals...),
+			lo:   2,
+			hi:   7,
+			want: nil,
+		},
+		{
+			name: "AVL empty range",
+			tree: avl,
+			lo:   100,
+			hi:   200,
+			want: nil,
+		},
+		{
+			name: "reversed bounds",
+			tree: avl,
+			lo:   60,
+			hi:   10,
+			want: nil,
+		},
+		{
+			name: "BST everything",
+			tree: newBSTWith(vals...),
+			lo:   -100,
+			hi:   100,
+			want: []int{-13, 1, 8, 11, 21, 30, 42, 57, 64, 84, 99},
+		},
+		{
+			name: "AVL everything",
+			tree: avl,
+			lo:   -100,
+			hi:   100,
+			want: []int{-13, 1, 8, 11, 21, 30, 42, 57, 64, 84, 99},
+		},
+		{
+			name: "empty tree",
+			tree: &BST[int]{},
+			lo:   -100,
+			hi:   100,
+			want: nil,
+		},
+	}
+
+	for _, test := range tests {
+		var before []int
+		InOrderInto(rootOf(test.tree), &before)
+
+		got := Trim(test.tree, test.lo, test.hi)
+		if gotType, wantType := fmt.Sprintf("%T", got), fmt.Sprintf("%T", test.tree); gotType != wantType {
+			t.Errorf("%s: Trim() returned a %s, want %s", test.name, gotType, wantType)
+		}
+
+		var gotVals []int
+		InOrderInto(rootOf(got), &gotVals)
+		if diff := cmp.Diff(test.want, gotVals); diff != "" {
+			t.Errorf("%s: Trim(%d, %d) values differ (-want +got):\n%s", test.name, test.lo, test.hi, diff)
+		}
+		if got.Size() != len(test.want) {
+			t.Errorf("%s: Trim(%d, %d).Size() = %d, want %d", test.name, test.lo, test.hi, got.Size(), len(test.want))
+		}
+		for _, v := range test.want {
+			if !got.Search(v) {
+				t.Errorf("%s: Trim(%d, %d).Search(%d) = false, want true", test.name, test.lo, test.hi, v)
+			}
+		}
+		if _, ok := got.(*AVL[int]); ok && !IsValidAVL(got) {
+			t.Errorf("%s: Trim(%d, %d) is not a valid AVL tree", test.name, test.lo, test.hi)
+		}
+
+		// The original is unchanged.
+		var after []int
+		InOrderInto(rootOf(test.tree), &after)
+		if !slices.Equal(before, after) {
+			t.Errorf("%s: Trim() changed the tree from %v to %v", test.name, before, after)
+		}
+	}
+
+	// A BST keeps the relative structure of the values that are left, with
+	// each trimmed node replaced by its trimmed subtree on the inside.
+	got := Trim[int](newBSTWith(vals...), 10, 60)
+	if want := newBSTWith(42, 21, 57, 11, 30); !binaryTreesEqual(rootOf(got), want.Root()) {
+		t.Errorf("Trim(10, 60) of a BST = %v, want %v", got, want)
+	}
+	got = Trim[int](newBSTWith(vals...), -100, 100)
+	if want := newBSTWith(vals...); !binaryTreesEqual(rootOf(got), want.Root()) {
+		t.Errorf("Trim(-100, 100) of a BST = %v, want %v", got, want)
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	const n = 1000
+
+	// Sorted inserts leave a plain BST as one long right leaning chain.
+	vals := make([]int, n)
+	for i := range vals {
+		vals[i] = i
+	}
+	degenerate := newBSTWith(vals...)
+	if h := degenerate.Height(); h != n {
+		t.Fatalf("degenerate tree Height() = %d, want %d", h, n)
+	}
+
+	got := Rebalance[int](degenerate)
+	bst, ok := got.(*BST[int])
+	if !ok {
+		t.Fatalf("Rebalance() returned a %T, want a *BST[int]", got)
+	}
+	if h, want := bst.Height(), minHeight(n); h != want {
+		t.Errorf("Rebalance().Height() = %d, want %d", h, want)
+	}
+	if bst.Size() != n {
+		t.Errorf("Rebalance().Size() = %d, want %d", bst.Size(), n)
+	}
+	var gotVals []int
+	InOrderInto(bst.Root(), &gotVals)
+	if diff := cmp.Diff(vals, gotVals); diff != "" {
+		t.Errorf("Rebalance() values differ (-want +got):\n%s", diff)
+	}
+	if !IsValidBST(bst.Root()) {
+		t.Errorf("Rebalance() is not a valid BST")
+	}
+
+	// The ordering of the tree is kept.
+	desc := NewBST[int](Descending())
+	desc.InsertAll(5, 4, 3, 2, 1)
+	rebalanced := Rebalance(desc)
+	if h := rebalanced.Height(); h != 3 {
+		t.Errorf("descending Rebalance().Height() = %d, want 3", h)
+	}
+	if !rebalanced.Insert(0) {
+		t.Errorf("Insert(0) into the rebalanced descending tree = false, want true")
+	}
+	var descVals []int
+	InOrderInto(rootOf(rebalanced), &descVals)
+	if want := []int{5, 4, 3, 2, 1, 0}; !slices.Equal(descVals, want) {
+		t.Errorf("descending Rebalance() values = %v, want %v", descVals, want)
+	}
+
+	// Self balancing trees are left alone.
+	avl := &AVL[int]{}
+	avl.InsertAll(vals...)
+	if got := Rebalance[int](avl); got != Tree[int](avl) {
+		t.Errorf("Rebalance(AVL) did not return the same tree")
+	}
+
+	// The rebalance threshold of a BST is kept.
+	auto := NewBST[int](AutoRebalanceThreshold(2)).(*BST[int])
+	auto.InsertAll(5, 4, 3, 2, 1)
+	if got := Rebalance[int](auto).(*BST[int]).rebalanceFactor; got != auto.rebalanceFactor {
+		t.Errorf("Rebalance() rebalanceFactor = %v, want %v", got, auto.rebalanceFactor)
+	}
+
+	// A SyncTree is rebalanced in place.
+	synced := NewSyncTree[int](newBSTWith(vals...))
+	if got := Rebalance[int](synced); got != Tree[int](synced) {
+		t.Errorf("Rebalance(SyncTree) did not return the same tree")
+	}
+	if h, want := synced.Height(), minHeight(n); h != want {
+		t.Errorf("Rebalance(SyncTree).Height() = %d, want %d", h, want)
+	}
+	if got := ToSlice[int](synced); !slices.Equal(got, vals) {
+		t.Errorf("Rebalance(SyncTree) lost values, holds %d of %d", len(got), n)
+	}
+}
+
+func TestEqualRootedTrees(t *testing.T) {
+	vals := []int{4, 2, 6, 1, 3, 5, 7}
+
+	// Each of the trees is built into the same shape:
+	//
+	//	    4
+	//	  /   \
+	//	 2     6
+	//	/ \   / \
+	//	1 3   5   7
+	bst := NewBST[int]()
+	bst.InsertAll(vals...)
+	avl := NewAVL[int]()
+	avl.InsertAll(vals...)
+	rb := NewRedBlack[int]()
+	rb.InsertAll(vals...)
+
+	// The Treap picks its own shape, so it is only Equal to a tree built
+	// to match. See TestTreapFixedSeed.
+	treap := NewTreap[int](WithRandSource(rand.NewSource(1)))
+	treap.InsertAll(vals...)
+	treapShaped := newBSTWith(2, 1, 5, 4, 3, 6, 7)
+
+	tests := []struct {
+		name      string
+		a, b      RootedTree[int]
+		wantEqual bool
+		wantEquiv bool
+	}{
+		{name: "BST, AVL", a: bst.(RootedTree[int]), b: avl.(RootedTree[int]), wantEqual: true, wantEquiv: true},
+		{name: "AVL, RedBlack", a: avl.(RootedTree[int]), b: rb.(RootedTree[int]), wantEqual: true, wantEquiv: true},
+		{name: "RedBlack, BST", a: rb.(RootedTree[int]), b: bst.(RootedTree[int]), wantEqual: true, wantEquiv: true},
+		{name: "Treap, BST", a: treap.(RootedTree[int]), b: treapShaped, wantEqual: true, wantEquiv: true},
+		{name: "Treap, AVL", a: treap.(RootedTree[int]), b: avl.(RootedTree[int]), wantEqual: false, wantEquiv: true},
+		{name: "BST, smaller BST", a: bst.(RootedTree[int]), b: newBSTWith(4, 2, 6), wantEqual: false, wantEquiv: false},
+		{name: "empty trees", a: &BST[int]{}, b: &AVL[int]{}, wantEqual: true, wantEquiv: true},
+	}
+
+	for _, test := range tests {
+		if got := Equal(test.a, test.b); got != test.wantEqual {
+			t.Errorf("%s: Equal() = %v, want %v", test.name, got, test.wantEqual)
+		}
+		if got := Equivalent(test.a, test.b); got != test.wantEquiv {
+			t.Errorf("%s: Equivalent() = %v, want %v", test.name, got, test.wantEquiv)
+		}
+	}
+}
+
+func TestEqualStrict(t *testing.T) {
+	vals := []int{4, 2, 6, 1, 3, 5, 7}
+	avl := NewAVL[int]()
+	avl.InsertAll(vals...)
+	same := NewAVL[int]()
+	same.InsertAll(vals...)
+
+	// stale has the same shape and values, but one node has a wrong
+	// balance factor, as if it had not been updated after a change.
+	stale := NewAVL[int]()
+	stale.InsertAll(vals...)
+	stale.(*AVL[int]).root.left.bf = 1
+
+	smaller := NewAVL[int]()
+	smaller.InsertAll(4, 2, 6)
+	bst := NewBST[int]()
+	bst.InsertAll(vals...)
+
+	tests := []struct {
+		name       string
+		a, b       RootedTree[int]
+		wantEqual  bool
+		wantStrict bool
+	}{
+		{name: "same AVL", a: avl.(RootedTree[int]), b: same.(RootedTree[int]), wantEqual: true, wantStrict: true},
+		{name: "stale balance factor", a: avl.(RootedTree[int]), b: stale.(RootedTree[int]), wantEqual: true, wantStrict: false},
+		{name: "AVL, BST", a: avl.(RootedTree[int]), b: bst.(RootedTree[int]), wantEqual: true, wantStrict: false},
+		{name: "AVL, smaller AVL", a: avl.(RootedTree[int]), b: smaller.(RootedTree[int]), wantEqual: false, wantStrict: false},
+		{name: "empty trees", a: &AVL[int]{}, b: &AVL[int]{}, wantEqual: true, wantStrict: true},
+	}
+
+	for _, test := range tests {
+		if got := Equal(test.a, test.b); got != test.wantEqual {
+			t.Errorf("%s: Equal() = %v, want %v", test.name, got, test.wantEqual)
+		}
+		if got := EqualStrict(test.a, test.b); got != test.wantStrict {
+			t.Errorf("%s: EqualStrict() = %v, want %v", test.name, got, test.wantStrict)
+		}
+	}
+
+	// Nodes can be compared directly.
+	if got := EqualStrict[int](avl.(*AVL[int]).root, stale.(*AVL[int]).root); got {
+		t.Errorf("EqualStrict(AVL root, stale AVL root) = true, want false")
+	}
+	if got := EqualStrict[string](NewTernary(), NewTernary()); got {
+		t.Errorf("EqualStrict(Ternary, Ternary) = true, want false")
+	}
+}
+
+func TestSameValuesAndShape(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b       Tree[int]
+		wantValues bool
+		wantShape  bool
+	}{
+		{
+			name:       "nil trees",
+			a:          nil,
+			b:          nil,
+			wantValues: true,
+			wantShape:  true,
+		},
+		{
+			name:       "nil and empty trees",
+			a:          nil,
+			b:          &AVL[int]{},
+			wantValues: true,
+			wantShape:  true,
+		},
+		{
+			name:       "empty BST and AVL",
+			a:          &BST[int]{},
+			b:          &AVL[int]{},
+			wantValues: true,
+			wantShape:  true,
+		},
+		{
+			name:       "empty and populated",
+			a:          &BST[int]{},
+			b:          newBSTWith(42),
+			wantValues: false,
+			wantShape:  false,
+		},
+		{
+			name: "single BST and AVL nodes",
+			a:    newBSTWith(42),
+			b: &AVL[int]{
+				root: &avlNode[int]{
+					value: 42,
+				},
+			},
+			wantValues: true,
+			wantShape:  true,
+		},
+		{
+			// Same shape, different values.
+			//
+			//   21       21
+			//  /  \     /  \
+			// 1   53   1   42
+			name:       "BSTs with different values",
+			a:          newBSTWith(21, 1, 53),
+			b:          newBSTWith(21, 1, 42),
+			wantValues: false,
+			wantShape:  true,
+		},
+		{
+			// Same values, different shape.
+			//
+			//     42       21
+			//    /        /  \
+			//   21       1   42
+			//  /
+			// 1
+			name: "BST and AVL with different layout",
+			a:    newBSTWith(42, 21, 1),
+			b: &AVL[int]{
+				root: &avlNode[int]{
+					value: 21,
+					left: &avlNode[int]{
+						value: 1,
+					},
+					right: &avlNode[int]{
+						value: 42,
+					},
+				},
+			},
+			wantValues: true,
+			wantShape:  false,
+		},
+		{
+			name:       "BST and AVL with the same layout",
+			a:          newBSTWith(42, 53),
+			b:          &AVL[int]{root: &avlNode[int]{value: 42, right: &avlNode[int]{value: 53}}},
+			wantValues: true,
+			wantShape:  true,
+		},
+		{
+			// A SyncTree does not give access to the nodes of the
+			// tree it wraps, so it has no shape to compare.
+			name:       "BST and a SyncTree wrapping one",
+			a:          newBSTWith(2, 1, 3),
+			b:          NewSyncTree[int](newBSTWith(2, 1, 3)),
+			wantValues: true,
+			wantShape:  false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := SameValues(test.a, test.b); got != test.wantValues {
+			t.Errorf("%s: SameValues() = %v, want %v", test.name, got, test.wantValues)
+		}
+		if got := SameShape(test.a, test.b); got != test.wantShape {
+			t.Errorf("%s: SameShape() = %v, want %v", test.name, got, test.wantShape)
+		}
+	}
+
+	// Trees of words compare by value, but only binary trees have a shape.
+	words := NewTernary()
+	words.InsertAll("b", "a", "c")
+	bst := NewBST[string]()
+	bst.InsertAll("b", "a", "c")
+	if !SameValues[string](words, bst) {
+		t.Errorf("SameValues(Ternary, BST) = false, want true")
+	}
+	if SameShape[string](words, words) {
+		t.Errorf("SameShape(Ternary, Ternary) = true, want false")
+	}
+}
+
+func TestParallelReduce(t *testing.T) {
+	const n = 100000
+
+	vals := make([]int, n)
+	for i := range vals {
+		vals[i] = i*7 - n
+	}
+	sum := func(a, b int) int { return a + b }
+	square := func(v int) int { return v * v }
+
+	var want int
+	for _, v := range vals {
+		want += square(v)
+	}
+
+	trees := []struct {
+		name string
+		tree Tree[int]
+	}{
+		{name: "AVL", tree: NewAVLFromSorted(vals)},
+		{name: "RedBlack", tree: NewRedBlackFromSorted(vals)},
+		// Without a Root, a SyncTree is reduced serially.
+		{name: "SyncTree", tree: NewSyncTree[int](NewBSTFromSorted(vals))},
+	}
+	for _, tt := range trees {
+		if got := ParallelReduce(tt.tree, 0, square, sum); got != want {
+			t.Errorf("%s: ParallelReduce(sum of squares) = %d, want %d", tt.name, got, want)
+		}
+	}
+
+	// The results are combined in order, so concatenating works.
+	words := newBSTWith(40, 20, 60, 10, 30, 50, 70)
+	got := ParallelReduce[int](words, "", strconv.Itoa, func(a, b string) string {
+		if a == "" {
+			return b
+		}
+		if b == "" {
+			return a
+		}
+		return a + "," + b
+	})
+	if want := "10,20,30,40,50,60,70"; got != want {
+		t.Errorf("ParallelReduce(concatenate) = %q, want %q", got, want)
+	}
+
+	if got := ParallelReduce[int](&BST[int]{}, -1, square, sum); got != -1 {
+		t.Errorf("ParallelReduce(empty tree) = %d, want the identity -1", got)
+	}
+}
+
+// BenchmarkParallelReduce compares ParallelReduce with a serial walk summing
+// a tree with some work done for each value.
+func BenchmarkParallelReduce(b *testing.B) {
+	const n = 100000
+
+	vals := make([]int, n)
+	for i := range vals {
+		vals[i] = i
+	}
+	tree := NewAVLFromSorted(vals)
+
+	work := func(v int) int {
+		for i := 0; i < 100; i++ {
+			v = v*31 + i
+		}
+		return v & 0xff
+	}
+	sum := func(a, b int) int { return a + b }
+
+	b.Run("Serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var total int
+			tree.Walk(TraverseInOrder, func(v int) bool {
+				total += work(v)
+				return true
+			})
+		}
+	})
+
+	b.Run("Parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ParallelReduce[int](tree, 0, work, sum)
+		}
+	})
+}
+
+// minHeight returns the smallest possible height of a binary tree with n nodes.
+func minHeight(n int) int {
+	h := 0
+	for n > 0 {
+		n >>= 1
+		h++
+	}
+	return h
+}
@@ -1,13 +1,19 @@
 package tree
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"maps"
+	"math"
 	"math/rand"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"golang.org/x/exp/constraints"
 )
 
@@ -22,6 +28,11 @@ var (
 		"Flag to restrict benchmark runs to one type of tree. String should match "+
 			"the type name case-insensitively. e.g., avl or AvL or AVL would all "+
 			"be matched to the AVL tree type.")
+
+	concurrentWritePercent = flag.Int("concurrent_write_percent", -1,
+		"Percentage of the operations in BenchmarkConcurrentSearch that are "+
+			"writes rather than reads. If negative, a standard set of ratios "+
+			"is run.")
 )
 
 // To cut out some of the timing variability of benchmark functions, pre-create
@@ -86,19 +97,124 @@ func newAVLTree[T constraints.Ordered]() Tree[int] {
 	return &AVL[int]{}
 }
 
-// To run the Tree Insert Benchmarks use this command with
-// the desired number of run repetitions:
-//
-// go test . --test.benchmem --test.bench="BenchmarkTreeInsert" --count=n
+// newRedBlackTree creates a new RedBlack.
+func newRedBlackTree[T constraints.Ordered]() Tree[int] {
+	return &RedBlack[int]{}
+}
+
+// newTreapTree creates a new Treap.
+func newTreapTree[T constraints.Ordered]() Tree[int] {
+	return &Treap[int]{}
+}
+
+// benchmarkTrees are the tree types run by the Insert and Search benchmark
+// harnesses, named as matched by the tree_type_filter flag.
 //
+// RedBlack is left out until its insert recolors and rotates; for now it
+// builds a plain unbalanced BST, so its numbers would only repeat those of
+// BST under another name.
+var benchmarkTrees = []struct {
+	name string
+	tree newTreeFunc[int]
+}{
+	{
+		name: "BST",
+		tree: newBSTTree[int],
+	},
+	{
+		name: "AVL",
+		tree: newAVLTree[int],
+	},
+	{
+		name: "Treap",
+		tree: newTreapTree[int],
+	},
+}
 
-// BenchmarkTreeInsert is a harness to benchmark the Insert method on all
-// supported tree types.
-func BenchmarkTreeInsert(b *testing.B) {
-	examples := []struct {
+// treeTypeMatches reports if the tree type with the given name should be
+// benchmarked under the given value of the tree_type_filter flag. An empty
+// filter matches every type.
+func treeTypeMatches(name, filter string) bool {
+	return filter == "" || strings.EqualFold(name, filter)
+}
+
+func TestTreeTypeMatches(t *testing.T) {
+	tests := []struct {
 		name   string
-		sorted bool
-		tree   newTreeFunc[int]
+		filter string
+		want   bool
+	}{
+		{name: "AVL", filter: "", want: true},
+		{name: "BST", filter: "", want: true},
+		{name: "AVL", filter: "AVL", want: true},
+		{name: "AVL", filter: "avl", want: true},
+		{name: "AVL", filter: "AvL", want: true},
+		{name: "BST", filter: "avl", want: false},
+		{name: "AVL", filter: "av", want: false},
+	}
+
+	for _, test := range tests {
+		if got := treeTypeMatches(test.name, test.filter); got != test.want {
+			t.Errorf("treeTypeMatches(%q, %q) = %v, want %v", test.name, test.filter, got, test.want)
+		}
+	}
+}
+
+func TestBenchmarkTrees(t *testing.T) {
+	// Every tree type is benchmarked, each under its own name.
+	seen := map[string]bool{}
+	for _, example := range benchmarkTrees {
+		if seen[example.name] {
+			t.Errorf("benchmarkTrees has %q more than once", example.name)
+		}
+		seen[example.name] = true
+
+		tree := example.tree()
+		if got, want := fmt.Sprintf("%T", tree), fmt.Sprintf("*tree.%s[int]", example.name); got != want {
+			t.Errorf("benchmarkTrees %q builds a %s, want %s", example.name, got, want)
+		}
+		if !tree.IsEmpty() {
+			t.Errorf("benchmarkTrees %q builds a tree that is not empty", example.name)
+		}
+
+		// Each one works at the smallest benchmark size.
+		n := insertSteps[0]
+		for _, v := range testIntVals[:n] {
+			tree.Insert(v)
+		}
+		if got := tree.Size(); got != n {
+			t.Errorf("benchmarkTrees %q Size() after %d inserts = %d, want %d", example.name, n, got, n)
+		}
+		for _, v := range testIntVals[:n] {
+			if !tree.Search(v) {
+				t.Errorf("benchmarkTrees %q Search(%d) = false, want true", example.name, v)
+				break
+			}
+		}
+
+		// The Delete and Mixed benchmarks rely on values really being
+		// removed.
+		for _, v := range testIntVals[:n] {
+			if !tree.Delete(v) {
+				t.Errorf("benchmarkTrees %q Delete(%d) = false, want true", example.name, v)
+				break
+			}
+		}
+		if !tree.IsEmpty() {
+			t.Errorf("benchmarkTrees %q Size() after deleting every value = %d, want 0", example.name, tree.Size())
+		}
+	}
+	for _, name := range []string{"BST", "AVL", "Treap"} {
+		if !seen[name] {
+			t.Errorf("benchmarkTrees is missing %q", name)
+		}
+	}
+}
+
+func TestTreeSize(t *testing.T) {
+	trees := []struct {
+		name string
+		tree newTreeFunc[int]
 	}{
 		{
 			name: "BST",
@@ -108,37 +224,146 @@ func BenchmarkTreeInsert(b *testing.B) {
 			name: "AVL",
 			tree: newAVLTree[int],
 		},
+		{
+			name: "RedBlack",
+			tree: newRedBlackTree[int],
+		},
 	}
 
-	for _, example := range examples {
-		// Check if the user requested filtering on the benchmark.
-		if *treeTypeFilter != "" &&
-			strings.EqualFold(example.name, *treeTypeFilter) {
-			continue
+	for _, tt := range trees {
+		tree := tt.tree()
+		if got := tree.Size(); got != 0 {
+			t.Errorf("%s: empty tree.Size() = %d, want 0", tt.name, got)
 		}
 
-		for _, n := range insertSteps {
-			// Skip any tests that are outside the limit.
-			if n > *treeSizeUpperLimit {
-				break
+		for _, v := range []int{21, 1, 42, -13, 11, 30} {
+			tree.Insert(v)
+		}
+		if got := tree.Size(); got != 6 {
+			t.Errorf("%s: tree.Size() after inserts = %d, want 6", tt.name, got)
+		}
+
+		// Rejected duplicates should not change the size.
+		tree.Insert(21)
+		tree.Insert(11)
+		if got := tree.Size(); got != 6 {
+			t.Errorf("%s: tree.Size() after duplicate inserts = %d, want 6", tt.name, got)
+		}
+
+		// Neither should deleting values that are not in the tree.
+		tree.Delete(500)
+		if got := tree.Size(); got != 6 {
+			t.Errorf("%s: tree.Size() after failed delete = %d, want 6", tt.name, got)
+		}
+
+		tree.Insert(84)
+		if got := tree.Size(); got != 7 {
+			t.Errorf("%s: tree.Size() after final insert = %d, want 7", tt.name, got)
+		}
+	}
+}
+
+func TestTreeInsertAll(t *testing.T) {
+	// rooter is the subset of methods being tested here.
+	type rooter interface {
+		Tree[int]
+		Root() BinaryTree[int]
+	}
+
+	trees := []struct {
+		name string
+		tree newTreeFunc[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTTree[int],
+		},
+		{
+			name: "AVL",
+			tree: newAVLTree[int],
+		},
+		{
+			name: "RedBlack",
+			tree: newRedBlackTree[int],
+		},
+	}
+
+	tests := []struct {
+		name string
+		have []int
+		vals []int
+		want int
+	}{
+		{
+			name: "nothing",
+			have: []int{5, 3},
+			want: 0,
+		},
+		{
+			name: "into empty tree",
+			vals: []int{21, 1, 42, -13, 11, 30},
+			want: 6,
+		},
+		{
+			name: "repeats within the values",
+			vals: []int{7, 3, 7, 9, 3, 3, 1},
+			want: 4,
+		},
+		{
+			name: "overlapping the tree",
+			have: []int{10, 20, 30},
+			vals: []int{5, 10, 15, 20, 25, 25},
+			want: 3,
+		},
+		{
+			name: "few into a larger tree",
+			have: []int{50, 25, 75, 12, 37, 62, 87, 6, 18, 31, 43, 56, 68, 81, 93},
+			vals: []int{40, 43},
+			want: 1,
+		},
+	}
+
+	for _, tt := range trees {
+		for _, test := range tests {
+			tree := tt.tree().(rooter)
+			for _, v := range test.have {
+				tree.Insert(v)
 			}
-			vals := testIntVals[:n]
 
-			b.Run(fmt.Sprintf("%s-%06d", example.name, n),
-				func(b *testing.B) {
-					tree := example.tree()
-					for i := 0; i < b.N; i++ {
-						tree.Insert(vals[i%n])
-					}
-				})
+			if got := tree.InsertAll(test.vals...); got != test.want {
+				t.Errorf("%s: %s: InsertAll(%v) = %d, want %d", tt.name, test.name, test.vals, got, test.want)
+			}
+
+			want := append(slices.Clone(test.have), test.vals...)
+			slices.Sort(want)
+			want = slices.Compact(want)
+			var got []int
+			InOrderInto(tree.Root(), &got)
+			if !cmp.Equal(got, want, cmpopts.EquateEmpty()) {
+				t.Errorf("%s: %s: in order values after InsertAll = %v, want %v", tt.name, test.name, got, want)
+			}
+			if got := tree.Size(); got != len(want) {
+				t.Errorf("%s: %s: Size() after InsertAll = %d, want %d", tt.name, test.name, got, len(want))
+			}
+			if avl, ok := tree.(*AVL[int]); ok {
+				if _, ok := checkAVL(avl.root); !ok || !checkAVLSizes(avl.root) {
+					t.Errorf("%s: %s: tree after InsertAll does not hold the AVL invariants", tt.name, test.name)
+				}
+			}
 		}
 	}
 }
 
-// BenchmarkTreeSearch is a harness to benchmark the Search method
-// on all supported tree types.
-func BenchmarkTreeSearch(b *testing.B) {
-	examples := []struct {
+func TestTreeMinMax(t *testing.T) {
+	// minMaxer is the subset of methods being tested here.
+	type minMaxer interface {
+		Tree[int]
+		Min() (int, bool)
+		Max() (int, bool)
+		Root() BinaryTree[int]
+	}
+
+	trees := []struct {
 		name string
 		tree newTreeFunc[int]
 	}{
@@ -150,35 +375,2919 @@ func BenchmarkTreeSearch(b *testing.B) {
 			name: "AVL",
 			tree: newAVLTree[int],
 		},
+		{
+			name: "RedBlack",
+			tree: newRedBlackTree[int],
+		},
 	}
 
-	for _, example := range examples {
-		// Check if the user requested filtering on the benchmark.
-		if *treeTypeFilter != "" &&
-			strings.EqualFold(example.name, *treeTypeFilter) {
-			continue
+	tests := []struct {
+		vals    []int
+		wantMin int
+		wantMax int
+		wantOK  bool
+	}{
+		{
+			vals:   nil,
+			wantOK: false,
+		},
+		{
+			vals:    []int{42},
+			wantMin: 42,
+			wantMax: 42,
+			wantOK:  true,
+		},
+		{
+			vals:    []int{21, 1, 42, -13, 11, 30, 84, 57},
+			wantMin: -13,
+			wantMax: 84,
+			wantOK:  true,
+		},
+	}
+
+	for _, tt := range trees {
+		for _, test := range tests {
+			tree := tt.tree().(minMaxer)
+			for _, v := range test.vals {
+				tree.Insert(v)
+			}
+
+			gotMin, ok := tree.Min()
+			if gotMin != test.wantMin || ok != test.wantOK {
+				t.Errorf("%s: Min() = %v, %v, want %v, %v",
+					tt.name, gotMin, ok, test.wantMin, test.wantOK)
+			}
+			gotMax, ok := tree.Max()
+			if gotMax != test.wantMax || ok != test.wantOK {
+				t.Errorf("%s: Max() = %v, %v, want %v, %v",
+					tt.name, gotMax, ok, test.wantMax, test.wantOK)
+			}
+
+			// These should match the ends of the in order traversal.
+			var vals []int
+			InOrderInto(tree.Root(), &vals)
+			if len(vals) > 0 &&
+				(vals[0] != gotMin || vals[len(vals)-1] != gotMax) {
+				t.Errorf("%s: Min(), Max() = %v, %v, want %v, %v",
+					tt.name, gotMin, gotMax, vals[0], vals[len(vals)-1])
+			}
 		}
+	}
+}
 
-		for _, n := range insertSteps {
-			b.StopTimer()
-			// Skip any tests that are outside the limit.
-			if n > *treeSizeUpperLimit {
-				break
+func TestTreePredecessorSuccessor(t *testing.T) {
+	// neighborer is the subset of methods being tested here.
+	type neighborer interface {
+		Tree[int]
+		Predecessor(v int) (int, bool)
+		Successor(v int) (int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree newTreeFunc[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTTree[int],
+		},
+		{
+			name: "AVL",
+			tree: newAVLTree[int],
+		},
+	}
+
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		val      int
+		wantPred int
+		predOK   bool
+		wantSucc int
+		succOK   bool
+	}{
+		{
+			// The minimum has no predecessor.
+			val:      -13,
+			predOK:   false,
+			wantSucc: 1,
+			succOK:   true,
+		},
+		{
+			// The maximum has no successor.
+			val:      84,
+			wantPred: 57,
+			predOK:   true,
+			succOK:   false,
+		},
+		{
+			val:      21,
+			wantPred: 11,
+			predOK:   true,
+			wantSucc: 30,
+			succOK:   true,
+		},
+		{
+			val:      11,
+			wantPred: 1,
+			predOK:   true,
+			wantSucc: 21,
+			succOK:   true,
+		},
+		{
+			val:      57,
+			wantPred: 42,
+			predOK:   true,
+			wantSucc: 84,
+			succOK:   true,
+		},
+		{
+			// Values not in the tree have no neighbors.
+			val:    25,
+			predOK: false,
+			succOK: false,
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(neighborer)
+
+		// Empty trees have no neighbors.
+		if _, ok := tree.Predecessor(1); ok {
+			t.Errorf("%s: Predecessor(1) on an empty tree = _, true, want false", tt.name)
+		}
+		if _, ok := tree.Successor(1); ok {
+			t.Errorf("%s: Successor(1) on an empty tree = _, true, want false", tt.name)
+		}
+
+		for _, v := range vals {
+			tree.Insert(v)
+		}
+
+		for _, test := range tests {
+			got, ok := tree.Predecessor(test.val)
+			if got != test.wantPred || ok != test.predOK {
+				t.Errorf("%s: Predecessor(%d) = %v, %v, want %v, %v",
+					tt.name, test.val, got, ok, test.wantPred, test.predOK)
 			}
-			vals := testIntVals[:n]
-			// Build and fill the tree before starting the benchmark.
-			tree := example.tree()
-			for i := 0; i < n; i++ {
-				tree.Insert(vals[i])
+			got, ok = tree.Successor(test.val)
+			if got != test.wantSucc || ok != test.succOK {
+				t.Errorf("%s: Successor(%d) = %v, %v, want %v, %v",
+					tt.name, test.val, got, ok, test.wantSucc, test.succOK)
 			}
+		}
+	}
+}
 
-			b.StartTimer()
-			b.Run(fmt.Sprintf("%s-%06d", example.name, n),
-				func(b *testing.B) {
-					for i := 0; i < b.N; i++ {
-						_ = tree.Search(vals[i%n])
-					}
-				})
+func TestTreeLCA(t *testing.T) {
+	// ancestorer is the subset of methods being tested here.
+	type ancestorer interface {
+		Tree[int]
+		LCA(a, b int) (int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree newTreeFunc[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTTree[int],
+		},
+		{
+			name: "AVL",
+			tree: newAVLTree[int],
+		},
+	}
+
+	//          21
+	//        /    \
+	//       1      42
+	//      / \    /  \
+	//   -13  11  30   84
+	//                /
+	//               57
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		a, b int
+		want int
+		ok   bool
+	}{
+		{
+			// Different subtrees of the root.
+			a:    -13,
+			b:    57,
+			want: 21,
+			ok:   true,
+		},
+		{
+			// Different subtrees below the root.
+			a:    30,
+			b:    57,
+			want: 42,
+			ok:   true,
+		},
+		{
+			a:    11,
+			b:    -13,
+			want: 1,
+			ok:   true,
+		},
+		{
+			// One value is an ancestor of the other.
+			a:    42,
+			b:    57,
+			want: 42,
+			ok:   true,
+		},
+		{
+			a:    57,
+			b:    21,
+			want: 21,
+			ok:   true,
+		},
+		{
+			// A value is its own ancestor.
+			a:    11,
+			b:    11,
+			want: 11,
+			ok:   true,
+		},
+		{
+			// Missing values.
+			a:  25,
+			b:  30,
+			ok: false,
+		},
+		{
+			a:  57,
+			b:  60,
+			ok: false,
+		},
+		{
+			a:  -20,
+			b:  100,
+			ok: false,
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(ancestorer)
+
+		if _, ok := tree.LCA(1, 2); ok {
+			t.Errorf("%s: LCA(1, 2) on an empty tree = _, true, want false", tt.name)
+		}
+
+		for _, v := range vals {
+			tree.Insert(v)
+		}
+
+		for _, test := range tests {
+			got, ok := tree.LCA(test.a, test.b)
+			if got != test.want || ok != test.ok {
+				t.Errorf("%s: LCA(%d, %d) = %v, %v, want %v, %v",
+					tt.name, test.a, test.b, got, ok, test.want, test.ok)
+			}
+		}
+	}
+
+	// Descending trees mirror the layout, so the LCA is unchanged.
+	desc := NewBST[int](Descending()).(*BST[int])
+	for _, v := range vals {
+		desc.Insert(v)
+	}
+	if got, ok := desc.LCA(30, 57); got != 42 || !ok {
+		t.Errorf("descending LCA(30, 57) = %v, %v, want 42, true", got, ok)
+	}
+}
+
+func TestTreePathTo(t *testing.T) {
+	// pather is the subset of methods being tested here.
+	type pather interface {
+		Tree[int]
+		PathTo(v int) ([]int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree newTreeFunc[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTTree[int],
+		},
+		{
+			name: "AVL",
+			tree: newAVLTree[int],
+		},
+	}
+
+	//          21
+	//        /    \
+	//       1      42
+	//      / \    /  \
+	//   -13  11  30   84
+	//                /
+	//               57
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		val  int
+		want []int
+		ok   bool
+	}{
+		{
+			val:  21,
+			want: []int{21},
+			ok:   true,
+		},
+		{
+			val:  11,
+			want: []int{21, 1, 11},
+			ok:   true,
+		},
+		{
+			val:  57,
+			want: []int{21, 42, 84, 57},
+			ok:   true,
+		},
+		{
+			// Not in the tree, but would be a child of 57.
+			val: 60,
+			ok:  false,
+		},
+		{
+			val: -20,
+			ok:  false,
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(pather)
+
+		if got, ok := tree.PathTo(1); got != nil || ok {
+			t.Errorf("%s: PathTo(1) on an empty tree = %v, %v, want nil, false", tt.name, got, ok)
+		}
+
+		for _, v := range vals {
+			tree.Insert(v)
+		}
+
+		for _, test := range tests {
+			got, ok := tree.PathTo(test.val)
+			if diff := cmp.Diff(test.want, got); diff != "" || ok != test.ok {
+				t.Errorf("%s: PathTo(%d) = %v, %v, want %v, %v",
+					tt.name, test.val, got, ok, test.want, test.ok)
+			}
+		}
+	}
+}
+
+func TestTreeAncestors(t *testing.T) {
+	// ancestorTree is the subset of methods being tested here.
+	type ancestorTree interface {
+		Tree[int]
+		Ancestors(v int) ([]int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree newTreeFunc[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTTree[int],
+		},
+		{
+			name: "AVL",
+			tree: newAVLTree[int],
+		},
+	}
+
+	//          21
+	//        /    \
+	//       1      42
+	//      / \    /  \
+	//   -13  11  30   84
+	//                /
+	//               57
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		val  int
+		want []int
+		ok   bool
+	}{
+		{
+			val:  21,
+			want: []int{},
+			ok:   true,
+		},
+		{
+			val:  42,
+			want: []int{21},
+			ok:   true,
+		},
+		{
+			val:  11,
+			want: []int{1, 21},
+			ok:   true,
+		},
+		{
+			val:  57,
+			want: []int{84, 42, 21},
+			ok:   true,
+		},
+		{
+			val: 25,
+			ok:  false,
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(ancestorTree)
+
+		if _, ok := tree.Ancestors(1); ok {
+			t.Errorf("%s: Ancestors(1) on an empty tree = _, true, want false", tt.name)
+		}
+
+		for _, v := range vals {
+			tree.Insert(v)
+		}
+
+		for _, test := range tests {
+			got, ok := tree.Ancestors(test.val)
+			if !cmp.Equal(got, test.want) || ok != test.ok {
+				t.Errorf("%s: Ancestors(%d) = %v, %v, want %v, %v",
+					tt.name, test.val, got, ok, test.want, test.ok)
+			}
+		}
+	}
+}
+
+func TestTreeDepth(t *testing.T) {
+	// depther is the subset of methods being tested here.
+	type depther interface {
+		Tree[int]
+		Depth(v int) (int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree newTreeFunc[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTTree[int],
+		},
+		{
+			name: "AVL",
+			tree: newAVLTree[int],
+		},
+	}
+
+	//          21
+	//        /    \
+	//       1      42
+	//      / \    /  \
+	//   -13  11  30   84
+	//                /
+	//               57
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		val  int
+		want int
+		ok   bool
+	}{
+		{
+			val:  21,
+			want: 0,
+			ok:   true,
+		},
+		{
+			val:  42,
+			want: 1,
+			ok:   true,
+		},
+		{
+			val:  -13,
+			want: 2,
+			ok:   true,
+		},
+		{
+			val:  57,
+			want: 3,
+			ok:   true,
+		},
+		{
+			val: 25,
+			ok:  false,
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(depther)
+
+		if _, ok := tree.Depth(1); ok {
+			t.Errorf("%s: Depth(1) on an empty tree = _, true, want false", tt.name)
+		}
+
+		for _, v := range vals {
+			tree.Insert(v)
+		}
+
+		for _, test := range tests {
+			got, ok := tree.Depth(test.val)
+			if got != test.want || ok != test.ok {
+				t.Errorf("%s: Depth(%d) = %v, %v, want %v, %v",
+					tt.name, test.val, got, ok, test.want, test.ok)
+			}
+		}
+
+		// The deepest value is one less than the height.
+		if got, _ := tree.Depth(57); got != tree.Height()-1 {
+			t.Errorf("%s: Depth(57) = %d, want Height()-1 = %d", tt.name, got, tree.Height()-1)
+		}
+	}
+}
+
+func TestTreeDiameter(t *testing.T) {
+	// diameterer is the subset of methods being tested here.
+	type diameterer interface {
+		Tree[int]
+		Diameter() int
+	}
+
+	avl := &AVL[int]{}
+	for _, v := range []int{1, 2, 3, 4, 5, 6, 7, 8} {
+		avl.Insert(v)
+	}
+
+	tests := []struct {
+		name string
+		tree diameterer
+		want int
+	}{
+		{
+			name: "empty",
+			tree: &BST[int]{},
+			want: 0,
+		},
+		{
+			name: "single node",
+			tree: newBSTWith(42),
+			want: 1,
+		},
+		{
+			name: "balanced",
+			tree: newBSTWith(42, 21, 84, 1, 30, 57, 99),
+			want: 5,
+		},
+		{
+			name: "right skewed",
+			tree: newBSTWith(1, 2, 3, 4, 5),
+			want: 5,
+		},
+		{
+			// The longest path runs from 1 up to 20 and back down to 40,
+			// without reaching the root.
+			//
+			//           50
+			//          /
+			//        20
+			//       /  \
+			//     10    30
+			//     /       \
+			//    5        35
+			//   /           \
+			//  1            40
+			name: "not through the root",
+			tree: newBSTWith(50, 20, 10, 30, 5, 35, 1, 40),
+			want: 7,
+		},
+		{
+			//        4
+			//       / \
+			//      2   6
+			//     / \ / \
+			//    1  3 5  7
+			//             \
+			//              8
+			name: "AVL",
+			tree: avl,
+			want: 6,
+		},
+		{
+			name: "RedBlack",
+			tree: &RedBlack[int]{
+				root: &redBlackNode[int]{
+					value: 21,
+					left:  &redBlackNode[int]{value: 1},
+					right: &redBlackNode[int]{
+						value: 42,
+						isRed: true,
+						left:  &redBlackNode[int]{value: 30},
+						right: &redBlackNode[int]{value: 84},
+					},
+				},
+				size: 5,
+			},
+			want: 4,
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.tree.Diameter(); got != test.want {
+			t.Errorf("%s: Diameter() = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTreeWalk(t *testing.T) {
+	// walker is the subset of methods being tested here.
+	type walker interface {
+		Tree[int]
+		Walk(order TraverseOrder, visit func(int) bool) bool
+	}
+
+	trees := []struct {
+		name string
+		tree walker
+	}{
+		{
+			name: "BST",
+			tree: &BST[int]{},
+		},
+		{
+			name: "AVL",
+			tree: &AVL[int]{},
+		},
+		{
+			name: "RedBlack",
+			tree: &RedBlack[int]{},
+		},
+	}
+
+	//          21
+	//        /    \
+	//       1      42
+	//      / \    /  \
+	//   -13  11  30   84
+	//                /
+	//               57
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		order TraverseOrder
+		want  []int
+	}{
+		{
+			order: TraverseInOrder,
+			want:  []int{-13, 1, 11, 21, 30, 42, 57, 84},
+		},
+		{
+			order: TraversePreOrder,
+			want:  []int{21, 1, -13, 11, 42, 30, 84, 57},
+		},
+		{
+			order: TraversePostOrder,
+			want:  []int{-13, 11, 1, 30, 57, 84, 42, 21},
+		},
+		{
+			order: TraverseReverseOrder,
+			want:  []int{84, 57, 42, 30, 21, 11, 1, -13},
+		},
+		{
+			order: TraverseLevelOrder,
+			want:  []int{21, 1, 42, -13, 11, 30, 84, 57},
+		},
+	}
+
+	for _, tt := range trees {
+		if !tt.tree.Walk(TraverseInOrder, func(int) bool {
+			t.Errorf("%s: Walk() on an empty tree visited a value", tt.name)
+			return true
+		}) {
+			t.Errorf("%s: Walk() on an empty tree = false, want true", tt.name)
+		}
+
+		for _, v := range vals {
+			tt.tree.Insert(v)
+		}
+
+		for _, test := range tests {
+			var got []int
+			all := tt.tree.Walk(test.order, func(v int) bool {
+				got = append(got, v)
+				return true
+			})
+			if !all {
+				t.Errorf("%s: Walk(%v) visiting everything = false, want true", tt.name, test.order)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("%s: Walk(%v) values differ (-want +got):\n%s", tt.name, test.order, diff)
+			}
+
+			// Stopping at 30 must not visit anything after it.
+			got = nil
+			all = tt.tree.Walk(test.order, func(v int) bool {
+				got = append(got, v)
+				return v != 30
+			})
+			if all {
+				t.Errorf("%s: Walk(%v) stopping early = true, want false", tt.name, test.order)
+			}
+			want := test.want[:slices.Index(test.want, 30)+1]
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("%s: Walk(%v) stopping at 30 values differ (-want +got):\n%s", tt.name, test.order, diff)
+			}
+		}
+	}
+}
+
+func TestTreeDescending(t *testing.T) {
+	// descender is the subset of methods being tested here.
+	type descender interface {
+		Tree[int]
+		Root() BinaryTree[int]
+		Min() (int, bool)
+		Max() (int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+	}
+
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+	want := []int{84, 57, 42, 30, 21, 11, 1, -13}
+
+	for _, tt := range trees {
+		tree := tt.tree(Descending()).(descender)
+		for _, v := range vals {
+			if !tree.Insert(v) {
+				t.Errorf("%s: Insert(%d) = false, want true", tt.name, v)
+			}
+		}
+
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		if !cmp.Equal(got, want) {
+			t.Errorf("%s: in order values = %v, want %v", tt.name, got, want)
+		}
+
+		for _, v := range vals {
+			if !tree.Search(v) {
+				t.Errorf("%s: Search(%d) = false, want true", tt.name, v)
+			}
+		}
+		for _, v := range []int{-100, 0, 25, 100} {
+			if tree.Search(v) {
+				t.Errorf("%s: Search(%d) = true, want false", tt.name, v)
+			}
+		}
+
+		if got, ok := tree.Min(); got != 84 || !ok {
+			t.Errorf("%s: Min() = %v, %v, want 84, true", tt.name, got, ok)
+		}
+		if got, ok := tree.Max(); got != -13 || !ok {
+			t.Errorf("%s: Max() = %v, %v, want -13, true", tt.name, got, ok)
+		}
+	}
+}
+
+func TestTreeCount(t *testing.T) {
+	// counter is the subset of methods being tested here.
+	type counter interface {
+		Tree[int]
+		Root() BinaryTree[int]
+		Count(v int) int
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	vals := []int{21, 1, 42, 21, -13, 11, 21, 30, 84, 1, 57}
+	counts := map[int]int{21: 3, 1: 2, 42: 1, -13: 1, 11: 1, 30: 1, 84: 1, 57: 1, 0: 0, 100: 0}
+
+	for _, tt := range trees {
+		// By default, duplicates are rejected.
+		tree := tt.tree().(counter)
+		if got := tree.InsertAll(vals...); got != 8 {
+			t.Errorf("%s: InsertAll() = %d, want 8", tt.name, got)
+		}
+		for v, n := range counts {
+			if got, want := tree.Count(v), min(n, 1); got != want {
+				t.Errorf("%s: Count(%d) = %d, want %d", tt.name, v, got, want)
+			}
+		}
+
+		tree = tt.tree(IgnoreDuplicates(false)).(counter)
+		if got := tree.Count(21); got != 0 {
+			t.Errorf("%s: Count(21) on an empty tree = %d, want 0", tt.name, got)
+		}
+		for _, v := range vals {
+			if !tree.Insert(v) {
+				t.Errorf("%s: Insert(%d) = false, want true", tt.name, v)
+			}
+		}
+		// Add more copies in bulk on top of those already there.
+		if got := tree.InsertAll(1, 21, 21, 99); got != 4 {
+			t.Errorf("%s: InsertAll() = %d, want 4", tt.name, got)
+		}
+		if got, want := tree.Size(), len(vals)+4; got != want {
+			t.Errorf("%s: Size() = %d, want %d", tt.name, got, want)
+		}
+
+		counts := maps.Clone(counts)
+		counts[1] += 1
+		counts[21] += 2
+		counts[99] = 1
+		for v, n := range counts {
+			if got := tree.Count(v); got != n {
+				t.Errorf("%s: Count(%d) = %d, want %d", tt.name, v, got, n)
+			}
+			if got := tree.Search(v); got != (n > 0) {
+				t.Errorf("%s: Search(%d) = %v, want %v", tt.name, v, got, n > 0)
+			}
+		}
+
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		want := []int{-13, 1, 1, 1, 11, 21, 21, 21, 21, 21, 30, 42, 57, 84, 99}
+		if !cmp.Equal(got, want) {
+			t.Errorf("%s: in order values = %v, want %v", tt.name, got, want)
+		}
+	}
+
+	// The AVL tree stays balanced with repeated values.
+	tree := NewAVL[int](IgnoreDuplicates(false)).(*AVL[int])
+	for i := 0; i < 200; i++ {
+		tree.Insert(i % 7)
+	}
+	if !IsBalanced[int](tree.Root()) {
+		t.Errorf("AVL with duplicates is not balanced")
+	}
+	if got := tree.Count(3); got != 29 {
+		t.Errorf("AVL Count(3) = %d, want 29", got)
+	}
+}
+
+func TestTreeDeleteOneAll(t *testing.T) {
+	// multiset is the subset of methods being tested here.
+	type multiset interface {
+		Tree[int]
+		Count(v int) int
+		DeleteOne(v int) bool
+		DeleteAll(v int) int
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree(IgnoreDuplicates(false)).(multiset)
+		tree.InsertAll(21, 7, 21, 42, 3, 21)
+
+		if !tree.DeleteOne(21) {
+			t.Errorf("%s: DeleteOne(21) = false, want true", tt.name)
+		}
+		if got := tree.Count(21); got != 2 {
+			t.Errorf("%s: after DeleteOne(21), Count(21) = %d, want 2", tt.name, got)
+		}
+		if got := tree.DeleteAll(21); got != 2 {
+			t.Errorf("%s: DeleteAll(21) = %d, want 2", tt.name, got)
+		}
+		if got := tree.Count(21); got != 0 {
+			t.Errorf("%s: after DeleteAll(21), Count(21) = %d, want 0", tt.name, got)
+		}
+		if got, want := ToSlice[int](tree), []int{3, 7, 42}; !cmp.Equal(got, want) {
+			t.Errorf("%s: after DeleteAll(21), values = %v, want %v", tt.name, got, want)
+		}
+		if got := tree.Size(); got != 3 {
+			t.Errorf("%s: after DeleteAll(21), Size() = %d, want 3", tt.name, got)
+		}
+		if got := tree.DeleteAll(21); got != 0 {
+			t.Errorf("%s: DeleteAll(21) of a missing value = %d, want 0", tt.name, got)
+		}
+	}
+}
+
+func TestTreeCountFunc(t *testing.T) {
+	// counter is the subset of methods being tested here.
+	type counter interface {
+		Tree[int]
+		CountFunc(pred func(int) bool) int
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		name string
+		pred func(int) bool
+		want int
+	}{
+		{
+			name: "negative",
+			pred: func(v int) bool { return v < 0 },
+			want: 1,
+		},
+		{
+			name: "in [10, 50]",
+			pred: func(v int) bool { return v >= 10 && v <= 50 },
+			want: 4,
+		},
+		{
+			name: "never",
+			pred: func(int) bool { return false },
+			want: 0,
+		},
+		{
+			name: "always",
+			pred: func(int) bool { return true },
+			want: len(vals),
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(counter)
+		if got := tree.CountFunc(func(int) bool { return true }); got != 0 {
+			t.Errorf("%s: CountFunc() on an empty tree = %d, want 0", tt.name, got)
+		}
+
+		tree.InsertAll(vals...)
+		for _, test := range tests {
+			if got := tree.CountFunc(test.pred); got != test.want {
+				t.Errorf("%s: CountFunc(%s) = %d, want %d", tt.name, test.name, got, test.want)
+			}
+		}
+		if got := tree.CountFunc(func(int) bool { return true }); got != tree.Size() {
+			t.Errorf("%s: CountFunc(always) = %d, want Size() = %d", tt.name, got, tree.Size())
+		}
+	}
+}
+
+func TestTreeInsertDeleteErr(t *testing.T) {
+	// errTree is the subset of methods being tested here.
+	type errTree interface {
+		Tree[int]
+		InsertErr(v int) error
+		DeleteErr(v int) error
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+
+		// canDelete is set for trees whose nodes support removing values.
+		canDelete bool
+	}{
+		{
+			name:      "BST",
+			tree:      NewBST[int],
+			canDelete: true,
+		},
+		{
+			name:      "AVL",
+			tree:      NewAVL[int],
+			canDelete: true,
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name:      "Treap",
+			tree:      NewTreap[int],
+			canDelete: true,
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(errTree)
+
+		if err := tree.DeleteErr(21); !errors.Is(err, ErrEmptyTree) {
+			t.Errorf("%s: DeleteErr(21) on an empty tree = %v, want %v", tt.name, err, ErrEmptyTree)
+		}
+
+		for _, v := range []int{21, 1, 42} {
+			if err := tree.InsertErr(v); err != nil {
+				t.Errorf("%s: InsertErr(%d) = %v, want nil", tt.name, v, err)
+			}
+		}
+		if err := tree.InsertErr(42); !errors.Is(err, ErrDuplicate) {
+			t.Errorf("%s: InsertErr(42) again = %v, want %v", tt.name, err, ErrDuplicate)
+		}
+		if tree.Insert(42) {
+			t.Errorf("%s: Insert(42) again = true, want false", tt.name)
+		}
+		if got := tree.Size(); got != 3 {
+			t.Errorf("%s: Size() = %d, want 3", tt.name, got)
+		}
+
+		if err := tree.DeleteErr(7); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: DeleteErr(7) = %v, want %v", tt.name, err, ErrNotFound)
+		}
+		if tree.Delete(7) {
+			t.Errorf("%s: Delete(7) = true, want false", tt.name)
+		}
+
+		err := tree.DeleteErr(1)
+		switch {
+		case tt.canDelete && err != nil:
+			t.Errorf("%s: DeleteErr(1) = %v, want nil", tt.name, err)
+		case !tt.canDelete && !errors.Is(err, errors.ErrUnsupported):
+			t.Errorf("%s: DeleteErr(1) = %v, want %v", tt.name, err, errors.ErrUnsupported)
+		}
+		want := 3
+		if tt.canDelete {
+			want = 2
+		}
+		if tree.Size() != want {
+			t.Errorf("%s: Size() after DeleteErr(1) = %d, want %d", tt.name, tree.Size(), want)
+		}
+
+		// Trees keeping duplicates accept the same value again.
+		multi := tt.tree(IgnoreDuplicates(false)).(errTree)
+		for i := 0; i < 2; i++ {
+			if err := multi.InsertErr(42); err != nil {
+				t.Errorf("%s: InsertErr(42) into a multiset = %v, want nil", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestTreeIsEmpty(t *testing.T) {
+	trees := []struct {
+		name string
+		tree Tree[int]
+	}{
+		{name: "BST", tree: NewBST[int]()},
+		{name: "AVL", tree: NewAVL[int]()},
+		{name: "RedBlack", tree: NewRedBlack[int]()},
+		{name: "Treap", tree: NewTreap[int]()},
+		{name: "SyncTree", tree: NewSyncTree(NewBST[int]())},
+	}
+
+	for _, tt := range trees {
+		if !tt.tree.IsEmpty() {
+			t.Errorf("%s: new tree IsEmpty() = false, want true", tt.name)
+		}
+		// Traversing an empty tree emits nothing and closes the channel.
+		for v := range tt.tree.Traverse(TraverseInOrder) {
+			t.Errorf("%s: Traverse() of an empty tree emitted %d", tt.name, v)
+		}
+
+		tt.tree.InsertAll(21, 1, 42)
+		if tt.tree.IsEmpty() {
+			t.Errorf("%s: IsEmpty() after InsertAll = true, want false", tt.name)
+		}
+		var got []int
+		for v := range tt.tree.Traverse(TraverseInOrder) {
+			got = append(got, v)
+		}
+		if want := []int{1, 21, 42}; !cmp.Equal(got, want) {
+			t.Errorf("%s: Traverse() = %v, want %v", tt.name, got, want)
+		}
+	}
+
+	// Nodes are only empty when nil.
+	if !(*bstNode[int])(nil).IsEmpty() {
+		t.Errorf("nil node IsEmpty() = false, want true")
+	}
+	if (&bstNode[int]{value: 1}).IsEmpty() {
+		t.Errorf("node IsEmpty() = true, want false")
+	}
+
+	// Deleting every value leaves a tree empty again.
+	treap := NewTreap[int]()
+	treap.InsertAll(3, 1, 2)
+	for _, v := range []int{1, 2, 3} {
+		treap.Delete(v)
+	}
+	if !treap.IsEmpty() {
+		t.Errorf("Treap IsEmpty() after deleting every value = false, want true")
+	}
+
+	ternary := NewTernary()
+	if !ternary.IsEmpty() {
+		t.Errorf("new Ternary IsEmpty() = false, want true")
+	}
+	ternary.InsertAll("cat", "car")
+	if ternary.IsEmpty() {
+		t.Errorf("Ternary IsEmpty() after InsertAll = true, want false")
+	}
+	ternary.Delete("cat")
+	ternary.Delete("car")
+	if !ternary.IsEmpty() {
+		t.Errorf("Ternary IsEmpty() after deleting every word = false, want true")
+	}
+}
+
+func TestTreeString(t *testing.T) {
+	avl := NewAVL[int]()
+	for _, v := range []int{21, 1, 42, 84} {
+		avl.Insert(v)
+	}
+	rb := NewRedBlack[int]()
+	rb.InsertAll(21, 1, 42)
+
+	tests := []struct {
+		name string
+		tree fmt.Stringer
+		want string
+	}{
+		{
+			name: "empty BST",
+			tree: &BST[int]{},
+			want: "(empty)",
+		},
+		{
+			name: "BST",
+			tree: newBSTWith(21, 1, 42, -13, 30),
+			want: "21\n" +
+				"  L: 1\n" +
+				"    L: -13\n" +
+				"  R: 42\n" +
+				"    L: 30",
+		},
+		{
+			name: "AVL",
+			tree: avl.(fmt.Stringer),
+			want: "21 (BF: 1)\n" +
+				"  L: 1 (BF: 0)\n" +
+				"  R: 42 (BF: 1)\n" +
+				"    R: 84 (BF: 0)",
+		},
+		{
+			name: "RedBlack",
+			tree: rb.(fmt.Stringer),
+			want: "21 (Black)\n" +
+				"  L: 1 (Black)\n" +
+				"  R: 42 (Black)",
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.tree.String(); got != test.want {
+			t.Errorf("%s: String() = %q, want %q", test.name, got, test.want)
+		}
+		if got := fmt.Sprintf("%v", test.tree); got != test.want {
+			t.Errorf("%s: Sprintf(%%v) = %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	// Large trees are cut short.
+	vals := make([]int, 1000)
+	for i := range vals {
+		vals[i] = i
+	}
+	big := NewAVL[int]()
+	big.InsertAll(vals...)
+	got := fmt.Sprint(big)
+	lines := strings.Split(got, "\n")
+	if len(lines) != maxStringNodes+1 {
+		t.Errorf("String() of a %d value tree has %d lines, want %d", len(vals), len(lines), maxStringNodes+1)
+	}
+	if want := fmt.Sprintf("... %d more values", len(vals)-maxStringNodes); lines[len(lines)-1] != want {
+		t.Errorf("String() of a %d value tree ends with %q, want %q", len(vals), lines[len(lines)-1], want)
+	}
+}
+
+func TestTreeAppendValues(t *testing.T) {
+	// appender is the subset of methods being tested here.
+	type appender interface {
+		Tree[int]
+		AppendValues(dst []int, tOrder TraverseOrder) []int
+	}
+
+	bst := NewBST[int]().(appender)
+	bst.InsertAll(21, 1, 42)
+	avl := NewAVL[int]().(appender)
+	avl.InsertAll(7, 3, 9)
+	rb := NewRedBlack[int]().(appender)
+	rb.InsertAll(-1, -2)
+	treap := NewTreap[int]().(appender)
+	treap.InsertAll(100)
+
+	// One slice is reused across all the trees.
+	buf := make([]int, 0, 16)
+	for _, tree := range []appender{bst, avl, &BST[int]{}, rb, treap} {
+		buf = tree.AppendValues(buf, TraverseInOrder)
+	}
+	want := []int{1, 21, 42, 3, 7, 9, -2, -1, 100}
+	if !cmp.Equal(buf, want) {
+		t.Errorf("AppendValues() across trees = %v, want %v", buf, want)
+	}
+	if cap(buf) != 16 {
+		t.Errorf("AppendValues() grew the slice to cap %d, want it reused at cap 16", cap(buf))
+	}
+
+	// Starting over reuses the same backing array.
+	buf = bst.AppendValues(buf[:0], TraverseReverseOrder)
+	if want := []int{42, 21, 1}; !cmp.Equal(buf, want) {
+		t.Errorf("AppendValues(TraverseReverseOrder) = %v, want %v", buf, want)
+	}
+	buf = bst.AppendValues(buf[:0], TraversePreOrder)
+	if want := []int{21, 1, 42}; !cmp.Equal(buf, want) {
+		t.Errorf("AppendValues(TraversePreOrder) = %v, want %v", buf, want)
+	}
+
+	// A nil slice works just like append.
+	if got := avl.AppendValues(nil, TraverseInOrder); !cmp.Equal(got, []int{3, 7, 9}) {
+		t.Errorf("AppendValues(nil) = %v, want [3 7 9]", got)
+	}
+}
+
+func TestTreeBoundary(t *testing.T) {
+	// boundaryTree is the subset of methods being tested here.
+	type boundaryTree interface {
+		Tree[int]
+		Boundary() []int
+	}
+
+	tests := []struct {
+		name string
+		vals []int
+		want []int
+	}{
+		{
+			name: "empty",
+			want: nil,
+		},
+		{
+			name: "single node",
+			vals: []int{42},
+			want: []int{42},
+		},
+		{
+			//	    4
+			//	  /   \
+			//	 2     6
+			//	/ \   / \
+			//	1 3   5   7
+			name: "full",
+			vals: []int{4, 2, 6, 1, 3, 5, 7},
+			want: []int{4, 2, 1, 3, 5, 7, 6},
+		},
+		{
+			//	      20
+			//	    /    \
+			//	   8      22
+			//	  / \       \
+			//	 4   12      25
+			//	    /  \
+			//	   10   14
+			name: "uneven",
+			vals: []int{20, 8, 22, 4, 12, 25, 10, 14},
+			want: []int{20, 8, 4, 10, 14, 25, 22},
+		},
+		{
+			// The left boundary takes a right child when there is no
+			// left one.
+			name: "left zigzag",
+			vals: []int{5, 1, 3, 2},
+			want: []int{5, 1, 3, 2},
+		},
+		{
+			// The whole tree is the left boundary, ending at its leaf.
+			name: "left only",
+			vals: []int{4, 3, 2, 1},
+			want: []int{4, 3, 2, 1},
+		},
+		{
+			// The right boundary comes last, from the bottom up.
+			name: "right only",
+			vals: []int{1, 2, 3, 4},
+			want: []int{1, 4, 3, 2},
+		},
+	}
+
+	for _, test := range tests {
+		tree := NewBST[int]().(boundaryTree)
+		tree.InsertAll(test.vals...)
+		if got := tree.Boundary(); !cmp.Equal(got, test.want) {
+			t.Errorf("%s: Boundary() = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	// The balanced trees build the full tree into the same shape as the BST.
+	for _, tree := range []boundaryTree{
+		NewAVL[int]().(boundaryTree),
+		NewRedBlack[int]().(boundaryTree),
+	} {
+		tree.InsertAll(4, 2, 6, 1, 3, 5, 7)
+		if got, want := tree.Boundary(), []int{4, 2, 1, 3, 5, 7, 6}; !cmp.Equal(got, want) {
+			t.Errorf("%T.Boundary() = %v, want %v", tree, got, want)
+		}
+	}
+
+	treap := NewTreap[int]().(boundaryTree)
+	if got := treap.Boundary(); got != nil {
+		t.Errorf("empty Treap Boundary() = %v, want nil", got)
+	}
+	treap.Insert(42)
+	if got := treap.Boundary(); !cmp.Equal(got, []int{42}) {
+		t.Errorf("Treap Boundary() = %v, want [42]", got)
+	}
+}
+
+func TestTreeSelectDescending(t *testing.T) {
+	// selecter is the subset of methods being tested here.
+	type selecter interface {
+		Tree[int]
+		SelectDescending(k int) (int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+	want := slices.Clone(vals)
+	slices.Sort(want)
+	slices.Reverse(want)
+
+	for _, tt := range trees {
+		tree := tt.tree().(selecter)
+		if _, ok := tree.SelectDescending(0); ok {
+			t.Errorf("%s: SelectDescending(0) on an empty tree = _, true, want false", tt.name)
+		}
+
+		tree.InsertAll(vals...)
+		for k, w := range want {
+			if got, ok := tree.SelectDescending(k); got != w || !ok {
+				t.Errorf("%s: SelectDescending(%d) = %v, %v, want %v, true", tt.name, k, got, ok, w)
+			}
+		}
+		for _, k := range []int{-1, len(vals), len(vals) + 10} {
+			if _, ok := tree.SelectDescending(k); ok {
+				t.Errorf("%s: SelectDescending(%d) = _, true, want false", tt.name, k)
+			}
+		}
+
+		// Descending trees count from the smallest.
+		desc := tt.tree(Descending()).(selecter)
+		desc.InsertAll(vals...)
+		if got, ok := desc.SelectDescending(0); got != -13 || !ok {
+			t.Errorf("%s: Descending SelectDescending(0) = %v, %v, want -13, true", tt.name, got, ok)
+		}
+	}
+}
+
+func TestTreeAt(t *testing.T) {
+	// indexer is the subset of methods being tested here.
+	type indexer interface {
+		Tree[int]
+		At(index int) (int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+	want := slices.Clone(vals)
+	slices.Sort(want)
+
+	for _, tt := range trees {
+		tree := tt.tree().(indexer)
+		if _, ok := tree.At(0); ok {
+			t.Errorf("%s: At(0) on an empty tree = _, true, want false", tt.name)
+		}
+
+		tree.InsertAll(vals...)
+		if got, ok := tree.At(0); got != -13 || !ok {
+			t.Errorf("%s: At(0) = %v, %v, want the minimum -13, true", tt.name, got, ok)
+		}
+		if got, ok := tree.At(tree.Size() - 1); got != 84 || !ok {
+			t.Errorf("%s: At(Size()-1) = %v, %v, want the maximum 84, true", tt.name, got, ok)
+		}
+		for i, w := range want {
+			if got, ok := tree.At(i); got != w || !ok {
+				t.Errorf("%s: At(%d) = %v, %v, want %v, true", tt.name, i, got, ok, w)
+			}
+		}
+		for _, i := range []int{-1, len(vals), len(vals) + 10} {
+			if _, ok := tree.At(i); ok {
+				t.Errorf("%s: At(%d) = _, true, want false", tt.name, i)
+			}
+		}
+
+		// Descending trees start from the largest.
+		desc := tt.tree(Descending()).(indexer)
+		desc.InsertAll(vals...)
+		if got, ok := desc.At(0); got != 84 || !ok {
+			t.Errorf("%s: Descending At(0) = %v, %v, want 84, true", tt.name, got, ok)
+		}
+	}
+}
+
+func TestTreeMinDepth(t *testing.T) {
+	// minDepther is the subset of methods being tested here.
+	type minDepther interface {
+		Tree[int]
+		MinDepth() int
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(minDepther)
+		if got := tree.MinDepth(); got != 0 {
+			t.Errorf("%s: MinDepth() of an empty tree = %d, want 0", tt.name, got)
+		}
+		tree.Insert(42)
+		if got := tree.MinDepth(); got != 0 {
+			t.Errorf("%s: MinDepth() of a single node = %d, want 0", tt.name, got)
+		}
+
+		// The nearest leaf is never further than the farthest.
+		for v := 0; v < 100; v++ {
+			tree.Insert(v)
+		}
+		if got, height := tree.MinDepth(), tree.Height(); got < 1 || got > height-1 {
+			t.Errorf("%s: MinDepth() = %d, want between 1 and Height()-1 = %d", tt.name, got, height-1)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		vals       []int
+		wantMin    int
+		wantHeight int
+	}{
+		{
+			// Every leaf is on the bottom level, so the nearest leaf is as
+			// far as the farthest, counted in edges rather than nodes.
+			name:       "balanced",
+			vals:       []int{4, 2, 6, 1, 3, 5, 7},
+			wantMin:    2,
+			wantHeight: 3,
+		},
+		{
+			name:       "nearly balanced",
+			vals:       []int{4, 2, 6, 1, 3},
+			wantMin:    1,
+			wantHeight: 3,
+		},
+		{
+			// One leaf hangs right off the root, with a long chain opposite.
+			name:       "skewed",
+			vals:       []int{2, 1, 3, 4, 5, 6, 7, 8, 9, 10},
+			wantMin:    1,
+			wantHeight: 9,
+		},
+		{
+			name:       "chain",
+			vals:       []int{1, 2, 3, 4, 5},
+			wantMin:    4,
+			wantHeight: 5,
+		},
+	}
+
+	for _, test := range tests {
+		tree := NewBSTFromLevelOrder(test.vals)
+		if got := tree.MinDepth(); got != test.wantMin {
+			t.Errorf("%s: MinDepth() = %d, want %d", test.name, got, test.wantMin)
+		}
+		if got := tree.Height(); got != test.wantHeight {
+			t.Errorf("%s: Height() = %d, want %d", test.name, got, test.wantHeight)
+		}
+	}
+}
+
+func TestTreeBalanceInfo(t *testing.T) {
+	// balanceInfoer is the subset of methods being tested here.
+	type balanceInfoer interface {
+		Tree[int]
+		BalanceInfo() BalanceInfo
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(balanceInfoer)
+		if got, want := tree.BalanceInfo(), (BalanceInfo{}); got != want {
+			t.Errorf("%s: BalanceInfo() of an empty tree = %+v, want %+v", tt.name, got, want)
+		}
+
+		// A perfect tree has a ratio of exactly 1.
+		tree.InsertAll(4, 2, 6, 1, 3, 5, 7)
+		want := BalanceInfo{Height: 3, MinDepth: 2, Size: 7, SkewRatio: 1}
+		if tt.name == "Treap" {
+			// The shape depends on the priorities drawn.
+			want = tree.BalanceInfo()
+		}
+		if got := tree.BalanceInfo(); got != want {
+			t.Errorf("%s: BalanceInfo() of a perfect tree = %+v, want %+v", tt.name, got, want)
+		}
+	}
+
+	const n = 1000
+
+	// Sorted inserts keep an AVL tree close to balanced.
+	avl := NewAVL[int]().(*AVL[int])
+	for v := 0; v < n; v++ {
+		avl.Insert(v)
+	}
+	info := avl.BalanceInfo()
+	if info.Size != n || info.Height != avl.Height() || info.MinDepth != avl.MinDepth() {
+		t.Errorf("AVL BalanceInfo() = %+v, want Size %d, Height %d, MinDepth %d", info, n, avl.Height(), avl.MinDepth())
+	}
+	if info.SkewRatio < 1 || info.SkewRatio > 1.1 {
+		t.Errorf("AVL BalanceInfo().SkewRatio = %v, want about 1", info.SkewRatio)
+	}
+
+	// but leave a plain BST as one long chain.
+	bst := &BST[int]{}
+	for v := 0; v < n; v++ {
+		bst.Insert(v)
+	}
+	info = bst.BalanceInfo()
+	if info.Size != n || info.Height != n || info.MinDepth != n-1 {
+		t.Errorf("BST BalanceInfo() = %+v, want Size %d, Height %d, MinDepth %d", info, n, n, n-1)
+	}
+	if want := float64(n) / math.Log2(n+1); math.Abs(info.SkewRatio-want) > 1e-9 {
+		t.Errorf("BST BalanceInfo().SkewRatio = %v, want %v", info.SkewRatio, want)
+	}
+}
+
+func TestTreeMorrisInOrder(t *testing.T) {
+	// morriser is the subset of methods being tested here.
+	type morriser interface {
+		RootedTree[int]
+		MorrisInOrder() []int
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	random := make([]int, 500)
+	for i := range random {
+		random[i] = r.Intn(10000)
+	}
+
+	for _, tt := range trees {
+		for _, vals := range [][]int{
+			nil,
+			{42},
+			{21, 1, 42, -13, 11, 30, 84, 57},
+			random,
+		} {
+			tree := tt.tree().(morriser)
+			for _, v := range vals {
+				tree.Insert(v)
+			}
+			before := binaryTreeStructure(tree.Root())
+
+			got := tree.MorrisInOrder()
+			want := ToSlice[int](tree)
+			if !cmp.Equal(got, want, cmpopts.EquateEmpty()) {
+				t.Errorf("%s: MorrisInOrder() of %d values = %v, want %v", tt.name, len(vals), got, want)
+			}
+			if after := binaryTreeStructure(tree.Root()); !cmp.Equal(after, before) {
+				t.Errorf("%s: MorrisInOrder() of %d values changed the tree structure", tt.name, len(vals))
+			}
+		}
+	}
+
+	// A descending tree gives its own order.
+	tree := NewBST[int](Descending()).(morriser)
+	tree.InsertAll(3, 1, 2)
+	if got, want := tree.MorrisInOrder(), []int{3, 2, 1}; !cmp.Equal(got, want) {
+		t.Errorf("Descending MorrisInOrder() = %v, want %v", got, want)
+	}
+}
+
+func TestTreeLeafAndInternalCount(t *testing.T) {
+	// nodeCounter is the subset of methods being tested here.
+	type nodeCounter interface {
+		Tree[int]
+		LeafCount() int
+		InternalCount() int
+	}
+
+	tests := []struct {
+		name         string
+		vals         []int
+		wantLeaf     int
+		wantInternal int
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "single node",
+			vals:     []int{42},
+			wantLeaf: 1,
+		},
+		{
+			name:         "balanced",
+			vals:         []int{4, 2, 6, 1, 3, 5, 7},
+			wantLeaf:     4,
+			wantInternal: 3,
+		},
+		{
+			name:         "degenerate chain",
+			vals:         []int{1, 2, 3, 4, 5},
+			wantLeaf:     1,
+			wantInternal: 4,
+		},
+	}
+
+	for _, test := range tests {
+		tree := NewBST[int]().(nodeCounter)
+		tree.InsertAll(test.vals...)
+		if got := tree.LeafCount(); got != test.wantLeaf {
+			t.Errorf("%s: LeafCount() = %d, want %d", test.name, got, test.wantLeaf)
+		}
+		if got := tree.InternalCount(); got != test.wantInternal {
+			t.Errorf("%s: InternalCount() = %d, want %d", test.name, got, test.wantInternal)
+		}
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	// Whatever shape the trees take, every node is one or the other.
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+	for _, tt := range trees {
+		tree := tt.tree().(nodeCounter)
+		tree.InsertAll(vals...)
+		leaves, internal := tree.LeafCount(), tree.InternalCount()
+		if leaves+internal != tree.Size() {
+			t.Errorf("%s: LeafCount() + InternalCount() = %d + %d, want Size() %d",
+				tt.name, leaves, internal, tree.Size())
+		}
+		if leaves == 0 || internal == 0 {
+			t.Errorf("%s: LeafCount(), InternalCount() = %d, %d, want both above 0",
+				tt.name, leaves, internal)
+		}
+	}
+}
+
+func TestTreeFuncStruct(t *testing.T) {
+	type employee struct {
+		id   int
+		name string
+	}
+	byID := func(a, b employee) int {
+		return a.id - b.id
+	}
+
+	// funcTree is the subset of methods being tested here.
+	type funcTree interface {
+		Tree[employee]
+		Root() BinaryTree[employee]
+		Min() (employee, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree Tree[employee]
+	}{
+		{
+			name: "BST",
+			tree: NewBSTFunc(byID),
+		},
+		{
+			name: "AVL",
+			tree: NewAVLFunc(byID),
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlackFunc(byID),
+		},
+	}
+
+	vals := []employee{
+		{id: 42, name: "Ada"},
+		{id: 7, name: "Grace"},
+		{id: 19, name: "Edsger"},
+		{id: 88, name: "Barbara"},
+		{id: 3, name: "Alan"},
+	}
+	want := []string{"Alan", "Grace", "Edsger", "Ada", "Barbara"}
+
+	for _, tt := range trees {
+		tree := tt.tree.(funcTree)
+		for _, v := range vals {
+			if !tree.Insert(v) {
+				t.Errorf("%s: Insert(%v) = false, want true", tt.name, v)
+			}
+		}
+
+		// Only the id is compared, so a different name is still a duplicate.
+		if tree.Insert(employee{id: 19, name: "Donald"}) {
+			t.Errorf("%s: Insert of a duplicate id = true, want false", tt.name)
+		}
+		if got := tree.Size(); got != len(vals) {
+			t.Errorf("%s: Size() = %d, want %d", tt.name, got, len(vals))
+		}
+
+		var vs []employee
+		InOrderInto(tree.Root(), &vs)
+		var got []string
+		for _, v := range vs {
+			got = append(got, v.name)
+		}
+		if !cmp.Equal(got, want) {
+			t.Errorf("%s: in order names = %v, want %v", tt.name, got, want)
+		}
+
+		if !tree.Search(employee{id: 88}) {
+			t.Errorf("%s: Search(id 88) = false, want true", tt.name)
+		}
+		if tree.Search(employee{id: 50}) {
+			t.Errorf("%s: Search(id 50) = true, want false", tt.name)
+		}
+
+		if got, ok := tree.Min(); got.name != "Alan" || !ok {
+			t.Errorf("%s: Min() = %v, %v, want Alan, true", tt.name, got, ok)
+		}
+	}
+
+	// Get looks up the full record by its key.
+	for _, tree := range []interface {
+		Get(employee) (employee, bool)
+	}{
+		NewBSTFunc(byID).(*BST[employee]),
+		NewAVLFunc(byID).(*AVL[employee]),
+	} {
+		for _, v := range vals {
+			tree.(Tree[employee]).Insert(v)
+		}
+		if got, ok := tree.Get(employee{id: 7}); got.name != "Grace" || !ok {
+			t.Errorf("%T: Get(id 7) = %v, %v, want Grace, true", tree, got, ok)
+		}
+	}
+}
+
+func TestTreeFuncReverseInts(t *testing.T) {
+	reverse := func(a, b int) int {
+		switch {
+		case a > b:
+			return -1
+		case a < b:
+			return 1
+		}
+		return 0
+	}
+
+	// funcTree is the subset of methods being tested here.
+	type funcTree interface {
+		Tree[int]
+		Root() BinaryTree[int]
+		Min() (int, bool)
+		Max() (int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBSTFunc(reverse),
+		},
+		{
+			name: "AVL",
+			tree: NewAVLFunc(reverse),
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlackFunc(reverse),
+		},
+	}
+
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+	want := []int{84, 57, 42, 30, 21, 11, 1, -13}
+
+	for _, tt := range trees {
+		tree := tt.tree.(funcTree)
+		for _, v := range vals {
+			if !tree.Insert(v) {
+				t.Errorf("%s: Insert(%d) = false, want true", tt.name, v)
+			}
+		}
+
+		var got []int
+		InOrderInto(tree.Root(), &got)
+		if !cmp.Equal(got, want) {
+			t.Errorf("%s: in order values = %v, want %v", tt.name, got, want)
+		}
+
+		for _, v := range vals {
+			if !tree.Search(v) {
+				t.Errorf("%s: Search(%d) = false, want true", tt.name, v)
+			}
+		}
+		if tree.Search(25) {
+			t.Errorf("%s: Search(25) = true, want false", tt.name)
+		}
+
+		if got, ok := tree.Min(); got != 84 || !ok {
+			t.Errorf("%s: Min() = %v, %v, want 84, true", tt.name, got, ok)
+		}
+		if got, ok := tree.Max(); got != -13 || !ok {
+			t.Errorf("%s: Max() = %v, %v, want -13, true", tt.name, got, ok)
+		}
+	}
+
+	// Ranges follow the comparator, so lo is the larger value.
+	tree := NewAVLFunc(reverse).(*AVL[int])
+	for _, v := range vals {
+		tree.Insert(v)
+	}
+	if got, want := tree.RangeSearch(30, 11), []int{30, 21, 11}; !cmp.Equal(got, want) {
+		t.Errorf("RangeSearch(30, 11) = %v, want %v", got, want)
+	}
+	if got := tree.Rank(11); got != 5 {
+		t.Errorf("Rank(11) = %d, want 5", got)
+	}
+}
+
+func TestTreeGet(t *testing.T) {
+	// getter is the subset of methods being tested here.
+	type getter interface {
+		Tree[int]
+		Get(v int) (int, bool)
+	}
+
+	trees := []struct {
+		name string
+		tree newTreeFunc[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTTree[int],
+		},
+		{
+			name: "AVL",
+			tree: newAVLTree[int],
+		},
+	}
+
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	for _, tt := range trees {
+		tree := tt.tree().(getter)
+		if got, ok := tree.Get(21); ok {
+			t.Errorf("%s: Get(21) on an empty tree = %v, true, want false", tt.name, got)
+		}
+
+		for _, v := range vals {
+			tree.Insert(v)
+		}
+
+		for _, v := range vals {
+			if got, ok := tree.Get(v); got != v || !ok {
+				t.Errorf("%s: Get(%d) = %v, %v, want %v, true", tt.name, v, got, ok, v)
+			}
+		}
+		for _, v := range []int{-100, 0, 25, 100} {
+			if got, ok := tree.Get(v); got != 0 || ok {
+				t.Errorf("%s: Get(%d) = %v, %v, want 0, false", tt.name, v, got, ok)
+			}
+		}
+	}
+
+	// Positive and negative zero are equal but distinct, so Get should hand
+	// back the one actually stored rather than the key asked for.
+	negZero := math.Copysign(0, -1)
+	for _, tree := range []interface {
+		Tree[float64]
+		Get(v float64) (float64, bool)
+	}{&BST[float64]{}, &AVL[float64]{}} {
+		tree.Insert(1.5)
+		tree.Insert(0)
+		tree.Insert(-1.5)
+		got, ok := tree.Get(negZero)
+		if !ok || math.Signbit(got) {
+			t.Errorf("%T: Get(-0) = %v, %v, want the stored +0, true", tree, got, ok)
+		}
+	}
+}
+
+func TestTreeRangeSearch(t *testing.T) {
+	// ranger is the subset of methods being tested here.
+	type ranger interface {
+		Tree[int]
+		RangeSearch(lo, hi int) []int
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+		opts []treeOptionFunc
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "BST Descending",
+			tree: NewBST[int],
+			opts: []treeOptionFunc{Descending()},
+		},
+	}
+
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		lo, hi int
+		want   []int
+	}{
+		{
+			// Empty range between values.
+			lo:   22,
+			hi:   29,
+			want: nil,
+		},
+		{
+			// Inverted range.
+			lo:   42,
+			hi:   1,
+			want: nil,
+		},
+		{
+			// Entirely outside the tree.
+			lo:   100,
+			hi:   200,
+			want: nil,
+		},
+		{
+			// Exactly one value.
+			lo:   30,
+			hi:   30,
+			want: []int{30},
+		},
+		{
+			lo:   0,
+			hi:   42,
+			want: []int{1, 11, 21, 30, 42},
+		},
+		{
+			// Spanning the whole tree.
+			lo:   -100,
+			hi:   100,
+			want: []int{-13, 1, 11, 21, 30, 42, 57, 84},
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree(tt.opts...).(ranger)
+		for _, v := range vals {
+			tree.Insert(v)
+		}
+
+		for _, test := range tests {
+			want := test.want
+			if len(tt.opts) > 0 {
+				want = slices.Clone(want)
+				slices.Reverse(want)
+			}
+			if got := tree.RangeSearch(test.lo, test.hi); !cmp.Equal(got, want) {
+				t.Errorf("%s: RangeSearch(%d, %d) = %v, want %v",
+					tt.name, test.lo, test.hi, got, want)
+			}
+		}
+	}
+}
+
+func TestTreeSameSubtree(t *testing.T) {
+	// subtreeTree is the subset of methods being tested here.
+	type subtreeTree interface {
+		Tree[int]
+		SameSubtree(a, b int) bool
+	}
+
+	trees := []struct {
+		name string
+		tree newTreeFunc[int]
+	}{
+		{
+			name: "BST",
+			tree: newBSTTree[int],
+		},
+		{
+			name: "AVL",
+			tree: newAVLTree[int],
+		},
+	}
+
+	//          21
+	//        /    \
+	//       1      42
+	//      / \    /  \
+	//   -13  11  30   84
+	//                /
+	//               57
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		a, b int
+		want bool
+	}{
+		// Both on the left.
+		{a: -13, b: 11, want: true},
+		{a: 1, b: -13, want: true},
+		// Both on the right, at different depths.
+		{a: 30, b: 57, want: true},
+		// A value is in the same subtree as itself.
+		{a: 84, b: 84, want: true},
+		// Opposite sides.
+		{a: 11, b: 30, want: false},
+		{a: 1, b: 42, want: false},
+		// The root is in neither subtree.
+		{a: 21, b: 11, want: false},
+		{a: 42, b: 21, want: false},
+		{a: 21, b: 21, want: false},
+		// Missing values.
+		{a: 12, b: 11, want: false},
+		{a: 57, b: 100, want: false},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(subtreeTree)
+
+		if tree.SameSubtree(1, 2) {
+			t.Errorf("%s: SameSubtree(1, 2) on an empty tree = true, want false", tt.name)
+		}
+
+		for _, v := range vals {
+			tree.Insert(v)
+		}
+		for _, test := range tests {
+			if got := tree.SameSubtree(test.a, test.b); got != test.want {
+				t.Errorf("%s: SameSubtree(%d, %d) = %v, want %v", tt.name, test.a, test.b, got, test.want)
+			}
+		}
+	}
+}
+
+func TestTreeFrom(t *testing.T) {
+	// fromer is the subset of methods being tested here.
+	type fromer interface {
+		Tree[int]
+		From(v int, tOrder TraverseOrder) func(yield func(int) bool)
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+	}
+
+	vals := []int{21, 1, 42, -13, 11, 30, 84, 57}
+
+	tests := []struct {
+		v     int
+		order TraverseOrder
+		want  []int
+	}{
+		{
+			// Starting mid-tree at a value in the tree.
+			v:     21,
+			order: TraverseInOrder,
+			want:  []int{21, 30, 42, 57, 84},
+		},
+		{
+			// Starting between values.
+			v:     25,
+			order: TraverseInOrder,
+			want:  []int{30, 42, 57, 84},
+		},
+		{
+			// Starting before the minimum yields everything.
+			v:     -100,
+			order: TraverseInOrder,
+			want:  []int{-13, 1, 11, 21, 30, 42, 57, 84},
+		},
+		{
+			// Starting past the maximum yields nothing.
+			v:     100,
+			order: TraverseInOrder,
+			want:  nil,
+		},
+		{
+			v:     25,
+			order: TraverseReverseOrder,
+			want:  []int{21, 11, 1, -13},
+		},
+		{
+			v:     42,
+			order: TraverseReverseOrder,
+			want:  []int{42, 30, 21, 11, 1, -13},
+		},
+		{
+			v:     100,
+			order: TraverseReverseOrder,
+			want:  []int{84, 57, 42, 30, 21, 11, 1, -13},
+		},
+		{
+			v:     -100,
+			order: TraverseReverseOrder,
+			want:  nil,
+		},
+		{
+			// Orders without a position to start from.
+			v:     21,
+			order: TraversePreOrder,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(fromer)
+		tree.From(0, TraverseInOrder)(func(v int) bool {
+			t.Errorf("%s: From() on an empty tree yielded %d", tt.name, v)
+			return true
+		})
+
+		tree.InsertAll(vals...)
+		for _, test := range tests {
+			var got []int
+			tree.From(test.v, test.order)(func(v int) bool {
+				got = append(got, v)
+				return true
+			})
+			if !cmp.Equal(got, test.want) {
+				t.Errorf("%s: From(%d, %v) = %v, want %v", tt.name, test.v, test.order, got, test.want)
+			}
+		}
+
+		// Iteration stops as soon as yield returns false.
+		var got []int
+		tree.From(10, TraverseInOrder)(func(v int) bool {
+			got = append(got, v)
+			return len(got) < 2
+		})
+		if want := []int{11, 21}; !cmp.Equal(got, want) {
+			t.Errorf("%s: From(10) stopped after two = %v, want %v", tt.name, got, want)
+		}
+	}
+
+	// A descending tree starts at the first value not before v in its order.
+	desc := NewAVL[int](Descending()).(fromer)
+	desc.InsertAll(vals...)
+	var got []int
+	desc.From(25, TraverseInOrder)(func(v int) bool {
+		got = append(got, v)
+		return true
+	})
+	if want := []int{21, 11, 1, -13}; !cmp.Equal(got, want) {
+		t.Errorf("descending From(25) = %v, want %v", got, want)
+	}
+}
+
+func TestTreeRangeSearchPrunes(t *testing.T) {
+	const n = 10000
+	bst, _ := NewBSTFromSortedSeq(intRange(n))
+
+	ch := make(chan int)
+	go func() {
+		for v := 0; v < n; v++ {
+			ch <- v
+		}
+		close(ch)
+	}()
+	avl := &AVL[int]{}
+	avl.InsertSortedStream(ch)
+
+	trees := []struct {
+		name        string
+		rangeSearch func(lo, hi int, visit func(BinaryTree[int])) []int
+	}{
+		{
+			name:        "BST",
+			rangeSearch: bst.(*BST[int]).rangeSearch,
+		},
+		{
+			name:        "AVL",
+			rangeSearch: avl.rangeSearch,
+		},
+	}
+
+	for _, tt := range trees {
+		var visited int
+		got := tt.rangeSearch(5000, 5009, func(BinaryTree[int]) { visited++ })
+		if want := []int{5000, 5001, 5002, 5003, 5004, 5005, 5006, 5007, 5008, 5009}; !cmp.Equal(got, want) {
+			t.Errorf("%s: RangeSearch(5000, 5009) = %v, want %v", tt.name, got, want)
+		}
+
+		// A narrow range in a balanced tree should only need the values
+		// themselves plus the paths down to either end of the range.
+		if limit := 10 + 2*minHeight(n); visited > limit {
+			t.Errorf("%s: RangeSearch(5000, 5009) visited %d nodes, want <= %d",
+				tt.name, visited, limit)
+		}
+	}
+}
+
+// To run the Tree Insert Benchmarks use this command with
+// the desired number of run repetitions:
+//
+// go test . --test.benchmem --test.bench="BenchmarkTreeInsert" --count=n
+//
+// The benchmarks of a tree used from many goroutines at once are in
+// sync_tree_test.go.
+
+// BenchmarkTreeInsert is a harness to benchmark the Insert method on all
+// supported tree types.
+//
+// Each type is run with the values arriving in random, ascending and
+// descending order. Sorted input is the worst case for a plain BST, which
+// degenerates into a chain, while trees that rebalance as values are
+// inserted are unaffected.
+func BenchmarkTreeInsert(b *testing.B) {
+	inputs := []struct {
+		name string
+		vals []int
+	}{
+		{
+			name: "random",
+			vals: testIntVals,
+		},
+		{
+			name: "sorted",
+			vals: testIntValsSorted,
+		},
+		{
+			name: "reverse",
+			vals: testIntValsReverseSorted,
+		},
+	}
+
+	for _, example := range benchmarkTrees {
+		// Check if the user requested filtering on the benchmark.
+		if !treeTypeMatches(example.name, *treeTypeFilter) {
+			continue
+		}
+
+		for _, input := range inputs {
+			for _, n := range insertSteps {
+				// Skip any tests that are outside the limit.
+				if n > *treeSizeUpperLimit {
+					break
+				}
+				vals := input.vals[:n]
+
+				b.Run(fmt.Sprintf("%s-%s-%06d", example.name, input.name, n),
+					func(b *testing.B) {
+						tree := example.tree()
+						for i := 0; i < b.N; i++ {
+							tree.Insert(vals[i%n])
+						}
+					})
+			}
+		}
+	}
+}
+
+// BenchmarkTreeSearch is a harness to benchmark the Search method
+// on all supported tree types.
+func BenchmarkTreeSearch(b *testing.B) {
+	for _, example := range benchmarkTrees {
+		// Check if the user requested filtering on the benchmark.
+		if !treeTypeMatches(example.name, *treeTypeFilter) {
+			continue
+		}
+
+		for _, n := range insertSteps {
+			b.StopTimer()
+			// Skip any tests that are outside the limit.
+			if n > *treeSizeUpperLimit {
+				break
+			}
+			vals := testIntVals[:n]
+			// Build and fill the tree before starting the benchmark.
+			tree := example.tree()
+			for i := 0; i < n; i++ {
+				tree.Insert(vals[i])
+			}
+
+			b.StartTimer()
+			b.Run(fmt.Sprintf("%s-%06d", example.name, n),
+				func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						_ = tree.Search(vals[i%n])
+					}
+				})
+		}
+	}
+}
+
+// BenchmarkTreeDelete is a harness to benchmark the Delete method on all
+// supported tree types. Each tree is filled before the timer starts, and
+// filled again each time every value has been deleted.
+func BenchmarkTreeDelete(b *testing.B) {
+	for _, example := range benchmarkTrees {
+		// Check if the user requested filtering on the benchmark.
+		if !treeTypeMatches(example.name, *treeTypeFilter) {
+			continue
+		}
+
+		for _, n := range insertSteps {
+			// Skip any tests that are outside the limit.
+			if n > *treeSizeUpperLimit {
+				break
+			}
+			vals := testIntVals[:n]
+
+			b.Run(fmt.Sprintf("%s-%06d", example.name, n),
+				func(b *testing.B) {
+					var tree Tree[int]
+					for i := 0; i < b.N; i++ {
+						if i%n == 0 {
+							b.StopTimer()
+							tree = example.tree()
+							for _, v := range vals {
+								tree.Insert(v)
+							}
+							b.StartTimer()
+						}
+						tree.Delete(vals[i%n])
+					}
+				})
+		}
+	}
+}
+
+// BenchmarkTreeMixed is a harness to benchmark a mix of operations on all
+// supported tree types, as a tree sees when in use. Out of every ten
+// operations, eight are searches, one inserts a new value, and one deletes
+// the oldest value, so the tree stays the same size as the values in it
+// change over time.
+func BenchmarkTreeMixed(b *testing.B) {
+	for _, example := range benchmarkTrees {
+		// Check if the user requested filtering on the benchmark.
+		if !treeTypeMatches(example.name, *treeTypeFilter) {
+			continue
+		}
+
+		for _, n := range insertSteps {
+			// Skip any tests that are outside the limit.
+			if n > *treeSizeUpperLimit {
+				break
+			}
+
+			b.Run(fmt.Sprintf("%s-%06d", example.name, n),
+				func(b *testing.B) {
+					// The tree holds the window of n test values
+					// starting at oldest, which slides along as values
+					// are deleted and inserted.
+					tree := example.tree()
+					for _, v := range testIntVals[:n] {
+						tree.Insert(v)
+					}
+					oldest := 0
+					at := func(i int) int {
+						return testIntVals[i%len(testIntVals)]
+					}
+
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						switch i % 10 {
+						case 0:
+							tree.Insert(at(oldest + n))
+						case 5:
+							tree.Delete(at(oldest))
+							oldest++
+						default:
+							_ = tree.Search(at(oldest + i%n))
+						}
+					}
+				})
+		}
+	}
+}
+
+// BenchmarkCollectValues compares the different ways of collecting all the
+// values out of a tree in order.
+func BenchmarkCollectValues(b *testing.B) {
+	for _, n := range insertSteps {
+		// Skip any tests that are outside the limit.
+		if n > *treeSizeUpperLimit {
+			break
+		}
+		tree := &BST[int]{}
+		for _, v := range testIntVals[:n] {
+			tree.Insert(v)
+		}
+
+		b.Run(fmt.Sprintf("Traverse-%06d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var got []int
+				for v := range tree.Traverse(TraverseInOrder) {
+					got = append(got, v)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("ToSlice-%06d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = ToSlice[int](tree)
+			}
+		})
+
+		b.Run(fmt.Sprintf("AppendValues-%06d", n), func(b *testing.B) {
+			out := make([]int, 0, n)
+			for i := 0; i < b.N; i++ {
+				out = tree.AppendValues(out[:0], TraverseInOrder)
+			}
+		})
+
+		b.Run(fmt.Sprintf("InOrderInto-%06d", n), func(b *testing.B) {
+			out := make([]int, 0, n)
+			for i := 0; i < b.N; i++ {
+				out = out[:0]
+				InOrderInto(tree.Root(), &out)
+			}
+		})
+	}
+}
+
+// BenchmarkTraverseSlice compares collecting the values of a small tree with
+// TraverseSlice against draining the channel from Traverse, where starting
+// the goroutine and passing each value across costs more than the walk.
+func BenchmarkTraverseSlice(b *testing.B) {
+	const n = 100
+
+	tree := &BST[int]{}
+	for _, v := range testIntVals[:n] {
+		tree.Insert(v)
+	}
+
+	b.Run(fmt.Sprintf("Traverse-%06d", n), func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			got := make([]int, 0, n)
+			for v := range tree.Traverse(TraverseInOrder) {
+				got = append(got, v)
+			}
+		}
+	})
+
+	b.Run(fmt.Sprintf("TraverseSlice-%06d", n), func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = tree.TraverseSlice(TraverseInOrder)
+		}
+	})
+}
+
+// BenchmarkTraverseBuffered compares draining the channel from Traverse with
+// that from TraverseBuffered for a large tree, where the reader does almost
+// no work per value.
+func BenchmarkTraverseBuffered(b *testing.B) {
+	const n = 100000
+
+	tree := &BST[int]{}
+	for _, v := range testIntVals[:n] {
+		tree.Insert(v)
+	}
+
+	b.Run(fmt.Sprintf("Traverse-%06d", n), func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var sum int
+			for v := range tree.Traverse(TraverseInOrder) {
+				sum += v
+			}
+		}
+	})
+
+	for _, bufSize := range []int{16, 256, 4096} {
+		b.Run(fmt.Sprintf("TraverseBuffered-%d-%06d", bufSize, n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var sum int
+				for v := range tree.TraverseBuffered(TraverseInOrder, bufSize) {
+					sum += v
+				}
+			}
+		})
+	}
+}
+
+func TestTreeTraverseBuffered(t *testing.T) {
+	// bufferedTraverser is the subset of methods being tested here.
+	type bufferedTraverser interface {
+		Tree[int]
+		TraverseBuffered(tOrder TraverseOrder, bufSize int) <-chan int
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(bufferedTraverser)
+		for v := range tree.TraverseBuffered(TraverseInOrder, 4) {
+			t.Errorf("%s: TraverseBuffered() of an empty tree emitted %v", tt.name, v)
+		}
+
+		for _, v := range []int{21, 1, 42, -13, 11, 30, 84, 57} {
+			tree.Insert(v)
+		}
+		for _, order := range consistencyOrders {
+			var want []int
+			for v := range tree.Traverse(order) {
+				want = append(want, v)
+			}
+			for _, bufSize := range []int{-1, 0, 1, 3, 100} {
+				var got []int
+				for v := range tree.TraverseBuffered(order, bufSize) {
+					got = append(got, v)
+				}
+				if !cmp.Equal(got, want) {
+					t.Errorf("%s: TraverseBuffered(%v, %d) = %v, want %v", tt.name, order, bufSize, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestTreeTraverseSlice(t *testing.T) {
+	// sliceTraverser is the subset of methods being tested here.
+	type sliceTraverser interface {
+		Tree[int]
+		TraverseSlice(tOrder TraverseOrder) []int
+		Walk(tOrder TraverseOrder, visit func(int) bool) bool
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	for _, tt := range trees {
+		tree := tt.tree().(sliceTraverser)
+		for _, order := range consistencyOrders {
+			if got := tree.TraverseSlice(order); len(got) != 0 {
+				t.Errorf("%s: TraverseSlice(%v) of an empty tree = %v, want no values", tt.name, order, got)
+			}
+		}
+
+		for _, v := range []int{21, 1, 42, -13, 11, 30, 84, 57} {
+			tree.Insert(v)
+		}
+		for _, order := range consistencyOrders {
+			var want []int
+			for v := range tree.Traverse(order) {
+				want = append(want, v)
+			}
+			if got := tree.TraverseSlice(order); !cmp.Equal(got, want) {
+				t.Errorf("%s: TraverseSlice(%v) = %v, want %v", tt.name, order, got, want)
+			}
+		}
+
+		// Level order is not streamed by Traverse, so check it against Walk.
+		var want []int
+		tree.Walk(TraverseLevelOrder, func(v int) bool {
+			want = append(want, v)
+			return true
+		})
+		if got := tree.TraverseSlice(TraverseLevelOrder); len(got) != tree.Size() || !cmp.Equal(got, want) {
+			t.Errorf("%s: TraverseSlice(%v) = %v, want %v", tt.name, TraverseLevelOrder, got, want)
+		}
+	}
+}
+
+func TestTreeDeleteFunc(t *testing.T) {
+	// funcDeleter is the subset of methods being tested here.
+	type funcDeleter interface {
+		Tree[int]
+		DeleteFunc(pred func(int) bool) int
+	}
+
+	isEven := func(v int) bool { return v%2 == 0 }
+
+	// Removing every even value from an AVL tree of 1..100 leaves it valid.
+	avl := NewAVL[int]().(*AVL[int])
+	for v := 1; v <= 100; v++ {
+		avl.Insert(v)
+	}
+	if got := avl.DeleteFunc(isEven); got != 50 {
+		t.Errorf("AVL DeleteFunc(isEven) = %d, want 50", got)
+	}
+	if !IsValidAVL(avl.Root()) {
+		t.Errorf("AVL DeleteFunc(isEven) left an invalid AVL tree: %v", avl)
+	}
+	var odds []int
+	for v := 1; v <= 100; v += 2 {
+		odds = append(odds, v)
+	}
+	if got := ToSlice[int](avl); !cmp.Equal(got, odds) {
+		t.Errorf("AVL DeleteFunc(isEven) left %v, want %v", got, odds)
+	}
+	if got := avl.Size(); got != len(odds) {
+		t.Errorf("AVL DeleteFunc(isEven) Size() = %d, want %d", got, len(odds))
+	}
+
+	trees := []struct {
+		name string
+		tree func(opts ...treeOptionFunc) Tree[int]
+	}{
+		{
+			name: "BST",
+			tree: NewBST[int],
+		},
+		{
+			name: "AVL",
+			tree: NewAVL[int],
+		},
+		{
+			name: "RedBlack",
+			tree: NewRedBlack[int],
+		},
+		{
+			name: "Treap",
+			tree: NewTreap[int],
+		},
+	}
+
+	tests := []struct {
+		name        string
+		vals        []int
+		pred        func(int) bool
+		wantRemoved int
+		want        []int
+	}{
+		{
+			name: "empty",
+			pred: isEven,
+		},
+		{
+			name: "none match",
+			vals: []int{21, 1, 42, -13, 11, 30, 84, 57},
+			pred: func(v int) bool { return v > 100 },
+			want: []int{-13, 1, 11, 21, 30, 42, 57, 84},
+		},
+		{
+			name:        "some match",
+			vals:        []int{21, 1, 42, -13, 11, 30, 84, 57},
+			pred:        isEven,
+			wantRemoved: 3,
+			want:        []int{-13, 1, 11, 21, 57},
+		},
+		{
+			name:        "all match",
+			vals:        []int{21, 1, 42, -13, 11, 30, 84, 57},
+			pred:        func(int) bool { return true },
+			wantRemoved: 8,
+		},
+	}
+
+	for _, tr := range trees {
+		for _, test := range tests {
+			tree := tr.tree().(funcDeleter)
+			for _, v := range test.vals {
+				tree.Insert(v)
+			}
+			if got := tree.DeleteFunc(test.pred); got != test.wantRemoved {
+				t.Errorf("%s %s: DeleteFunc() = %d, want %d", tr.name, test.name, got, test.wantRemoved)
+			}
+			if got := ToSlice[int](tree); !cmp.Equal(got, test.want) {
+				t.Errorf("%s %s: DeleteFunc() left %v, want %v", tr.name, test.name, got, test.want)
+			}
+			if got := tree.Size(); got != len(test.want) {
+				t.Errorf("%s %s: DeleteFunc() Size() = %d, want %d", tr.name, test.name, got, len(test.want))
+			}
+			if got := tree.IsEmpty(); got != (len(test.want) == 0) {
+				t.Errorf("%s %s: DeleteFunc() IsEmpty() = %v, want %v", tr.name, test.name, got, len(test.want) == 0)
+			}
 		}
 	}
 }
@@ -0,0 +1,169 @@
+package tree
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// DefaultWeightBalanceDelta is the delta used by a WeightBalanced tree when
+// none is given.
+const DefaultWeightBalanceDelta = 3
+
+// minWeightBalanceDelta is the smallest usable delta. A node with a single
+// child has weights 2 and 1, so no smaller delta can be met by every tree.
+const minWeightBalanceDelta = 2
+
+// WeightBalanced is a binary search tree that keeps itself balanced by the
+// sizes of its subtrees rather than their heights. The weight of a subtree is
+// one more than the number of values in it, and at every node, neither child
+// may weigh more than delta times the other.
+//
+// When an insert or delete leaves a node out of balance, the whole subtree
+// below it is rebuilt in balanced shape, starting with the smallest such
+// subtree and working up. Rebuilds are rare but cost time in proportion to
+// the size of the subtree, unlike the constant work per level of an AVL
+// tree's rotations. A larger delta allows the tree to grow more lopsided
+// before a rebuild, making rebuilds rarer at the cost of longer searches.
+type WeightBalanced[T any] struct {
+	root *wbNode[T]
+
+	// size is the count of values in the tree, kept up to date by the
+	// Insert and Delete methods.
+	size int
+
+	// order defines how the values in the tree are arranged.
+	order ordering[T]
+
+	// delta is how many times heavier one child of a node may be than the
+	// other.
+	delta float64
+}
+
+// NewWeightBalanced returns an empty WeightBalanced tree ready to use, which
+// rebuilds any subtree where one child weighs more than delta times the
+// other. A delta of DefaultWeightBalanceDelta is a good balance between the
+// number of rebuilds and the height of the tree. Values of delta less than
+// 2 are raised to 2.
+//
+// Options can include Descending to order the values largest first, and
+// IgnoreDuplicates(false) to keep every copy of equal values inserted.
+func NewWeightBalanced[T constraints.Ordered](delta float64, opts ...treeOptionFunc) Tree[T] {
+	treeOpts := defaultOptions()
+	for _, opt := range opts {
+		opt(treeOpts)
+	}
+
+	return &WeightBalanced[T]{
+		order: newOrdering[T](treeOpts),
+		delta: max(delta, minWeightBalanceDelta),
+	}
+}
+
+// Root returns the root node of the tree.
+func (t *WeightBalanced[T]) Root() BinaryTree[T] {
+	return t.root
+}
+
+// Delta returns how many times heavier one child of a node may be than the
+// other before the subtree is rebuilt.
+func (t *WeightBalanced[T]) Delta() float64 {
+	return t.delta
+}
+
+// Insert inserts the value into the tree, rebuilding any subtrees left out of
+// balance. If the value is already in the tree, false is returned.
+func (t *WeightBalanced[T]) Insert(v T) bool {
+	return t.InsertErr(v) == nil
+}
+
+// InsertErr inserts the value into the tree like Insert. If the value is
+// already in the tree, and the tree does not keep duplicates, ErrDuplicate is
+// returned.
+func (t *WeightBalanced[T]) InsertErr(v T) error {
+	root, ok := t.root.insert(v, t.order.resolve(), t.delta)
+	t.root = root
+	if !ok {
+		return ErrDuplicate
+	}
+	t.size++
+	return nil
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+func (t *WeightBalanced[T]) InsertAll(vals ...T) int {
+	var n int
+	for _, v := range vals {
+		if t.Insert(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// Delete the requested node from the tree and reports if it was successful.
+// If the value is not in the tree, the tree is unchanged and false is returned.
+//
+// A node with two children takes the value of the next node in order, which
+// is removed in its place. Any subtrees left out of balance are rebuilt.
+func (t *WeightBalanced[T]) Delete(v T) bool {
+	return t.DeleteErr(v) == nil
+}
+
+// DeleteErr removes the requested node from the tree like Delete. If the tree
+// is empty ErrEmptyTree is returned, and if the value is not in the tree
+// ErrNotFound is returned, leaving the tree unchanged.
+func (t *WeightBalanced[T]) DeleteErr(v T) error {
+	if t.root == nil {
+		return ErrEmptyTree
+	}
+	root, ok := t.root.delete(v, t.order.resolve(), t.delta)
+	t.root = root
+	if !ok {
+		return ErrNotFound
+	}
+	t.size--
+	return nil
+}
+
+// Search reports if the given value is in the tree.
+func (t *WeightBalanced[T]) Search(v T) bool {
+	return t.root.search(v, t.order.resolve())
+}
+
+// Traverse traverses the tree in the specified order emitting the values to
+// the channel. Channel is closed once the final value is emitted.
+func (t *WeightBalanced[T]) Traverse(tOrder TraverseOrder) <-chan T {
+	return t.root.Traverse(tOrder)
+}
+
+// Walk calls visit with each value of the tree in the given order, stopping
+// as soon as visit returns false. It reports if every value was visited.
+func (t *WeightBalanced[T]) Walk(tOrder TraverseOrder, visit func(T) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	return walkBinaryTree[T](t.root, tOrder, visit)
+}
+
+// Height returns the height of the longest path in the tree from the
+// root node to the farthest leaf.
+func (t *WeightBalanced[T]) Height() int {
+	return t.root.Height()
+}
+
+// Size returns the number of values held in the tree.
+func (t *WeightBalanced[T]) Size() int {
+	return t.size
+}
+
+// IsEmpty reports if the tree holds no values.
+func (t *WeightBalanced[T]) IsEmpty() bool {
+	return t.root == nil
+}
+
+// String returns the values of the tree indented one level per depth, with
+// the root first and each child after its parent, for use when printing the
+// tree. Large trees are cut short after the first few dozen nodes.
+func (t *WeightBalanced[T]) String() string {
+	return treeString[T](t.root, t.size)
+}
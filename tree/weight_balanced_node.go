@@ -0,0 +1,311 @@
+package tree
+
+import (
+	"fmt"
+)
+
+// wbNode is the node in a WeightBalanced tree. Along with the value, each
+// node holds the number of nodes in its subtree, from which the weights of
+// its children are found.
+type wbNode[T any] struct {
+	value T
+
+	// size is the number of nodes in the subtree rooted here, including
+	// this one.
+	size int
+
+	left  *wbNode[T]
+	right *wbNode[T]
+}
+
+// wbNodeFromSorted builds a balanced subtree from the given sorted values by
+// recursively using the middle value as the root of each subtree.
+func wbNodeFromSorted[T any](vals []T) *wbNode[T] {
+	if len(vals) == 0 {
+		return nil
+	}
+
+	mid := len(vals) / 2
+	return &wbNode[T]{
+		value: vals[mid],
+		size:  len(vals),
+		left:  wbNodeFromSorted(vals[:mid]),
+		right: wbNodeFromSorted(vals[mid+1:]),
+	}
+}
+
+// HasLeft reports if this node has a Left child.
+func (t *wbNode[T]) HasLeft() bool {
+	return t.left != nil
+}
+
+// HasRight reports if this node has a Right child.
+func (t *wbNode[T]) HasRight() bool {
+	return t.right != nil
+}
+
+// Left returns this nodes Left child.
+func (t *wbNode[T]) Left() BinaryTree[T] {
+	return t.left
+}
+
+// Right returns this nodes Right child.
+func (t *wbNode[T]) Right() BinaryTree[T] {
+	return t.right
+}
+
+// Parent always returns nil, as these nodes do not keep track of their
+// parent.
+func (t *wbNode[T]) Parent() BinaryTree[T] {
+	return nil
+}
+
+// Value returns this nodes Value.
+func (t *wbNode[T]) Value() T {
+	return t.value
+}
+
+// Metadata returns a string of metadata about this node. For a WeightBalanced
+// tree, this is the size of the subtree rooted at the node.
+func (t *wbNode[T]) Metadata() string {
+	return fmt.Sprintf("S:%d", t.size)
+}
+
+// weight returns the weight of the subtree rooted at this node, one more than
+// its size, so that a missing child weighs one.
+func (t *wbNode[T]) weight() float64 {
+	if t == nil {
+		return 1
+	}
+	return float64(t.size + 1)
+}
+
+// balanced reports if neither child of this node weighs more than delta times
+// the other.
+func (t *wbNode[T]) balanced(delta float64) bool {
+	lw, rw := t.left.weight(), t.right.weight()
+	return lw <= delta*rw && rw <= delta*lw
+}
+
+// rebalance returns this subtree, rebuilt in balanced shape if it is out of
+// balance.
+func (t *wbNode[T]) rebalance(delta float64) *wbNode[T] {
+	if t.balanced(delta) {
+		return t
+	}
+
+	nodes := make([]*wbNode[T], 0, t.size)
+	t.appendNodes(&nodes)
+	return wbNodeFromNodes(nodes)
+}
+
+// appendNodes appends the nodes of this subtree onto nodes in order.
+func (t *wbNode[T]) appendNodes(nodes *[]*wbNode[T]) {
+	if t == nil {
+		return
+	}
+	t.left.appendNodes(nodes)
+	*nodes = append(*nodes, t)
+	t.right.appendNodes(nodes)
+}
+
+// wbNodeFromNodes relinks the given nodes, which must be in order, into a
+// balanced subtree by recursively using the middle node as the root of each
+// subtree, and returns its root.
+func wbNodeFromNodes[T any](nodes []*wbNode[T]) *wbNode[T] {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	mid := len(nodes) / 2
+	node := nodes[mid]
+	node.left = wbNodeFromNodes(nodes[:mid])
+	node.right = wbNodeFromNodes(nodes[mid+1:])
+	node.size = len(nodes)
+	return node
+}
+
+// Insert inserts the value below this node and reports if the operation was
+// successful, using the default delta.
+//
+// This node is held by the caller, so it is never rebuilt out of its place,
+// even if it is left out of balance. Callers should prefer the Insert of the
+// WeightBalanced tree which keeps track of the root.
+func (t *wbNode[T]) Insert(v T) bool {
+	o := ordering[T]{}.resolve()
+	var ok bool
+	switch c := o.compare(v, t.value); {
+	case c < 0:
+		t.left, ok = t.left.insert(v, o, DefaultWeightBalanceDelta)
+	case c > 0:
+		t.right, ok = t.right.insert(v, o, DefaultWeightBalanceDelta)
+	}
+	if ok {
+		t.size++
+	}
+	return ok
+}
+
+// insert is the worker for Insert, placing v according to the given ordering
+// of the tree. Each subtree on the way back up that is left out of balance is
+// rebuilt, so this returns the new root of this subtree, and reports if v was
+// added.
+func (t *wbNode[T]) insert(v T, o ordering[T], delta float64) (*wbNode[T], bool) {
+	if t == nil {
+		return &wbNode[T]{value: v, size: 1}, true
+	}
+
+	var ok bool
+	switch c := o.compare(v, t.value); {
+	case c == 0 && !o.duplicates:
+		return t, false
+	case c < 0:
+		t.left, ok = t.left.insert(v, o, delta)
+	default:
+		t.right, ok = t.right.insert(v, o, delta)
+	}
+	if !ok {
+		return t, false
+	}
+	t.size++
+	return t.rebalance(delta), true
+}
+
+// InsertAll inserts each of the values into the tree, returning the count of
+// values that were added.
+func (t *wbNode[T]) InsertAll(vals ...T) int {
+	var n int
+	for _, v := range vals {
+		if t.Insert(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// Delete removes the value from below this node and reports if it was
+// successful, using the default delta. If the value is not in the tree, the
+// tree is unchanged and false is returned.
+//
+// This node is held by the caller, so it can not remove its own value.
+// Callers should prefer the Delete of the WeightBalanced tree which keeps
+// track of the root.
+func (t *wbNode[T]) Delete(v T) bool {
+	o := ordering[T]{}.resolve()
+	var ok bool
+	switch c := o.compare(v, t.value); {
+	case c < 0:
+		t.left, ok = t.left.delete(v, o, DefaultWeightBalanceDelta)
+	case c > 0:
+		t.right, ok = t.right.delete(v, o, DefaultWeightBalanceDelta)
+	}
+	if ok {
+		t.size--
+	}
+	return ok
+}
+
+// delete is the worker for Delete, removing v according to the given ordering
+// of the tree. A node with two children takes the value of the first node of
+// its right subtree, which is removed instead. Like insert, subtrees left out
+// of balance are rebuilt on the way back up, so this returns the new root of
+// this subtree, and reports if v was removed.
+func (t *wbNode[T]) delete(v T, o ordering[T], delta float64) (*wbNode[T], bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	var ok bool
+	switch c := o.compare(v, t.value); {
+	case c < 0:
+		t.left, ok = t.left.delete(v, o, delta)
+	case c > 0:
+		t.right, ok = t.right.delete(v, o, delta)
+	case t.left == nil:
+		return t.right, true
+	case t.right == nil:
+		return t.left, true
+	default:
+		var next *wbNode[T]
+		t.right, next = t.right.removeMin(delta)
+		t.value = next.value
+		ok = true
+	}
+	if !ok {
+		return t, false
+	}
+	t.size--
+	return t.rebalance(delta), true
+}
+
+// removeMin removes the first node in order from this subtree, rebuilding
+// any subtrees left out of balance. It returns the new root of this subtree
+// and the removed node.
+func (t *wbNode[T]) removeMin(delta float64) (*wbNode[T], *wbNode[T]) {
+	if t.left == nil {
+		return t.right, t
+	}
+
+	var first *wbNode[T]
+	t.left, first = t.left.removeMin(delta)
+	t.size--
+	return t.rebalance(delta), first
+}
+
+// Search reports if the given value is in the tree.
+func (t *wbNode[T]) Search(v T) bool {
+	return t.search(v, ordering[T]{}.resolve())
+}
+
+// search is the worker for Search, following the given ordering of the tree.
+func (t *wbNode[T]) search(v T, o ordering[T]) bool {
+	for n := t; n != nil; {
+		c := o.compare(v, n.value)
+		if c == 0 {
+			return true
+		}
+		if c < 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return false
+}
+
+// Traverse traverse the tree in the specified order emitting the values to
+// the channel. Channel is closed once the final value is emitted.
+func (t *wbNode[T]) Traverse(tOrder TraverseOrder) <-chan T {
+	ch := make(chan T)
+	go func() {
+		if !t.IsEmpty() {
+			traverseBinaryTree(t, tOrder, ch)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Height returns the height of the longest path in the tree from the
+// root node to the farthest leaf.
+func (t *wbNode[T]) Height() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + max(t.left.Height(), t.right.Height())
+}
+
+// Size returns the number of values in the tree rooted at this node.
+func (t *wbNode[T]) Size() int {
+	if t == nil {
+		return 0
+	}
+	return t.size
+}
+
+// IsEmpty reports if the tree rooted at this node holds no values, which is
+// only the case for a nil node.
+func (t *wbNode[T]) IsEmpty() bool {
+	return t == nil
+}
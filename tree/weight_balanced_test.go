@@ -0,0 +1,214 @@
+package tree
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkWeightBalanced returns the number of nodes below n, and reports if
+// every node below n has a correct size, is in order, and has neither child
+// weighing more than delta times the other.
+func checkWeightBalanced(n *wbNode[int], delta float64) (int, bool) {
+	if n == nil {
+		return 0, true
+	}
+	if (n.left != nil && n.left.value >= n.value) || (n.right != nil && n.right.value <= n.value) {
+		return 0, false
+	}
+
+	ls, lok := checkWeightBalanced(n.left, delta)
+	rs, rok := checkWeightBalanced(n.right, delta)
+	if !lok || !rok || n.size != ls+rs+1 {
+		return 0, false
+	}
+	lw, rw := float64(ls+1), float64(rs+1)
+	if lw > delta*rw || rw > delta*lw {
+		return 0, false
+	}
+	return n.size, true
+}
+
+func TestWeightBalancedRandomWorkload(t *testing.T) {
+	for _, delta := range []float64{2, DefaultWeightBalanceDelta, 4.5} {
+		r := rand.New(rand.NewSource(42))
+		tree := NewWeightBalanced[int](delta).(*WeightBalanced[int])
+		want := map[int]bool{}
+
+		for step := 0; step < 3000; step++ {
+			v := r.Intn(500)
+			// Lean towards inserts so the tree grows as it churns.
+			if r.Intn(3) == 0 {
+				if got := tree.Delete(v); got != want[v] {
+					t.Fatalf("delta %v: Delete(%d) = %v, want %v", delta, v, got, want[v])
+				}
+				delete(want, v)
+			} else {
+				if got := tree.Insert(v); got == want[v] {
+					t.Fatalf("delta %v: Insert(%d) = %v, want %v", delta, v, got, !want[v])
+				}
+				want[v] = true
+			}
+
+			if _, ok := checkWeightBalanced(tree.root, delta); !ok {
+				t.Fatalf("delta %v: after step %d, the weight condition does not hold at every node", delta, step)
+			}
+			if got := tree.Size(); got != len(want) {
+				t.Fatalf("delta %v: after step %d, Size() = %d, want %d", delta, step, got, len(want))
+			}
+		}
+
+		var wantVals []int
+		for v := range want {
+			wantVals = append(wantVals, v)
+		}
+		slices.Sort(wantVals)
+		if got := ToSlice[int](tree); !slices.Equal(got, wantVals) {
+			t.Errorf("delta %v: values = %v, want %v", delta, got, wantVals)
+		}
+		for _, v := range wantVals {
+			if !tree.Search(v) {
+				t.Errorf("delta %v: Search(%d) = false, want true", delta, v)
+			}
+		}
+
+		// Delete everything that is left.
+		for _, v := range wantVals {
+			if !tree.Delete(v) {
+				t.Errorf("delta %v: Delete(%d) = false, want true", delta, v)
+			}
+			if _, ok := checkWeightBalanced(tree.root, delta); !ok {
+				t.Fatalf("delta %v: after deleting %d, the weight condition does not hold at every node", delta, v)
+			}
+		}
+		if !tree.IsEmpty() || tree.Size() != 0 {
+			t.Errorf("delta %v: tree not empty after deleting every value", delta)
+		}
+	}
+}
+
+func TestWeightBalancedSorted(t *testing.T) {
+	const n = 2000
+
+	// Sorted inserts keep the height logarithmic, growing with delta.
+	for _, delta := range []float64{2, DefaultWeightBalanceDelta, 10} {
+		tree := NewWeightBalanced[int](delta).(*WeightBalanced[int])
+		for v := 0; v < n; v++ {
+			tree.Insert(v)
+		}
+		if _, ok := checkWeightBalanced(tree.root, delta); !ok {
+			t.Errorf("delta %v: the weight condition does not hold at every node", delta)
+		}
+
+		// The heavier child holds at most delta/(delta+1) of the weight,
+		// which bounds the number of levels.
+		limit := int(math.Ceil(math.Log(n+1)/math.Log((delta+1)/delta))) + 1
+		if got := tree.Height(); got > limit {
+			t.Errorf("delta %v: Height() after %d sorted inserts = %d, want at most %d", delta, n, got, limit)
+		}
+	}
+}
+
+func TestNewWeightBalanced(t *testing.T) {
+	tests := []struct {
+		delta float64
+		want  float64
+	}{
+		{delta: 3, want: 3},
+		{delta: 2.5, want: 2.5},
+		{delta: 2, want: 2},
+		{delta: 1, want: 2},
+		{delta: 0, want: 2},
+		{delta: -4, want: 2},
+	}
+	for _, test := range tests {
+		tree := NewWeightBalanced[int](test.delta).(*WeightBalanced[int])
+		if got := tree.Delta(); got != test.want {
+			t.Errorf("NewWeightBalanced(%v).Delta() = %v, want %v", test.delta, got, test.want)
+		}
+	}
+
+	// Options are applied.
+	tree := NewWeightBalanced[int](DefaultWeightBalanceDelta, Descending(), IgnoreDuplicates(false))
+	tree.InsertAll(3, 1, 4, 1, 5, 9, 2, 6)
+	if got, want := ToSlice(tree), []int{9, 6, 5, 4, 3, 2, 1, 1}; !slices.Equal(got, want) {
+		t.Errorf("Descending multiset values = %v, want %v", got, want)
+	}
+}
+
+func TestWeightBalancedTreeFunctions(t *testing.T) {
+	vals := []int{5, 3, 8, 1, 4, 7, 9, 2, 6, 10}
+
+	asc := NewWeightBalanced[int](2.5)
+	asc.InsertAll(vals...)
+	desc := NewWeightBalanced[int](2.5, Descending())
+	desc.InsertAll(vals...)
+
+	if got := Rebalance(desc); got != desc {
+		t.Errorf("Rebalance(WeightBalanced) did not return the same tree")
+	}
+
+	tests := []struct {
+		name string
+		tree Tree[int]
+		want []int
+	}{
+		{
+			name: "Filter",
+			tree: Filter(asc, func(v int) bool { return v%2 == 0 }),
+			want: []int{2, 4, 6, 8, 10},
+		},
+		{
+			name: "Map",
+			tree: Map(asc, func(v int) int { return v * 10 }),
+			want: []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
+		},
+		{
+			name: "Trim",
+			tree: Trim(asc, 2, 9),
+			want: []int{2, 3, 4, 5, 6, 7, 8, 9},
+		},
+		{
+			name: "descending Filter",
+			tree: Filter(desc, func(int) bool { return true }),
+			want: []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+		},
+		{
+			name: "descending Map",
+			tree: Map(desc, func(v int) int { return -v }),
+			want: []int{-1, -2, -3, -4, -5, -6, -7, -8, -9, -10},
+		},
+		{
+			name: "descending Trim",
+			tree: Trim(desc, 2, 9),
+			want: []int{9, 8, 7, 6, 5, 4, 3, 2},
+		},
+	}
+	for _, test := range tests {
+		wb, ok := test.tree.(*WeightBalanced[int])
+		if !ok {
+			t.Errorf("%s: returned a %T, want a *WeightBalanced[int]", test.name, test.tree)
+			continue
+		}
+		if got := ToSlice[int](wb); !slices.Equal(got, test.want) {
+			t.Errorf("%s: values = %v, want %v", test.name, got, test.want)
+		}
+		if got := wb.Size(); got != len(test.want) {
+			t.Errorf("%s: Size() = %d, want %d", test.name, got, len(test.want))
+		}
+		if got := wb.Delta(); got != 2.5 {
+			t.Errorf("%s: Delta() = %v, want 2.5", test.name, got)
+		}
+		for _, v := range test.want {
+			if !wb.Search(v) {
+				t.Errorf("%s: Search(%d) = false, want true", test.name, v)
+			}
+		}
+		if !wb.order.descending {
+			if _, ok := checkWeightBalanced(wb.root, wb.delta); !ok {
+				t.Errorf("%s: the weight condition does not hold at every node", test.name)
+			}
+		}
+	}
+}